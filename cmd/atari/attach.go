@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/daemon"
+	"github.com/npratt/atari/internal/events"
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/tui"
+)
+
+// attachStatsInterval is how often an attached TUI polls the daemon status.
+const attachStatsInterval = time.Second
+
+// newAttachCmd creates the attach command, which runs the TUI against a
+// daemon started with --daemon. Quitting detaches and leaves the drain running.
+func newAttachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "attach",
+		Short: "Open the TUI for a running background daemon",
+		Long: `Connects the terminal UI to a daemon started with 'atari start --daemon'.
+Header stats come from the daemon's status, events are streamed over the
+control socket, and p/r/R/S pause, resume, retry and stop the drain.
+Press q to detach; the daemon keeps working.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getDaemonClient()
+			if err != nil {
+				return err
+			}
+
+			stats := daemon.NewRemoteStats(client)
+			if err := stats.Refresh(); err != nil {
+				return fmt.Errorf("attach: %w", err)
+			}
+
+			cfg, err := config.LoadConfig(viper.GetViper())
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			projectRoot := daemon.FindProjectRoot("")
+			cfg.Paths, err = daemon.ResolvePaths(cfg.Paths, projectRoot)
+			if err != nil {
+				return fmt.Errorf("resolve paths: %w", err)
+			}
+			beads, err := newBeadClient(cfg, cmdexec.NewExecRunner(), projectRoot)
+			if err != nil {
+				return err
+			}
+
+			// Keep log output off the TUI
+			logLevel := slog.LevelInfo
+			if viper.GetBool(FlagVerbose) {
+				logLevel = slog.LevelDebug
+			}
+			tuiLog, err := SetupTUILogger(filepath.Dir(cfg.Paths.Log), logLevel, cfg.LogRotation)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = tuiLog.Close() }()
+			slog.SetDefault(tuiLog.Logger)
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			go stats.Run(ctx, attachStatsInterval)
+
+			tuiEvents := make(chan events.Event, 5000)
+			streamDone := make(chan error, 1)
+			go func() {
+				defer close(tuiEvents)
+				streamDone <- streamAttachEvents(ctx, client, stats.Status(), tuiEvents)
+			}()
+
+			// Callbacks report daemon errors in the debug log; the header
+			// reflects the daemon's actual state on the next poll.
+			call := func(name string, fn func() error) func() {
+				return func() {
+					if err := fn(); err != nil {
+						slog.Warn("daemon request failed", "request", name, "error", err)
+					}
+				}
+			}
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				workDir = ""
+			}
+
+			tuiApp := tui.New(tuiEvents,
+				tui.WithDetach(),
+				tui.WithOnPause(call("pause", client.Pause)),
+				tui.WithOnResume(call("resume", client.Resume)),
+				tui.WithOnRetry(call("retry", func() error { return client.Retry("") })),
+				tui.WithOnStop(call("stop", func() error { return client.Stop(false) })),
+				tui.WithStatsGetter(stats),
+				tui.WithGraphFetcher(tui.NewBDFetcher(beads)),
+				tui.WithEpicID(cfg.WorkQueue.Epic),
+				tui.WithWorkingDirectory(workDir),
+			)
+
+			tuiErr := tuiApp.Run()
+			cancel()
+			if err := <-streamDone; err != nil && tuiErr == nil {
+				return fmt.Errorf("event stream: %w", err)
+			}
+			return tuiErr
+		},
+	}
+}
+
+// streamAttachEvents feeds an attached TUI. It first reports the daemon's
+// current drain state, then streams events, replaying the log from shortly
+// before the oldest running bead started so its progress is shown.
+func streamAttachEvents(ctx context.Context, client *daemon.Client, status *daemon.StatusResponse, out chan<- events.Event) error {
+	out <- &events.DrainStateChangedEvent{
+		BaseEvent: events.NewInternalEvent(events.EventDrainStateChanged),
+		To:        status.Status,
+	}
+
+	var params daemon.SubscribeParams
+	for _, w := range status.Workers {
+		if w.BeadID == "" || w.StartedAt.IsZero() {
+			continue
+		}
+		since := w.StartedAt.Add(-time.Second)
+		if params.Since.IsZero() || since.Before(params.Since) {
+			params.Since = since
+		}
+	}
+
+	return client.Subscribe(ctx, params, func(ev events.Event) {
+		select {
+		case out <- ev:
+		case <-ctx.Done():
+		}
+	})
+}
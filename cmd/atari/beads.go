@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/daemon"
+	"github.com/npratt/atari/internal/events"
+	cmdexec "github.com/npratt/atari/internal/exec"
+)
+
+// newBeadClient returns the br client for the configured bead source. Reads
+// from the JSONL export still write through br.
+func newBeadClient(cfg *config.Config, cmdRunner cmdexec.CommandRunner, projectRoot string) (brclient.Client, error) {
+	cli := brclient.NewCLIClient(cmdRunner)
+	switch cfg.Beads.Source {
+	case "", brclient.SourceBR:
+		return cli, nil
+	case brclient.SourceJSONL:
+		path := cfg.Beads.JSONL
+		if path == "" {
+			return nil, fmt.Errorf("beads source %q requires beads.jsonl", brclient.SourceJSONL)
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		return brclient.NewJSONLClient(path, cli), nil
+	default:
+		return nil, fmt.Errorf("unknown beads source %q (want %q or %q)", cfg.Beads.Source, brclient.SourceBR, brclient.SourceJSONL)
+	}
+}
+
+// cacheBeadClient wraps client in a CachingClient when beads.cache_ttl is
+// set. Bead changes and session ends seen on router clear the cache until
+// ctx is done.
+func cacheBeadClient(ctx context.Context, cfg *config.Config, client brclient.Client, router *events.Router) brclient.Client {
+	if cfg.Beads.CacheTTL <= 0 {
+		return client
+	}
+	cache := brclient.NewCachingClient(client, cfg.Beads.CacheTTL)
+	go cache.Watch(ctx, router.Subscribe())
+	return cache
+}
+
+// describeBeadCache summarizes the bead cache counters for atari status.
+func describeBeadCache(c *daemon.BeadCacheStatus) string {
+	desc := fmt.Sprintf("%d hits, %d misses", c.Hits, c.Misses)
+	if c.Coalesced > 0 {
+		desc += fmt.Sprintf(", %d coalesced", c.Coalesced)
+	}
+	if reads := c.Hits + c.Misses + c.Coalesced; reads > 0 {
+		saved := float64(c.Hits+c.Coalesced) / float64(reads) * 100
+		desc += fmt.Sprintf(" (%.0f%% of reads without br)", saved)
+	}
+	return desc
+}
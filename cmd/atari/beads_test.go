@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/daemon"
+	cmdexec "github.com/npratt/atari/internal/exec"
+)
+
+func TestNewBeadClient(t *testing.T) {
+	tests := []struct {
+		source  string
+		jsonl   string
+		want    string
+		wantErr bool
+	}{
+		{source: "", want: "cli"},
+		{source: brclient.SourceBR, want: "cli"},
+		{source: brclient.SourceJSONL, jsonl: ".beads/issues.jsonl", want: "jsonl"},
+		{source: brclient.SourceJSONL, wantErr: true},
+		{source: "sqlite", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Beads.Source, cfg.Beads.JSONL = tt.source, tt.jsonl
+
+			client, err := newBeadClient(cfg, cmdexec.NewExecRunner(), t.TempDir())
+			if tt.wantErr {
+				if err == nil {
+					t.Error("newBeadClient() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newBeadClient() error: %v", err)
+			}
+			var got string
+			switch client.(type) {
+			case *brclient.CLIClient:
+				got = "cli"
+			case *brclient.JSONLClient:
+				got = "jsonl"
+			}
+			if got != tt.want {
+				t.Errorf("newBeadClient() = %T, want %s", client, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeBeadCache(t *testing.T) {
+	tests := []struct {
+		stats daemon.BeadCacheStatus
+		want  string
+	}{
+		{daemon.BeadCacheStatus{}, "0 hits, 0 misses"},
+		{daemon.BeadCacheStatus{Hits: 9, Misses: 3}, "9 hits, 3 misses (75% of reads without br)"},
+		{daemon.BeadCacheStatus{Hits: 6, Misses: 2, Coalesced: 2}, "6 hits, 2 misses, 2 coalesced (80% of reads without br)"},
+	}
+	for _, tt := range tests {
+		if got := describeBeadCache(&tt.stats); got != tt.want {
+			t.Errorf("describeBeadCache(%+v) = %q, want %q", tt.stats, got, tt.want)
+		}
+	}
+}
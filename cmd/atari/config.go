@@ -20,6 +20,13 @@ const (
 	FlagEagerSwitch       = "eager-switch"
 	FlagPrompt            = "prompt"
 	FlagBDActivityEnabled = "bd-activity-enabled"
+	FlagWorkers           = "workers"
+
+	// Start command drain-until flags
+	FlagMaxBeads        = "max-beads"
+	FlagUntilEpicClosed = "until-epic-closed"
+	FlagMaxCost         = "max-cost"
+	FlagMaxDuration     = "max-duration"
 
 	// Start command daemon mode flags
 	FlagDaemon = "daemon"
@@ -30,10 +37,25 @@ const (
 	// Events command flags
 	FlagFollow = "follow"
 	FlagCount  = "count"
+	FlagType   = "type"
+	FlagBead   = "bead"
+	FlagSince  = "since"
 
 	// Output format flags
 	FlagJSON = "json"
 
+	// Transcript command flags
+	FlagAttempt = "attempt"
+	FlagRaw     = "raw"
+
+	// Replay command flags
+	FlagSpeed  = "speed"
+	FlagMaxGap = "max-gap"
+	FlagPaused = "paused"
+
+	// Plan command flags
+	FlagLimit = "limit"
+
 	// Init command flags
 	FlagDryRun  = "dry-run"
 	FlagMinimal = "minimal"
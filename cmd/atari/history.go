@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/daemon"
+	"github.com/npratt/atari/internal/history"
+)
+
+// defaultReportWindow is the window for atari report without --since.
+const defaultReportWindow = "7d"
+
+// newHistoryCmd creates the history command, which lists every recorded
+// attempt at a bead.
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history <bead-id>",
+		Short: "List recorded attempts at a bead",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			records, err := loadHistory()
+			if err != nil {
+				return err
+			}
+			records = history.ForBead(records, args[0])
+
+			if asJSON, _ := cmd.Flags().GetBool(FlagJSON); asJSON {
+				return printJSON(records)
+			}
+			if len(records) == 0 {
+				fmt.Printf("No recorded attempts for %s\n", args[0])
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ATTEMPT\tSTARTED\tDURATION\tTURNS\tCOST\tMODEL\tOUTCOME\tSESSION\tERROR")
+			for _, rec := range records {
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\t$%.2f\t%s\t%s\t%s\t%s\n",
+					rec.Attempt,
+					rec.StartedAt.Local().Format("2006-01-02 15:04"),
+					rec.Duration().Round(time.Second),
+					rec.Turns,
+					rec.CostUSD,
+					orDash(rec.Model),
+					rec.Outcome,
+					orDash(rec.SessionID),
+					firstLine(rec.Error),
+				)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Bool(FlagJSON, false, "Output attempts as JSON")
+	return cmd
+}
+
+// newReportCmd creates the report command, which summarises recent attempts.
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarise throughput, cost and failures",
+		Long: `Summarises the attempts recorded in the run history: beads completed per
+day, cost per epic, the most common failure reasons and the mean time spent
+on each completed bead.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sinceFlag, _ := cmd.Flags().GetString(FlagSince)
+			window, err := parseWindow(sinceFlag)
+			if err != nil {
+				return err
+			}
+
+			records, err := loadHistory()
+			if err != nil {
+				return err
+			}
+			until := time.Now()
+			report := history.Summarize(records, until.Add(-window), until)
+
+			if asJSON, _ := cmd.Flags().GetBool(FlagJSON); asJSON {
+				return printJSON(report)
+			}
+			printReport(report)
+			return nil
+		},
+	}
+	cmd.Flags().String(FlagSince, defaultReportWindow, "Report on attempts started within this window (e.g. 7d, 12h)")
+	cmd.Flags().Bool(FlagJSON, false, "Output the report as JSON")
+	return cmd
+}
+
+// loadHistory reads the run history for the current project.
+func loadHistory() ([]history.Record, error) {
+	paths, err := projectPaths()
+	if err != nil {
+		return nil, err
+	}
+	return history.Load(paths.History)
+}
+
+// projectPaths returns the configured paths resolved against the project root.
+func projectPaths() (config.PathsConfig, error) {
+	cfg, err := config.LoadConfig(viper.GetViper())
+	if err != nil {
+		return config.PathsConfig{}, fmt.Errorf("load config: %w", err)
+	}
+	paths, err := daemon.ResolvePaths(cfg.Paths, daemon.FindProjectRoot(""))
+	if err != nil {
+		return config.PathsConfig{}, fmt.Errorf("resolve paths: %w", err)
+	}
+	return paths, nil
+}
+
+// parseWindow parses a duration that may also be given in days, e.g. "7d".
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --%s %q: use a positive duration such as 7d or 12h", FlagSince, s)
+	}
+	return d, nil
+}
+
+// printReport writes a human-readable report.
+func printReport(r history.Report) {
+	fmt.Printf("Since %s (%s)\n", r.Since.Local().Format("2006-01-02 15:04"), r.Until.Sub(r.Since).Round(time.Minute))
+	if r.Attempts == 0 {
+		fmt.Println("No attempts recorded.")
+		return
+	}
+
+	fmt.Printf("Throughput:\n")
+	fmt.Printf("  Beads completed: %d of %d attempted (%.1f per day)\n", r.CompletedBeads, r.Beads, r.BeadsPerDay)
+	fmt.Printf("  Attempts: %d (%d completed, %d failed, %d abandoned)\n", r.Attempts, r.Completed, r.Failed, r.Abandoned)
+	fmt.Printf("  Mean time per bead: %s\n", r.MeanTimePerBead.Round(time.Second))
+	fmt.Printf("  Turns: %d\n", r.TotalTurns)
+	fmt.Printf("  Cost: $%.2f\n", r.TotalCostUSD)
+
+	fmt.Printf("Cost per epic:\n")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, epic := range r.Epics {
+		id := epic.EpicID
+		if id == "" {
+			id = "(no epic)"
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t$%.2f\t%d attempts\t%d completed\n", id, epic.CostUSD, epic.Attempts, epic.CompletedBeads)
+	}
+	_ = w.Flush()
+
+	if len(r.FailureReasons) > 0 {
+		fmt.Printf("Failure reasons:\n")
+		for _, reason := range r.FailureReasons {
+			fmt.Printf("  %4d  %s\n", reason.Count, reason.Reason)
+		}
+	}
+}
+
+// printJSON writes v as indented JSON.
+func printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal output: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// orDash returns s, or "-" when it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -18,18 +19,22 @@ import (
 	"github.com/spf13/viper"
 	"golang.org/x/term"
 
-	"github.com/npratt/atari/internal/brclient"
 	"github.com/npratt/atari/internal/config"
 	"github.com/npratt/atari/internal/controller"
 	"github.com/npratt/atari/internal/daemon"
 	"github.com/npratt/atari/internal/events"
 	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/history"
 	initcmd "github.com/npratt/atari/internal/init"
+	"github.com/npratt/atari/internal/notify"
 	"github.com/npratt/atari/internal/observer"
 	"github.com/npratt/atari/internal/runner"
+	"github.com/npratt/atari/internal/schedule"
+	"github.com/npratt/atari/internal/session"
 	"github.com/npratt/atari/internal/shutdown"
 	"github.com/npratt/atari/internal/tui"
 	"github.com/npratt/atari/internal/workqueue"
+	"github.com/npratt/atari/internal/worktree"
 )
 
 var version = "dev"
@@ -101,8 +106,38 @@ func waitForFile(ctx context.Context, path string) (*os.File, error) {
 	}
 }
 
+// followEvents streams live events from the daemon, falling back to tailing
+// the log file when no daemon is serving subscriptions.
+func followEvents(ctx context.Context, logPath string, params daemon.SubscribeParams) error {
+	client, err := getDaemonClient()
+	if err == nil {
+		streamed := false
+		err = client.Subscribe(ctx, params, func(ev events.Event) {
+			streamed = true
+			fmt.Println(events.FormatWithTimestamp(ev))
+		})
+		if err == nil || streamed {
+			return err
+		}
+	}
+
+	filter := daemon.NewEventFilter(params)
+	if !params.Since.IsZero() {
+		replay, err := observer.NewLogReader(logPath).ReadAfterTimestamp(params.Since)
+		if err != nil && !errors.Is(err, observer.ErrFileNotFound) {
+			return fmt.Errorf("read log: %w", err)
+		}
+		for _, ev := range replay {
+			if filter.Match(ev) {
+				fmt.Println(events.FormatWithTimestamp(ev))
+			}
+		}
+	}
+	return tailFollow(ctx, logPath, filter)
+}
+
 // tailFollow follows the log file and prints new lines as they appear.
-func tailFollow(ctx context.Context, path string) error {
+func tailFollow(ctx context.Context, path string, filter *daemon.EventFilter) error {
 	file, err := os.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -139,7 +174,7 @@ func tailFollow(ctx context.Context, path string) error {
 				}
 				return fmt.Errorf("read log: %w", err)
 			}
-			printEventLine(strings.TrimSuffix(line, "\n"))
+			printFilteredLine(strings.TrimSuffix(line, "\n"), filter)
 		}
 	}
 }
@@ -164,6 +199,16 @@ func printEventLine(line string) {
 	fmt.Println(events.FormatWithTimestamp(ev))
 }
 
+// printFilteredLine prints an event line if it passes the filter. Lines that
+// are not known events are always printed.
+func printFilteredLine(line string, filter *daemon.EventFilter) {
+	ev, err := events.ParseEvent([]byte(line))
+	if err == nil && ev != nil && !filter.Match(ev) {
+		return
+	}
+	printEventLine(line)
+}
+
 // normalizeHistoryForRecovery converts HistoryWorking entries to HistoryFailed.
 // When atari crashes while working on a bead, that bead's history entry will be
 // in the "working" state. On restart, we normalize these to "failed" so the
@@ -262,7 +307,7 @@ Use --daemon to run in the background.`,
 
 			// Check for incompatible flags
 			if tuiEnabled && daemonMode {
-				return fmt.Errorf("--tui and --daemon flags are incompatible")
+				return fmt.Errorf("--tui and --daemon flags are incompatible (run 'atari attach' to open the TUI for a daemon)")
 			}
 
 			if viper.GetBool(FlagVerbose) {
@@ -310,6 +355,23 @@ Use --daemon to run in the background.`,
 			if cmd.Flags().Changed(FlagBDActivityEnabled) {
 				cfg.BDActivity.Enabled = viper.GetBool(FlagBDActivityEnabled)
 			}
+			if cmd.Flags().Changed(FlagWorkers) {
+				cfg.Workers = viper.GetInt(FlagWorkers)
+			}
+
+			// Drain-until flag overrides
+			if cmd.Flags().Changed(FlagMaxBeads) {
+				cfg.Until.MaxBeads = viper.GetInt(FlagMaxBeads)
+			}
+			if cmd.Flags().Changed(FlagUntilEpicClosed) {
+				cfg.Until.EpicClosed = viper.GetString(FlagUntilEpicClosed)
+			}
+			if cmd.Flags().Changed(FlagMaxCost) {
+				cfg.Until.MaxCostUSD = viper.GetFloat64(FlagMaxCost)
+			}
+			if cmd.Flags().Changed(FlagMaxDuration) {
+				cfg.Until.MaxDuration = viper.GetDuration(FlagMaxDuration)
+			}
 
 			// Observer flag overrides
 			if cmd.Flags().Changed(FlagObserverEnabled) {
@@ -338,6 +400,33 @@ Use --daemon to run in the background.`,
 				cfg.Claude.MaxTurns = viper.GetInt(FlagMaxTurns)
 			}
 
+			// Check prompt templates before starting any sessions
+			if err := cfg.ValidatePrompts(); err != nil {
+				return fmt.Errorf("invalid prompt: %w", err)
+			}
+
+			// Select the coding agent backend
+			backend, err := session.NewBackend(cfg)
+			if err != nil {
+				return fmt.Errorf("agent backend: %w", err)
+			}
+
+			drainSchedule, err := schedule.New(cfg.Schedule)
+			if err != nil {
+				return err
+			}
+
+			// Check the selection mode before any work is selected
+			if _, err := workqueue.NewStrategy(cfg.WorkQueue.SelectionMode, cfg.WorkQueue.Selection); err != nil {
+				return err
+			}
+			if err := cfg.ValidateRetry(); err != nil {
+				return err
+			}
+			if err := cfg.ValidateWorkers(); err != nil {
+				return err
+			}
+
 			// Find project root for path resolution
 			projectRoot := daemon.FindProjectRoot("")
 
@@ -347,6 +436,20 @@ Use --daemon to run in the background.`,
 				return fmt.Errorf("resolve paths: %w", err)
 			}
 
+			// Create command runner for real commands
+			cmdRunner := cmdexec.NewExecRunner()
+
+			// Create br client for bead operations
+			brClient, err := newBeadClient(cfg, cmdRunner, projectRoot)
+			if err != nil {
+				return err
+			}
+
+			// Refuse to drain alongside another atari, such as an 'atari run'
+			if pid := daemon.OtherInstancePID(projectRoot); pid != 0 {
+				return fmt.Errorf("atari is already running in this project (pid %d)", pid)
+			}
+
 			// Check if daemon is already running
 			if daemonMode {
 				client := daemon.NewClient(cfg.Paths.Socket)
@@ -416,17 +519,24 @@ Use --daemon to run in the background.`,
 				return fmt.Errorf("start state sink: %w", err)
 			}
 
-			// Create command runner for real commands
-			cmdRunner := cmdexec.NewExecRunner()
+			historySink := history.NewSink(cfg.Paths.History)
+			if err := historySink.Start(sinkCtx, router.SubscribeBuffered(events.StateBufferSize)); err != nil {
+				router.Close()
+				sinkCancel()
+				_ = logSink.Stop()
+				_ = stateSink.Stop()
+				return fmt.Errorf("start history sink: %w", err)
+			}
 
-			// Create br client for bead operations
-			brClient := brclient.NewCLIClient(cmdRunner)
+			// Share bead reads between the controller, work queue and TUI
+			brClient = cacheBeadClient(sinkCtx, cfg, brClient, router)
 
 			// Create process runner for bd activity watcher
 			processRunner := runner.NewExecProcessRunner()
 
-			// Create work queue
+			// Create work queue; the cost-aware strategies learn from the run history
 			wq := workqueue.New(cfg, brClient, logger)
+			wq.SetCostEstimator(history.NewCostModel(cfg.Paths.History))
 
 			// Wire crash recovery state into workqueue
 			// StateSink loaded state from disk during Start(). Now we need to:
@@ -449,19 +559,53 @@ Use --daemon to run in the background.`,
 				var err error
 				tuiLogResult, err = SetupTUILogger(filepath.Dir(cfg.Paths.Log), logLevel, cfg.LogRotation)
 				if err != nil {
-					sinkCancel()
 					router.Close()
+					sinkCancel()
 					_ = logSink.Stop()
 					_ = stateSink.Stop()
+					_ = historySink.Stop()
 					return err
 				}
 				ctrlLogger = tuiLogResult.Logger
 				slog.SetDefault(ctrlLogger)
 			}
 
+			// Send webhook and desktop notifications for drain milestones
+			notifySink, err := notify.New(cfg.Notify, cmdRunner, ctrlLogger)
+			if err != nil {
+				router.Close()
+				sinkCancel()
+				_ = logSink.Stop()
+				_ = stateSink.Stop()
+				_ = historySink.Stop()
+				return fmt.Errorf("configure notifications: %w", err)
+			}
+			if notifySink.Enabled() {
+				if err := notifySink.Start(sinkCtx, router.Subscribe()); err != nil {
+					router.Close()
+					sinkCancel()
+					_ = logSink.Stop()
+					_ = stateSink.Stop()
+					_ = historySink.Stop()
+					return fmt.Errorf("start notify sink: %w", err)
+				}
+			}
+
+			// Create worktree manager for parallel workers
+			worktrees := worktree.New(cmdRunner, projectRoot, cfg.Paths.Worktrees)
+
+			ctrlOpts := []controller.ControllerOption{
+				controller.WithStateSink(stateSink),
+				controller.WithWorktrees(worktrees),
+				controller.WithBackend(backend),
+			}
+			ctrlOpts = append(ctrlOpts, sessionOptions(cfg, cmdRunner, projectRoot, ctrlLogger)...)
+			if drainSchedule != nil {
+				ctrlOpts = append(ctrlOpts, controller.WithSchedule(drainSchedule))
+			}
+
 			// Create controller with appropriate logger and state sink
-			ctrl := controller.New(cfg, wq, router, brClient, processRunner, ctrlLogger,
-				controller.WithStateSink(stateSink))
+			ctrl := controller.New(cfg, wq, router, brClient, processRunner, ctrlLogger, ctrlOpts...)
 
 			// TUI mode: run TUI in foreground with controller in background
 			if tuiEnabled {
@@ -514,10 +658,12 @@ Use --daemon to run in the background.`,
 				select {
 				case err := <-ctrlDone:
 					// Controller failed immediately - clean up and return error
-					sinkCancel()
 					router.Close()
+					sinkCancel()
 					_ = logSink.Stop()
 					_ = stateSink.Stop()
+					_ = historySink.Stop()
+					_ = notifySink.Stop()
 					_ = tuiLogResult.Close()
 					_ = daemon.RemoveDaemonInfo(daemon.DaemonInfoPath(projectRoot))
 					return err
@@ -533,17 +679,19 @@ Use --daemon to run in the background.`,
 				<-ctrlDone
 
 				// Clean up
-				sinkCancel()
 				router.Close()
+				sinkCancel()
 				_ = logSink.Stop()
 				_ = stateSink.Stop()
+				_ = historySink.Stop()
+				_ = notifySink.Stop()
 				_ = daemon.RemoveDaemonInfo(daemon.DaemonInfoPath(projectRoot))
 
 				return tuiErr
 			}
 
 			// Non-TUI mode: create daemon for RPC control
-			dmn := daemon.New(cfg, ctrl, logger)
+			dmn := daemon.New(cfg, ctrl, logger, daemon.WithRouter(router))
 
 			// Start daemon socket server in background
 			daemonCtx, daemonCancel := context.WithCancel(ctx)
@@ -572,10 +720,12 @@ Use --daemon to run in the background.`,
 			)
 
 			// Clean up sinks
-			sinkCancel()
 			router.Close()
+			sinkCancel()
 			_ = logSink.Stop()
 			_ = stateSink.Stop()
+			_ = historySink.Stop()
+			_ = notifySink.Stop()
 
 			// Remove daemon info on clean exit
 			_ = daemon.RemoveDaemonInfo(daemon.DaemonInfoPath(projectRoot))
@@ -592,10 +742,17 @@ Use --daemon to run in the background.`,
 	startCmd.Flags().String(FlagEpic, "", "Restrict work to beads under this epic (e.g., bd-xxx)")
 	startCmd.Flags().Bool(FlagUnassignedOnly, false, "Only claim unassigned beads")
 	startCmd.Flags().StringSlice(FlagExcludeLabels, nil, "Labels to exclude from work selection (comma-separated)")
-	startCmd.Flags().String(FlagSelectionMode, "top-level", "Selection mode: top-level or global")
+	startCmd.Flags().String(FlagSelectionMode, "top-level", "Selection mode: top-level, global, priority, aging, unblocking, cheapest or weighted")
 	startCmd.Flags().Bool(FlagEagerSwitch, false, "Switch beads eagerly when higher priority available")
 	startCmd.Flags().String(FlagPrompt, "", "Custom prompt template file")
 	startCmd.Flags().Bool(FlagBDActivityEnabled, true, "Enable BD activity watcher")
+	startCmd.Flags().Int(FlagWorkers, 1, "Number of beads to work on in parallel (each in its own git worktree)")
+
+	// Drain-until flags
+	startCmd.Flags().Int(FlagMaxBeads, 0, "Stop after completing this many beads (0 = no limit)")
+	startCmd.Flags().String(FlagUntilEpicClosed, "", "Stop once this epic is closed (e.g., bd-xxx)")
+	startCmd.Flags().Float64(FlagMaxCost, 0, "Stop once this drain has spent this many USD (0 = no limit)")
+	startCmd.Flags().Duration(FlagMaxDuration, 0, "Stop once this drain has run this long, e.g. 2h (0 = no limit)")
 
 	// Observer flags
 	startCmd.Flags().Bool(FlagObserverEnabled, true, "Enable observer mode in TUI")
@@ -642,6 +799,30 @@ Use --daemon to run in the background.`,
 				if status.Stats.CurrentTurns > 0 {
 					fmt.Printf("Current turns: %d\n", status.Stats.CurrentTurns)
 				}
+				if len(status.Workers) == 1 && status.Workers[0].SelectedBecause != "" {
+					fmt.Printf("Selected because: %s\n", status.Workers[0].SelectedBecause)
+				}
+			}
+			if len(status.Workers) > 1 {
+				fmt.Printf("Workers:\n")
+				for _, w := range status.Workers {
+					if w.BeadID == "" {
+						fmt.Printf("  %d: idle\n", w.ID)
+						continue
+					}
+					model := ""
+					if w.Model != "" {
+						model = w.Model + ", "
+					}
+					fmt.Printf("  %d: %s (%s%d turns, %s, ~$%.2f)\n", w.ID, w.BeadID, model, w.Turns, w.Elapsed, w.CostUSD)
+					if w.SelectedBecause != "" {
+						fmt.Printf("     selected because: %s\n", w.SelectedBecause)
+					}
+				}
+			}
+			if s := status.Schedule; s != nil {
+				sched := schedule.Status{Open: s.Open, Window: s.Window, Next: s.Next}
+				fmt.Printf("Schedule: %s\n", sched.Describe(time.Now()))
 			}
 			fmt.Printf("Uptime: %s\n", status.Uptime)
 			fmt.Printf("Started: %s\n", status.StartTime)
@@ -654,6 +835,10 @@ Use --daemon to run in the background.`,
 			if status.Stats.InBackoff > 0 {
 				fmt.Printf("  In backoff: %d\n", status.Stats.InBackoff)
 			}
+			fmt.Printf("  Cost: $%.2f\n", status.Stats.TotalCostUSD)
+			if c := status.BeadCache; c != nil {
+				fmt.Printf("Bead cache: %s\n", describeBeadCache(c))
+			}
 			return nil
 		},
 	}
@@ -792,7 +977,14 @@ Use --force to stop immediately without waiting for bead completion.`,
 			follow := viper.GetBool(FlagFollow)
 
 			if follow {
-				return tailFollow(cmd.Context(), logPath)
+				params := daemon.SubscribeParams{
+					Types:  viper.GetStringSlice(FlagType),
+					BeadID: viper.GetString(FlagBead),
+				}
+				if since := viper.GetDuration(FlagSince); since > 0 {
+					params.Since = time.Now().Add(-since)
+				}
+				return followEvents(cmd.Context(), logPath, params)
 			}
 			return tailLast(logPath, count)
 		},
@@ -800,6 +992,9 @@ Use --force to stop immediately without waiting for bead completion.`,
 
 	eventsCmd.Flags().Bool(FlagFollow, false, "Follow event stream (like tail -f)")
 	eventsCmd.Flags().Int(FlagCount, 20, "Number of recent events to show")
+	eventsCmd.Flags().StringSlice(FlagType, nil, "Only follow these event types (e.g. claude.text,iteration.end)")
+	eventsCmd.Flags().String(FlagBead, "", "Only follow events for this bead")
+	eventsCmd.Flags().Duration(FlagSince, 0, "When following, first replay events from this long ago (e.g. 10m)")
 	eventsCmd.Flags().VisitAll(func(f *pflag.Flag) {
 		_ = viper.BindPFlag(f.Name, f)
 	})
@@ -853,10 +1048,17 @@ Creates the following structure:
 	rootCmd.AddCommand(retryCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(newAttachCmd())
+	rootCmd.AddCommand(newHistoryCmd())
+	rootCmd.AddCommand(newReportCmd())
+	rootCmd.AddCommand(newTranscriptCmd())
+	rootCmd.AddCommand(newReplayCmd())
+	rootCmd.AddCommand(newRunCmd())
+	rootCmd.AddCommand(newPlanCmd())
 	rootCmd.AddCommand(initCmd)
 
 	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
 		logger.Error("command failed", "error", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
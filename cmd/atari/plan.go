@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/daemon"
+	"github.com/npratt/atari/internal/events"
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/history"
+	"github.com/npratt/atari/internal/plan"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// newPlanCmd creates the plan command, which previews the order a drain
+// would work through beads.
+func newPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Preview the order a drain would work through beads",
+		Long: `Previews the drain without starting any sessions. The work queue selects
+beads from a snapshot of br list and br ready as a drain would, pretending
+each chosen bead closes so the beads it blocked become ready in turn.
+
+Each step shows the bead's epic, why it was selected and its estimated cost
+from the run history. Open beads the drain would not reach are listed with
+the reason: blocked, excluded, in backoff, or at max failures. The plan
+stops where the drain would, including the drain-until conditions other
+than max duration; the cost limit is checked against the estimates.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun, _ := cmd.Flags().GetBool(FlagDryRun); !dryRun {
+				return errors.New("atari plan only previews the drain; use atari start to run it")
+			}
+			if err := checkBrInstalled(); err != nil {
+				return err
+			}
+
+			cfg, err := config.LoadConfig(viper.GetViper())
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if cmd.Flags().Changed(FlagLabel) {
+				cfg.WorkQueue.Label, _ = cmd.Flags().GetString(FlagLabel)
+			}
+			if cmd.Flags().Changed(FlagEpic) {
+				cfg.WorkQueue.Epic, _ = cmd.Flags().GetString(FlagEpic)
+			}
+			if cmd.Flags().Changed(FlagSelectionMode) {
+				cfg.WorkQueue.SelectionMode, _ = cmd.Flags().GetString(FlagSelectionMode)
+			}
+			if cmd.Flags().Changed(FlagMaxBeads) {
+				cfg.Until.MaxBeads, _ = cmd.Flags().GetInt(FlagMaxBeads)
+			}
+			if cmd.Flags().Changed(FlagUntilEpicClosed) {
+				cfg.Until.EpicClosed, _ = cmd.Flags().GetString(FlagUntilEpicClosed)
+			}
+			if cmd.Flags().Changed(FlagMaxCost) {
+				cfg.Until.MaxCostUSD, _ = cmd.Flags().GetFloat64(FlagMaxCost)
+			}
+			if _, err := workqueue.NewStrategy(cfg.WorkQueue.SelectionMode, cfg.WorkQueue.Selection); err != nil {
+				return err
+			}
+
+			projectRoot := daemon.FindProjectRoot("")
+			cfg.Paths, err = daemon.ResolvePaths(cfg.Paths, projectRoot)
+			if err != nil {
+				return fmt.Errorf("resolve paths: %w", err)
+			}
+			brClient, err := newBeadClient(cfg, cmdexec.NewExecRunner(), projectRoot)
+			if err != nil {
+				return err
+			}
+			state := readState(cfg.Paths.State)
+
+			limit, _ := cmd.Flags().GetInt(FlagLimit)
+			opts := plan.Options{
+				Limit:          limit,
+				ActiveTopLevel: state.ActiveTopLevel,
+				Costs:          history.NewCostModel(cfg.Paths.History),
+			}
+			if len(state.History) > 0 {
+				opts.History = normalizeHistoryForRecovery(state.History)
+			}
+
+			p, err := plan.Build(cmd.Context(), cfg, brClient, opts)
+			if err != nil {
+				return err
+			}
+
+			if asJSON, _ := cmd.Flags().GetBool(FlagJSON); asJSON {
+				return printJSON(p)
+			}
+			return printPlan(p)
+		},
+	}
+	cmd.Flags().Bool(FlagDryRun, true, "Preview without starting sessions (plan never runs beads)")
+	cmd.Flags().Int(FlagLimit, 0, "Stop the preview after this many beads (0 = no limit)")
+	cmd.Flags().Bool(FlagJSON, false, "Output the plan as JSON")
+	cmd.Flags().String(FlagLabel, "", "Filter br ready by label")
+	cmd.Flags().String(FlagEpic, "", "Restrict work to beads under this epic (e.g., bd-xxx)")
+	cmd.Flags().String(FlagSelectionMode, "", "Selection mode to preview (default from config)")
+	cmd.Flags().Int(FlagMaxBeads, 0, "Stop after this many beads (0 = no limit)")
+	cmd.Flags().String(FlagUntilEpicClosed, "", "Stop once this epic would be closed (e.g., bd-xxx)")
+	cmd.Flags().Float64(FlagMaxCost, 0, "Stop once the estimated cost reaches this many USD (0 = no limit)")
+	return cmd
+}
+
+// readState reads the drain state file without the recovery a drain does
+// on load, so a preview never rewrites it. A missing or unreadable file
+// gives an empty state.
+func readState(path string) events.State {
+	var state events.State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+// printPlan prints a plan as a table of steps, grouped by epic, followed by
+// the beads it leaves out.
+func printPlan(p *plan.Plan) error {
+	fmt.Printf("Selection mode: %s\n\n", p.SelectionMode)
+
+	if len(p.Steps) == 0 {
+		fmt.Println("No beads would be worked on.")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "STEP\tEPIC\tBEAD\tPRI\tEST. COST\tTITLE\tSELECTED BECAUSE")
+		lastEpic := ""
+		for i, step := range p.Steps {
+			// Name each epic once, on the first of its steps in a row
+			epic := ""
+			if i == 0 || step.Epic != lastEpic {
+				epic = orDash(step.Epic)
+			}
+			lastEpic = step.Epic
+
+			cost := "-"
+			if step.CostBasis != "" {
+				cost = fmt.Sprintf("$%.2f", step.EstimatedCostUSD)
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\tP%d\t%s\t%s\t%s\n",
+				step.Step, epic, step.BeadID, step.Priority, cost, step.Title, step.SelectedBecause)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println()
+
+		fmt.Printf("Estimated cost: $%.2f", p.EstimatedCostUSD)
+		if p.Unestimated > 0 {
+			fmt.Printf(" (%d beads without cost history)", p.Unestimated)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Stops: %s\n", p.StopReason)
+	if p.Remaining > 0 {
+		fmt.Printf("Not reached: %d more eligible beads\n", p.Remaining)
+	}
+
+	if len(p.Skipped) > 0 {
+		fmt.Println()
+		fmt.Println("Skipped:")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, s := range p.Skipped {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", s.BeadID, s.Reason, s.Title)
+		}
+		return w.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/observer"
+	"github.com/npratt/atari/internal/replay"
+	"github.com/npratt/atari/internal/tui"
+)
+
+// newReplayCmd creates the replay command, which drives the TUI from a
+// recorded event log.
+func newReplayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [log]",
+		Short: "Replay a recorded event log in the TUI",
+		Long: `Feeds a recorded event log into the terminal UI with its original timing,
+without running a drain: no controller, agent or br is involved. The log
+defaults to the project's .atari/atari.log.
+
+Space or p pauses and resumes, [ and ] seek back and forward a minute, and
+{ and } ten minutes. Gaps longer than --max-gap are shortened.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			speedFlag, _ := cmd.Flags().GetString(FlagSpeed)
+			speed, err := parseSpeed(speedFlag)
+			if err != nil {
+				return err
+			}
+			maxGap, _ := cmd.Flags().GetDuration(FlagMaxGap)
+			startPaused, _ := cmd.Flags().GetBool(FlagPaused)
+
+			logPath := ""
+			if len(args) > 0 {
+				logPath = args[0]
+			} else {
+				paths, err := projectPaths()
+				if err != nil {
+					return err
+				}
+				logPath = paths.Log
+			}
+
+			recorded, err := observer.NewLogReader(logPath).ReadAll()
+			if err != nil {
+				return fmt.Errorf("read %s: %w", logPath, err)
+			}
+
+			// Nothing is running, so there is nothing worth logging
+			slog.SetDefault(slog.New(slog.DiscardHandler))
+
+			// Seeking can emit the whole log at once, so the TUI's buffer
+			// holds it twice over rather than dropping events
+			router := events.NewRouter(0)
+			defer router.Close()
+			tuiEvents := router.SubscribeBuffered(2*len(recorded) + events.DefaultBufferSize)
+
+			opts := []replay.Option{replay.WithSpeed(speed), replay.WithMaxGap(maxGap)}
+			if startPaused {
+				opts = append(opts, replay.WithStartPaused())
+			}
+			player := replay.New(recorded, router, opts...)
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			go func() { _ = player.Run(ctx) }()
+
+			workDir, err := os.Getwd()
+			if err != nil {
+				workDir = ""
+			}
+
+			return tui.New(tuiEvents,
+				tui.WithPlayback(player),
+				tui.WithWorkingDirectory(workDir),
+			).Run()
+		},
+	}
+	cmd.Flags().String(FlagSpeed, "1x", "Playback speed as a multiple of real time (e.g. 10x, 0.5x)")
+	cmd.Flags().Duration(FlagMaxGap, replay.DefaultMaxGap, "Longest pause between two events (0 = keep every gap)")
+	cmd.Flags().Bool(FlagPaused, false, "Start paused at the first event")
+	return cmd
+}
+
+// parseSpeed parses a playback speed such as "10x", "10" or "0.5x".
+func parseSpeed(s string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid --%s %q: use a positive multiple such as 10x or 0.5x", FlagSpeed, s)
+	}
+	return speed, nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"10x", 10, false},
+		{"10", 10, false},
+		{"0.5x", 0.5, false},
+		{"1x", 1, false},
+		{"0x", 0, true},
+		{"-2x", 0, true},
+		{"fast", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseSpeed(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSpeed(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSpeed(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/npratt/atari/internal/beadbranch"
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/controller"
+	"github.com/npratt/atari/internal/daemon"
+	"github.com/npratt/atari/internal/events"
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/history"
+	"github.com/npratt/atari/internal/runner"
+	"github.com/npratt/atari/internal/session"
+	"github.com/npratt/atari/internal/transcript"
+	"github.com/npratt/atari/internal/verify"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// Exit codes for atari run.
+const (
+	exitBeadNotCompleted = 2   // the bead was worked on but not closed
+	exitInterrupted      = 130 // interrupted by SIGINT or SIGTERM
+)
+
+// exitError makes atari exit with a specific status code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// newRunCmd creates the run command, which works on one bead in the
+// foreground instead of draining the queue.
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <bead-id>",
+		Short: "Work on a single bead in the foreground",
+		Long: `Works on one bead, skipping work selection, and streams its events to the
+terminal. The bead gets the same treatment as in a drain: prompt expansion,
+a follow-up session if it is left open, and epic auto-close.
+
+Exits 0 if the bead was closed, 2 if it was not, and 130 if interrupted.
+
+If a daemon is running in the project, the bead is queued for the daemon
+to work on next instead; follow it with 'atari events --follow --bead <id>'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			beadID := args[0]
+
+			if err := checkBrInstalled(); err != nil {
+				return err
+			}
+
+			// Hand the bead to a running drain rather than racing it
+			if info, err := daemon.FindDaemonInfo(""); err == nil {
+				client := daemon.NewClient(info.SocketPath)
+				if client.IsRunning() {
+					if err := client.Queue(beadID); err != nil {
+						return fmt.Errorf("queue %s: %w", beadID, err)
+					}
+					fmt.Printf("Queued %s with the running daemon\n", beadID)
+					fmt.Printf("Follow it with: atari events --follow --bead %s\n", beadID)
+					return nil
+				}
+				if info.PID != os.Getpid() && daemon.ProcessAlive(info.PID) {
+					return fmt.Errorf("atari is already running in this project (pid %d) without a control socket; stop it before running a single bead", info.PID)
+				}
+			}
+
+			cfg, err := config.LoadConfig(viper.GetViper())
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if cmd.Flags().Changed(FlagMaxTurns) {
+				cfg.Claude.MaxTurns, _ = cmd.Flags().GetInt(FlagMaxTurns)
+			}
+			// A single bead runs in the project checkout, not a worktree
+			cfg.Workers = 1
+
+			if err := cfg.ValidatePrompts(); err != nil {
+				return fmt.Errorf("invalid prompt: %w", err)
+			}
+			if err := cfg.ValidateRetry(); err != nil {
+				return err
+			}
+			backend, err := session.NewBackend(cfg)
+			if err != nil {
+				return fmt.Errorf("agent backend: %w", err)
+			}
+
+			projectRoot := daemon.FindProjectRoot("")
+			cfg.Paths, err = daemon.ResolvePaths(cfg.Paths, projectRoot)
+			if err != nil {
+				return fmt.Errorf("resolve paths: %w", err)
+			}
+			if err := os.MkdirAll(filepath.Dir(daemon.DaemonInfoPath(projectRoot)), 0755); err != nil {
+				return fmt.Errorf("create .atari directory: %w", err)
+			}
+
+			// Register the run, so an 'atari start' refuses to drain the
+			// project alongside it. There is no control socket.
+			if err := daemon.WriteDaemonInfo(daemon.DaemonInfoPath(projectRoot), &daemon.DaemonInfo{
+				LogPath:   cfg.Paths.Log,
+				StartTime: time.Now(),
+				PID:       os.Getpid(),
+			}); err != nil {
+				return fmt.Errorf("write daemon info: %w", err)
+			}
+			defer func() { _ = daemon.RemoveDaemonInfo(daemon.DaemonInfoPath(projectRoot)) }()
+
+			// Keep log output to warnings so it does not bury the event stream
+			logLevel := slog.LevelWarn
+			if viper.GetBool(FlagVerbose) {
+				logLevel = slog.LevelDebug
+			}
+			logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// The run is recorded like a drain, so history, report and
+			// transcript cover it too
+			router := events.NewRouter(events.DefaultBufferSize)
+			sinkCtx, sinkCancel := context.WithCancel(context.Background())
+			var sinks []interface{ Stop() error }
+			defer func() {
+				router.Close()
+				sinkCancel()
+				for _, sink := range sinks {
+					_ = sink.Stop()
+				}
+			}()
+
+			logSink := events.NewLogSink(cfg.Paths.Log)
+			if err := logSink.Start(sinkCtx, router.Subscribe()); err != nil {
+				return fmt.Errorf("start log sink: %w", err)
+			}
+			sinks = append(sinks, logSink)
+
+			stateSink := events.NewStateSink(cfg.Paths.State)
+			if err := stateSink.Start(sinkCtx, router.SubscribeBuffered(events.StateBufferSize)); err != nil {
+				return fmt.Errorf("start state sink: %w", err)
+			}
+			sinks = append(sinks, stateSink)
+
+			historySink := history.NewSink(cfg.Paths.History)
+			if err := historySink.Start(sinkCtx, router.SubscribeBuffered(events.StateBufferSize)); err != nil {
+				return fmt.Errorf("start history sink: %w", err)
+			}
+			sinks = append(sinks, historySink)
+
+			cmdRunner := cmdexec.NewExecRunner()
+			brClient, err := newBeadClient(cfg, cmdRunner, projectRoot)
+			if err != nil {
+				return err
+			}
+			brClient = cacheBeadClient(sinkCtx, cfg, brClient, router)
+
+			wq := workqueue.New(cfg, brClient, logger)
+			if loaded := stateSink.State(); len(loaded.History) > 0 {
+				wq.SetHistory(normalizeHistoryForRecovery(loaded.History))
+			}
+
+			ctrlOpts := append([]controller.ControllerOption{
+				controller.WithStateSink(stateSink),
+				controller.WithBackend(backend),
+			}, sessionOptions(cfg, cmdRunner, projectRoot, logger)...)
+			ctrl := controller.New(cfg, wq, router, brClient, runner.NewExecProcessRunner(), logger, ctrlOpts...)
+
+			// Stream events to the terminal while the bead runs
+			terminalEvents := router.SubscribeBuffered(events.StateBufferSize)
+			printed := make(chan struct{})
+			go func() {
+				defer close(printed)
+				for ev := range terminalEvents {
+					fmt.Println(events.FormatWithTimestamp(ev))
+				}
+			}()
+
+			status, err := ctrl.RunBead(ctx, beadID)
+			router.Unsubscribe(terminalEvents)
+			<-printed
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case ctx.Err() != nil:
+				return &exitError{code: exitInterrupted, err: fmt.Errorf("interrupted while running %s", beadID)}
+			case status != workqueue.HistoryCompleted:
+				return &exitError{code: exitBeadNotCompleted, err: fmt.Errorf("bead %s not completed: %s", beadID, status)}
+			}
+			fmt.Printf("Completed %s\n", beadID)
+			return nil
+		},
+	}
+	cmd.Flags().Int(FlagMaxTurns, 0, "Max turns per Claude session (0 = unlimited)")
+	return cmd
+}
+
+// sessionOptions returns the controller options that shape each session:
+// branch per bead, verification, transcript recording and the spending
+// earlier runs count against the budget.
+func sessionOptions(cfg *config.Config, cmdRunner cmdexec.CommandRunner, projectRoot string, logger *slog.Logger) []controller.ControllerOption {
+	var opts []controller.ControllerOption
+	if cfg.Git.BranchPerBead {
+		opts = append(opts, controller.WithBranches(
+			beadbranch.New(cmdRunner, projectRoot, cfg.Git.TargetBranch)))
+	}
+	if len(cfg.Verify.Commands) > 0 {
+		opts = append(opts, controller.WithVerifier(
+			verify.New(cmdRunner, cfg.Verify)))
+	}
+	if cfg.Transcripts.Enabled {
+		transcripts := transcript.NewStore(cfg.Paths.Sessions, cfg.Transcripts)
+		if removed, err := transcripts.Prune(); err != nil {
+			logger.Warn("failed to prune session transcripts", "error", err)
+		} else if removed > 0 {
+			logger.Info("pruned old session transcripts", "removed", removed)
+		}
+		opts = append(opts, controller.WithTranscripts(transcripts))
+	}
+	if cfg.Budget.DailyUSD > 0 || cfg.Budget.PerEpicUSD > 0 {
+		records, err := history.Load(cfg.Paths.History)
+		if err != nil {
+			logger.Warn("failed to read history for the budget", "error", err)
+		}
+		opts = append(opts, controller.WithPastSpending(records))
+	}
+	return opts
+}
+
+// exitCode returns the status code atari exits with for a command error.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"plain error", errors.New("boom"), 1},
+		{"exit error", &exitError{code: exitBeadNotCompleted, err: errors.New("not completed")}, exitBeadNotCompleted},
+		{"wrapped exit error", fmt.Errorf("run: %w", &exitError{code: exitInterrupted, err: errors.New("interrupted")}), exitInterrupted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
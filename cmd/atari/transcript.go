@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/transcript"
+)
+
+// newTranscriptCmd creates the transcript command, which shows the saved
+// output of a bead attempt's sessions.
+func newTranscriptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transcript <bead-id>",
+		Short: "Show the full agent transcript of a bead attempt",
+		Long: `Shows what the agent did during a bead attempt: every message, tool call
+and tool result in full, plus anything it wrote to stderr. Transcripts are
+saved under .atari/sessions/<bead>/<attempt>.jsonl; the latest attempt is
+shown unless --attempt is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			beadID := args[0]
+			paths, err := projectPaths()
+			if err != nil {
+				return err
+			}
+			store := transcript.NewStore(paths.Sessions, config.TranscriptConfig{})
+
+			attempts, err := store.Attempts(beadID)
+			if err != nil {
+				return err
+			}
+			if len(attempts) == 0 {
+				return fmt.Errorf("no transcripts for %s in %s", beadID, paths.Sessions)
+			}
+
+			attempt, _ := cmd.Flags().GetInt(FlagAttempt)
+			if attempt == 0 {
+				attempt = attempts[len(attempts)-1]
+			}
+
+			file, err := os.Open(store.Path(beadID, attempt))
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no transcript for %s attempt %d (available: %v)", beadID, attempt, attempts)
+			}
+			if err != nil {
+				return fmt.Errorf("open transcript: %w", err)
+			}
+			defer func() { _ = file.Close() }()
+
+			if raw, _ := cmd.Flags().GetBool(FlagRaw); raw {
+				_, err := io.Copy(os.Stdout, file)
+				return err
+			}
+			return transcript.Render(os.Stdout, file)
+		},
+	}
+	cmd.Flags().Int(FlagAttempt, 0, "Attempt to show (default: the latest)")
+	cmd.Flags().Bool(FlagRaw, false, "Print the recorded JSON lines as they are")
+	return cmd
+}
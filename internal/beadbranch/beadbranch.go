@@ -0,0 +1,224 @@
+// Package beadbranch implements the branch-per-bead workflow.
+//
+// Before a session starts, the bead's work directory is switched to a branch
+// named atari/<bead-id> created from the target branch. When the bead closes,
+// the branch is rebased onto the target and fast-forwarded into it, giving a
+// linear, revertable range of commits per bead. A bead that does not close
+// keeps its work on its branch for the next attempt.
+package beadbranch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/git"
+	"github.com/npratt/atari/internal/worktree"
+)
+
+// Manager creates bead branches and merges them back into the target branch.
+type Manager struct {
+	runner  exec.CommandRunner
+	repoDir string // main repository root
+	target  string // branch bead branches are created from and merged into
+
+	// mu serializes branch updates so parallel workers merge one at a time.
+	mu sync.Mutex
+}
+
+// New creates a Manager for the repository at repoDir. An empty target means
+// the branch checked out in repoDir when ResolveTarget is called.
+func New(runner exec.CommandRunner, repoDir, target string) *Manager {
+	return &Manager{
+		runner:  runner,
+		repoDir: repoDir,
+		target:  target,
+	}
+}
+
+// Name returns the branch used for the given bead.
+func Name(beadID string) string {
+	return worktree.BranchPrefix + beadID
+}
+
+// Target returns the branch bead branches are merged into.
+func (m *Manager) Target() string {
+	return m.target
+}
+
+// ResolveTarget fills in the target branch from the repository's current
+// branch when none was configured. It fails on a detached HEAD or when the
+// current branch is itself an atari branch, since merging into a bead or
+// worker branch is never what the user wants.
+func (m *Manager) ResolveTarget(ctx context.Context) error {
+	if m.target != "" {
+		return nil
+	}
+
+	branch, err := git.CurrentBranch(ctx, m.runner, m.repoDir)
+	if err != nil {
+		return fmt.Errorf("determine current branch (set git.target_branch): %w", err)
+	}
+	if strings.HasPrefix(branch, worktree.BranchPrefix) {
+		return fmt.Errorf("current branch %s is an atari branch; set git.target_branch", branch)
+	}
+	m.target = branch
+	return nil
+}
+
+// Start checks out the bead's branch in dir, creating it from the target
+// branch if needed. An existing branch is reused so commits from an earlier
+// attempt, such as one that failed to merge, are kept.
+// An empty dir means the main repository.
+func (m *Manager) Start(ctx context.Context, dir, beadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir = m.dir(dir)
+	branch := Name(beadID)
+	args := []string{"checkout", "-q", "-b", branch, m.target}
+	if git.BranchExists(ctx, m.runner, m.repoDir, branch) {
+		// A previous attempt may have left the branch checked out in
+		// another worker's worktree.
+		args = []string{"checkout", "-q", "--ignore-other-worktrees", branch}
+	}
+	if _, err := m.git(ctx, dir, args...); err != nil {
+		return fmt.Errorf("checkout %s: %w", branch, err)
+	}
+	return nil
+}
+
+// Merge rebases the bead's branch onto the target and fast-forwards the
+// target to it. The bead branch must be checked out in dir. On failure the
+// rebase is aborted and the target branch is left unchanged.
+// An empty dir means the main repository.
+func (m *Manager) Merge(ctx context.Context, dir, beadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir = m.dir(dir)
+	branch := Name(beadID)
+
+	if _, err := m.git(ctx, dir, "rebase", "-q", m.target); err != nil {
+		_, _ = m.git(ctx, dir, "rebase", "--abort")
+		return fmt.Errorf("rebase %s onto %s: %w", branch, m.target, err)
+	}
+
+	checkedOut, err := m.checkedOutAt(ctx, m.target)
+	if err != nil {
+		return err
+	}
+
+	// Return the main repository to the target branch when that is where
+	// the bead ran, so the project is not left on a bead branch.
+	if checkedOut == "" && dir == m.repoDir {
+		if _, err := m.git(ctx, dir, "checkout", "-q", m.target); err != nil {
+			return fmt.Errorf("checkout %s: %w", m.target, err)
+		}
+		checkedOut = dir
+	}
+
+	if checkedOut != "" {
+		if _, err := m.git(ctx, checkedOut, "merge", "-q", "--ff-only", branch); err != nil {
+			return fmt.Errorf("fast-forward %s to %s: %w", m.target, branch, err)
+		}
+		return nil
+	}
+
+	// The target is not checked out anywhere, so update the ref directly.
+	// fetch refuses anything other than a fast-forward.
+	refspec := "refs/heads/" + branch + ":refs/heads/" + m.target
+	if _, err := m.git(ctx, dir, "fetch", "-q", ".", refspec); err != nil {
+		return fmt.Errorf("fast-forward %s to %s: %w", m.target, branch, err)
+	}
+	return nil
+}
+
+// Leave moves dir off the bead's branch after a session whose work was not
+// merged, so the next bead does not start from this one's changes.
+// Uncommitted changes are committed to the bead branch, where the next
+// attempt picks them up, and the main repository is switched back to the
+// target branch. An empty dir means the main repository.
+func (m *Manager) Leave(ctx context.Context, dir, beadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir = m.dir(dir)
+	branch := Name(beadID)
+
+	current, err := git.CurrentBranch(ctx, m.runner, dir)
+	if err != nil {
+		return fmt.Errorf("determine current branch: %w", err)
+	}
+	if current == branch {
+		out, err := m.git(ctx, dir, "status", "--porcelain")
+		if err != nil {
+			return fmt.Errorf("check for uncommitted changes: %w", err)
+		}
+		if len(bytes.TrimSpace(out)) > 0 {
+			if _, err := m.git(ctx, dir, "add", "-A"); err != nil {
+				return fmt.Errorf("stage changes on %s: %w", branch, err)
+			}
+			msg := "atari: unfinished work on " + beadID
+			if _, err := m.git(ctx, dir, "commit", "-q", "--no-verify", "-m", msg); err != nil {
+				return fmt.Errorf("commit changes on %s: %w", branch, err)
+			}
+		}
+	}
+
+	// Worker worktrees create the next bead's branch from the target
+	// explicitly, so only the main repository needs to move.
+	if dir != m.repoDir {
+		return nil
+	}
+	checkedOut, err := m.checkedOutAt(ctx, m.target)
+	if err != nil {
+		return err
+	}
+	if checkedOut != "" {
+		return nil
+	}
+	if _, err := m.git(ctx, dir, "checkout", "-q", m.target); err != nil {
+		return fmt.Errorf("checkout %s: %w", m.target, err)
+	}
+	return nil
+}
+
+// checkedOutAt returns the path of the worktree that has branch checked out,
+// or "" if none does.
+func (m *Manager) checkedOutAt(ctx context.Context, branch string) (string, error) {
+	out, err := m.git(ctx, m.repoDir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return "", fmt.Errorf("list worktrees: %w", err)
+	}
+
+	var path string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			path = strings.TrimPrefix(line, "worktree ")
+		case line == "branch refs/heads/"+branch:
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// dir returns the directory to run bead git commands in.
+func (m *Manager) dir(dir string) string {
+	if dir == "" {
+		return m.repoDir
+	}
+	return dir
+}
+
+// git runs a git command in dir.
+func (m *Manager) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	return git.Run(ctx, m.runner, dir, args...)
+}
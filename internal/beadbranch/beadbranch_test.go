@@ -0,0 +1,287 @@
+package beadbranch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/testutil"
+)
+
+// initRepo creates a git repository with one commit on main.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	gitRun(t, repo, "init", "-q", "-b", "main")
+	// Rebases run through the Manager need an identity without the test env
+	gitRun(t, repo, "config", "user.name", "test")
+	gitRun(t, repo, "config", "user.email", "test@example.com")
+	writeFile(t, repo, "README", "hello\n")
+	gitRun(t, repo, "add", "README")
+	gitRun(t, repo, "commit", "-q", "-m", "initial")
+	return repo
+}
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitFile writes and commits a file in dir.
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	writeFile(t, dir, name, content)
+	gitRun(t, dir, "add", name)
+	gitRun(t, dir, "commit", "-q", "-m", "update "+name)
+}
+
+func TestName(t *testing.T) {
+	if got := Name("bd-001"); got != "atari/bd-001" {
+		t.Errorf("Name() = %q, want %q", got, "atari/bd-001")
+	}
+}
+
+func TestResolveTarget_KeepsConfigured(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	m := New(runner, "/repo", "develop")
+
+	if err := m.ResolveTarget(context.Background()); err != nil {
+		t.Fatalf("ResolveTarget() error: %v", err)
+	}
+	if m.Target() != "develop" {
+		t.Errorf("Target() = %q, want %q", m.Target(), "develop")
+	}
+	if calls := runner.GetCalls(); len(calls) != 0 {
+		t.Errorf("expected no git calls, got %v", calls)
+	}
+}
+
+func TestResolveTarget_UsesCurrentBranch(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		return []byte("main\n"), nil, true
+	}
+	m := New(runner, "/repo", "")
+
+	if err := m.ResolveTarget(context.Background()); err != nil {
+		t.Fatalf("ResolveTarget() error: %v", err)
+	}
+	if m.Target() != "main" {
+		t.Errorf("Target() = %q, want %q", m.Target(), "main")
+	}
+}
+
+func TestResolveTarget_RejectsAtariBranch(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		return []byte("atari/bd-001\n"), nil, true
+	}
+	m := New(runner, "/repo", "")
+
+	if err := m.ResolveTarget(context.Background()); err == nil {
+		t.Error("expected error when current branch is an atari branch")
+	}
+}
+
+func TestResolveTarget_DetachedHead(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		return nil, errors.New("fatal: ref HEAD is not a symbolic ref"), true
+	}
+	m := New(runner, "/repo", "")
+
+	if err := m.ResolveTarget(context.Background()); err == nil {
+		t.Error("expected error on detached HEAD")
+	}
+}
+
+func TestStartAndMerge_MainRepo(t *testing.T) {
+	repo := initRepo(t)
+	ctx := context.Background()
+	m := New(cmdexec.NewExecRunner(), repo, "")
+	if err := m.ResolveTarget(ctx); err != nil {
+		t.Fatalf("ResolveTarget() error: %v", err)
+	}
+
+	if err := m.Start(ctx, "", "bd-001"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if got := gitRun(t, repo, "symbolic-ref", "--short", "HEAD"); got != "atari/bd-001" {
+		t.Fatalf("current branch = %q, want atari/bd-001", got)
+	}
+	commitFile(t, repo, "feature.txt", "feature\n")
+
+	if err := m.Merge(ctx, "", "bd-001"); err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+
+	if got := gitRun(t, repo, "symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("current branch after merge = %q, want main", got)
+	}
+	if gitRun(t, repo, "rev-parse", "main") != gitRun(t, repo, "rev-parse", "atari/bd-001") {
+		t.Error("expected main to be fast-forwarded to the bead branch")
+	}
+}
+
+func TestMerge_RebasesOntoAdvancedTarget(t *testing.T) {
+	repo := initRepo(t)
+	ctx := context.Background()
+	m := New(cmdexec.NewExecRunner(), repo, "main")
+
+	// Bead runs in a separate worktree while main moves on
+	wt := filepath.Join(t.TempDir(), "wt")
+	gitRun(t, repo, "worktree", "add", "-q", "--detach", wt)
+
+	if err := m.Start(ctx, wt, "bd-002"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	commitFile(t, wt, "bead.txt", "bead\n")
+	commitFile(t, repo, "other.txt", "other\n")
+
+	if err := m.Merge(ctx, wt, "bd-002"); err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+
+	// main is checked out in the repo, so the merge updates its working tree
+	if _, err := os.Stat(filepath.Join(repo, "bead.txt")); err != nil {
+		t.Errorf("expected bead.txt in main working tree: %v", err)
+	}
+	if got := gitRun(t, repo, "rev-list", "--count", "main"); got != "3" {
+		t.Errorf("main commit count = %s, want 3 (linear history)", got)
+	}
+}
+
+func TestMerge_TargetNotCheckedOut(t *testing.T) {
+	repo := initRepo(t)
+	ctx := context.Background()
+	gitRun(t, repo, "branch", "release")
+	m := New(cmdexec.NewExecRunner(), repo, "release")
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	gitRun(t, repo, "worktree", "add", "-q", "--detach", wt)
+
+	if err := m.Start(ctx, wt, "bd-003"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	commitFile(t, wt, "bead.txt", "bead\n")
+
+	if err := m.Merge(ctx, wt, "bd-003"); err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+	if gitRun(t, repo, "rev-parse", "release") != gitRun(t, repo, "rev-parse", "atari/bd-003") {
+		t.Error("expected release to be fast-forwarded to the bead branch")
+	}
+	if gitRun(t, repo, "rev-parse", "main") == gitRun(t, repo, "rev-parse", "release") {
+		t.Error("main should be unchanged")
+	}
+}
+
+func TestMerge_ConflictLeavesTargetUnchanged(t *testing.T) {
+	repo := initRepo(t)
+	ctx := context.Background()
+	m := New(cmdexec.NewExecRunner(), repo, "main")
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	gitRun(t, repo, "worktree", "add", "-q", "--detach", wt)
+
+	if err := m.Start(ctx, wt, "bd-004"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	commitFile(t, wt, "README", "bead version\n")
+	commitFile(t, repo, "README", "main version\n")
+	mainBefore := gitRun(t, repo, "rev-parse", "main")
+
+	err := m.Merge(ctx, wt, "bd-004")
+	if err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if !strings.Contains(err.Error(), "rebase atari/bd-004 onto main") {
+		t.Errorf("error = %q, want it to mention the rebase", err)
+	}
+
+	if got := gitRun(t, repo, "rev-parse", "main"); got != mainBefore {
+		t.Error("main should be unchanged after a conflict")
+	}
+	// Rebase was aborted, leaving the bead branch checked out and intact
+	if got := gitRun(t, wt, "symbolic-ref", "--short", "HEAD"); got != "atari/bd-004" {
+		t.Errorf("worktree branch = %q, want atari/bd-004", got)
+	}
+}
+
+func TestStart_ReusesExistingBranch(t *testing.T) {
+	repo := initRepo(t)
+	ctx := context.Background()
+	m := New(cmdexec.NewExecRunner(), repo, "main")
+
+	if err := m.Start(ctx, "", "bd-005"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	commitFile(t, repo, "first.txt", "first attempt\n")
+	head := gitRun(t, repo, "rev-parse", "HEAD")
+	gitRun(t, repo, "checkout", "-q", "main")
+
+	if err := m.Start(ctx, "", "bd-005"); err != nil {
+		t.Fatalf("second Start() error: %v", err)
+	}
+	if got := gitRun(t, repo, "rev-parse", "HEAD"); got != head {
+		t.Error("expected existing bead branch to be reused with its commits")
+	}
+}
+
+func TestLeave_CommitsChangesAndReturnsToTarget(t *testing.T) {
+	repo := initRepo(t)
+	ctx := context.Background()
+	m := New(cmdexec.NewExecRunner(), repo, "main")
+
+	if err := m.Start(ctx, "", "bd-006"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	writeFile(t, repo, "README", "half done\n")
+	writeFile(t, repo, "new.txt", "untracked\n")
+
+	if err := m.Leave(ctx, "", "bd-006"); err != nil {
+		t.Fatalf("Leave() error: %v", err)
+	}
+
+	if got := gitRun(t, repo, "symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("current branch after leave = %q, want main", got)
+	}
+	if got := gitRun(t, repo, "status", "--porcelain"); got != "" {
+		t.Errorf("expected a clean checkout of main, got %q", got)
+	}
+	if got := gitRun(t, repo, "show", "atari/bd-006:new.txt"); got != "untracked" {
+		t.Errorf("expected changes kept on the bead branch, got %q", got)
+	}
+
+	// The next bead starts from main, not from the unfinished work
+	if err := m.Start(ctx, "", "bd-007"); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if gitRun(t, repo, "rev-parse", "HEAD") != gitRun(t, repo, "rev-parse", "main") {
+		t.Error("expected the next bead branch to start at main")
+	}
+}
@@ -0,0 +1,238 @@
+package brclient
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+// CacheStats counts the reads a CachingClient has served.
+type CacheStats struct {
+	Hits      int64 `json:"hits"`      // answered from the cache
+	Misses    int64 `json:"misses"`    // sent to the wrapped client
+	Coalesced int64 `json:"coalesced"` // shared an identical read already in flight
+}
+
+// CachingClient wraps a Client and caches its reads for a short TTL, so the
+// controller, work queue and TUI asking for the same bead within an
+// iteration cost one br process between them. Concurrent identical reads
+// share a single call to the wrapped client.
+//
+// Writes pass through and clear the cache. Changes made outside atari's own
+// writes are picked up by Watch, and otherwise once the TTL expires. Errors
+// are never cached.
+type CachingClient struct {
+	next Client
+	ttl  time.Duration
+	now  func() time.Time
+
+	group singleflight.Group
+
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	generation uint64 // bumped by Invalidate so reads already in flight are not stored
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	coalesced atomic.Int64
+}
+
+// cacheEntry is a cached read result.
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// NewCachingClient creates a CachingClient that caches reads from next for
+// ttl.
+func NewCachingClient(next Client, ttl time.Duration) *CachingClient {
+	return &CachingClient{
+		next:    next,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Show implements BeadReader and WorkQueueClient.
+func (c *CachingClient) Show(ctx context.Context, id string) (*Bead, error) {
+	return cachedRead(ctx, c, "show:"+id, func(ctx context.Context) (*Bead, error) {
+		return c.next.Show(ctx, id)
+	}, cloneBeadPtr)
+}
+
+// List implements BeadReader and WorkQueueClient.
+func (c *CachingClient) List(ctx context.Context, opts *ListOptions) ([]Bead, error) {
+	key := "list:"
+	if opts != nil {
+		key += opts.Status
+	}
+	return cachedRead(ctx, c, key, func(ctx context.Context) ([]Bead, error) {
+		return c.next.List(ctx, opts)
+	}, cloneBeads)
+}
+
+// Labels implements BeadReader.
+func (c *CachingClient) Labels(ctx context.Context, id string) ([]string, error) {
+	return cachedRead(ctx, c, "labels:"+id, func(ctx context.Context) ([]string, error) {
+		return c.next.Labels(ctx, id)
+	}, slices.Clone[[]string])
+}
+
+// Ready implements WorkQueueClient.
+func (c *CachingClient) Ready(ctx context.Context, opts *ReadyOptions) ([]Bead, error) {
+	key := "ready:"
+	if opts != nil {
+		key += fmt.Sprintf("%s:%t", opts.Label, opts.UnassignedOnly)
+	}
+	return cachedRead(ctx, c, key, func(ctx context.Context) ([]Bead, error) {
+		return c.next.Ready(ctx, opts)
+	}, cloneBeads)
+}
+
+// UpdateStatus implements BeadUpdater.
+func (c *CachingClient) UpdateStatus(ctx context.Context, id, status, notes string) error {
+	defer c.Invalidate()
+	return c.next.UpdateStatus(ctx, id, status, notes)
+}
+
+// Comment implements BeadUpdater.
+func (c *CachingClient) Comment(ctx context.Context, id, message string) error {
+	defer c.Invalidate()
+	return c.next.Comment(ctx, id, message)
+}
+
+// UpdateNotes implements BeadUpdater.
+func (c *CachingClient) UpdateNotes(ctx context.Context, id, notes string) error {
+	defer c.Invalidate()
+	return c.next.UpdateNotes(ctx, id, notes)
+}
+
+// Close implements BeadUpdater.
+func (c *CachingClient) Close(ctx context.Context, id, reason string) error {
+	defer c.Invalidate()
+	return c.next.Close(ctx, id, reason)
+}
+
+// CloseEligibleEpics implements BeadUpdater.
+func (c *CachingClient) CloseEligibleEpics(ctx context.Context) ([]EpicCloseResult, error) {
+	defer c.Invalidate()
+	return c.next.CloseEligibleEpics(ctx)
+}
+
+// Invalidate drops every cached read. Reads already in flight finish but
+// are not cached.
+func (c *CachingClient) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	clear(c.entries)
+}
+
+// Watch invalidates the cache whenever beads may have changed behind
+// atari's back: on a bead change seen by the br activity watcher, and at
+// the end of a session, which may have run br itself. It returns when ctx
+// is done or ch is closed.
+func (c *CachingClient) Watch(ctx context.Context, ch <-chan events.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			switch event.Type() {
+			case events.EventBeadChanged, events.EventSessionEnd:
+				c.Invalidate()
+			}
+		}
+	}
+}
+
+// CacheStats returns the read counters.
+func (c *CachingClient) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Coalesced: c.coalesced.Load(),
+	}
+}
+
+// cachedRead returns the cached result for key if it has not expired, or
+// reads it with fetch, sharing the call with any identical read in flight.
+// Callers get their own copy, since work queue code modifies the beads it
+// is given.
+func cachedRead[T any](ctx context.Context, c *CachingClient, key string, fetch func(context.Context) (T, error), clone func(T) T) (T, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && c.now().Before(entry.expires) {
+		c.mu.Unlock()
+		c.hits.Add(1)
+		return clone(entry.value.(T)), nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	// Only reads started since the last invalidation may share a result
+	ran := false
+	v, err, _ := c.group.Do(fmt.Sprintf("%d:%s", generation, key), func() (any, error) {
+		ran = true
+		c.misses.Add(1)
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.generation == generation {
+			c.entries[key] = cacheEntry{value: value, expires: c.now().Add(c.ttl)}
+		}
+		c.mu.Unlock()
+		return value, nil
+	})
+	if !ran {
+		c.coalesced.Add(1)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return clone(v.(T)), nil
+}
+
+// cloneBeadPtr deep-copies a bead.
+func cloneBeadPtr(b *Bead) *Bead {
+	if b == nil {
+		return nil
+	}
+	clone := cloneBead(*b)
+	return &clone
+}
+
+// cloneBeads deep-copies a slice of beads.
+func cloneBeads(beads []Bead) []Bead {
+	if beads == nil {
+		return nil
+	}
+	clones := make([]Bead, len(beads))
+	for i, b := range beads {
+		clones[i] = cloneBead(b)
+	}
+	return clones
+}
+
+// cloneBead copies a bead along with its slices.
+func cloneBead(b Bead) Bead {
+	b.Labels = slices.Clone(b.Labels)
+	b.BlockedBy = slices.Clone(b.BlockedBy)
+	b.Blocks = slices.Clone(b.Blocks)
+	b.Dependencies = slices.Clone(b.Dependencies)
+	b.Dependents = slices.Clone(b.Dependents)
+	return b
+}
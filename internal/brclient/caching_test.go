@@ -0,0 +1,202 @@
+package brclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+func newTestCachingClient() (*CachingClient, *MockClient, *time.Time) {
+	mock := NewMockClient()
+	mock.SetShowResponse("bd-a", &Bead{ID: "bd-a", Status: "open", Labels: []string{"x"}})
+	mock.ListResponse = []Bead{{ID: "bd-a", Status: "open"}}
+	mock.ReadyResponse = []Bead{{ID: "bd-a", Status: "open"}}
+
+	now := time.Now()
+	c := NewCachingClient(mock, 5*time.Second)
+	c.now = func() time.Time { return now }
+	return c, mock, &now
+}
+
+func TestCachingClient_Hits(t *testing.T) {
+	c, mock, _ := newTestCachingClient()
+	ctx := context.Background()
+
+	for range 3 {
+		bead, err := c.Show(ctx, "bd-a")
+		if err != nil || bead == nil || bead.ID != "bd-a" {
+			t.Fatalf("Show() = %v, %v", bead, err)
+		}
+		if _, err := c.Ready(ctx, &ReadyOptions{Label: "auto"}); err != nil {
+			t.Fatalf("Ready() error: %v", err)
+		}
+	}
+	if _, err := c.Ready(ctx, nil); err != nil {
+		t.Fatalf("Ready() error: %v", err)
+	}
+
+	if len(mock.ShowCalls) != 1 {
+		t.Errorf("br show ran %d times, want 1", len(mock.ShowCalls))
+	}
+	// Different options are cached separately
+	if len(mock.ReadyCalls) != 2 {
+		t.Errorf("br ready ran %d times, want 2", len(mock.ReadyCalls))
+	}
+	if got, want := c.CacheStats(), (CacheStats{Hits: 4, Misses: 3}); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCachingClient_TTL(t *testing.T) {
+	c, mock, now := newTestCachingClient()
+	ctx := context.Background()
+
+	_, _ = c.List(ctx, nil)
+	*now = now.Add(4 * time.Second)
+	_, _ = c.List(ctx, nil)
+	if len(mock.ListCalls) != 1 {
+		t.Fatalf("br list ran %d times within the TTL, want 1", len(mock.ListCalls))
+	}
+
+	*now = now.Add(2 * time.Second)
+	_, _ = c.List(ctx, nil)
+	if len(mock.ListCalls) != 2 {
+		t.Errorf("br list ran %d times after the TTL, want 2", len(mock.ListCalls))
+	}
+}
+
+func TestCachingClient_Copies(t *testing.T) {
+	c, _, _ := newTestCachingClient()
+	ctx := context.Background()
+
+	bead, _ := c.Show(ctx, "bd-a")
+	bead.Status = "closed"
+	bead.Labels[0] = "changed"
+	list, _ := c.List(ctx, nil)
+	list[0].Parent = "bd-epic"
+
+	bead, _ = c.Show(ctx, "bd-a")
+	if bead.Status != "open" || bead.Labels[0] != "x" {
+		t.Errorf("cached bead modified through a returned copy: %+v", bead)
+	}
+	list, _ = c.List(ctx, nil)
+	if list[0].Parent != "" {
+		t.Errorf("cached list modified through a returned copy: %+v", list[0])
+	}
+}
+
+func TestCachingClient_ErrorsNotCached(t *testing.T) {
+	c, mock, _ := newTestCachingClient()
+	ctx := context.Background()
+
+	mock.ListError = errors.New("br failed")
+	if _, err := c.List(ctx, nil); err == nil {
+		t.Fatal("List() error = nil, want the br error")
+	}
+	mock.ListError = nil
+	if _, err := c.List(ctx, nil); err != nil {
+		t.Fatalf("List() error after br recovered: %v", err)
+	}
+	if len(mock.ListCalls) != 2 {
+		t.Errorf("br list ran %d times, want 2", len(mock.ListCalls))
+	}
+}
+
+func TestCachingClient_WritesInvalidate(t *testing.T) {
+	c, mock, _ := newTestCachingClient()
+	ctx := context.Background()
+
+	_, _ = c.Show(ctx, "bd-a")
+	if err := c.UpdateStatus(ctx, "bd-a", "in_progress", ""); err != nil {
+		t.Fatalf("UpdateStatus() error: %v", err)
+	}
+	_, _ = c.Show(ctx, "bd-a")
+	if err := c.Close(ctx, "bd-a", "done"); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	_, _ = c.Show(ctx, "bd-a")
+
+	if len(mock.ShowCalls) != 3 {
+		t.Errorf("br show ran %d times, want 3", len(mock.ShowCalls))
+	}
+	if len(mock.UpdateStatusCalls) != 1 || len(mock.CloseCalls) != 1 {
+		t.Errorf("writes not passed through: %d updates, %d closes", len(mock.UpdateStatusCalls), len(mock.CloseCalls))
+	}
+}
+
+func TestCachingClient_Watch(t *testing.T) {
+	c, mock, _ := newTestCachingClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan events.Event)
+	done := make(chan struct{})
+	go func() {
+		c.Watch(ctx, ch)
+		close(done)
+	}()
+
+	_, _ = c.Show(ctx, "bd-a")
+	// An unrelated event leaves the cache alone
+	ch <- &events.IterationStartEvent{BaseEvent: events.NewInternalEvent(events.EventIterationStart)}
+	ch <- &events.SessionStartEvent{BaseEvent: events.NewInternalEvent(events.EventSessionStart)}
+	_, _ = c.Show(ctx, "bd-a")
+	if len(mock.ShowCalls) != 1 {
+		t.Fatalf("br show ran %d times, want 1", len(mock.ShowCalls))
+	}
+
+	ch <- &events.BeadChangedEvent{BaseEvent: events.NewBDEvent(events.EventBeadChanged), BeadID: "bd-a"}
+	// The send returns once Watch has received the event; a second send
+	// waits until it has been handled
+	ch <- &events.SessionStartEvent{BaseEvent: events.NewInternalEvent(events.EventSessionStart)}
+	_, _ = c.Show(ctx, "bd-a")
+	if len(mock.ShowCalls) != 2 {
+		t.Errorf("br show ran %d times after a bead change, want 2", len(mock.ShowCalls))
+	}
+
+	close(ch)
+	<-done
+}
+
+func TestCachingClient_Coalesces(t *testing.T) {
+	c, mock, _ := newTestCachingClient()
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mock.DynamicShow = func(ctx context.Context, id string) (*Bead, error, bool) {
+		close(started)
+		<-release
+		return nil, nil, false
+	}
+
+	const readers = 8
+	var wg sync.WaitGroup
+	wg.Add(readers)
+	go func() {
+		defer wg.Done()
+		_, _ = c.Show(ctx, "bd-a")
+	}()
+	<-started
+	for range readers - 1 {
+		go func() {
+			defer wg.Done()
+			_, _ = c.Show(ctx, "bd-a")
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	// Readers either shared the first call or found its cached result
+	if len(mock.ShowCalls) != 1 {
+		t.Errorf("br show ran %d times, want 1", len(mock.ShowCalls))
+	}
+	stats := c.CacheStats()
+	if stats.Misses != 1 || stats.Hits+stats.Coalesced != readers-1 {
+		t.Errorf("CacheStats() = %+v, want 1 miss and %d hits or coalesced reads", stats, readers-1)
+	}
+}
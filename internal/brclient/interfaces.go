@@ -17,6 +17,7 @@ type Bead struct {
 	IssueType       string          `json:"issue_type"`
 	Labels          []string        `json:"labels,omitempty"`
 	Parent          string          `json:"parent,omitempty"`
+	Assignee        string          `json:"assignee,omitempty"`
 	CreatedAt       time.Time       `json:"created_at"`
 	CreatedBy       string          `json:"created_by"`
 	UpdatedAt       time.Time       `json:"updated_at"`
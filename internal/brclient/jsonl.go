@@ -0,0 +1,282 @@
+package brclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sources of bead data, as in config.BeadsConfig.Source.
+const (
+	SourceBR    = "br"    // run br for every read
+	SourceJSONL = "jsonl" // read br's JSONL export with a JSONLClient
+)
+
+// JSONLClient implements Client by reading br's JSONL export instead of
+// running br for every query, which saves a process per bead when the work
+// queue or TUI walks the whole tree. Writes go to another client, normally a
+// CLIClient, so br stays the only writer.
+//
+// The index is rebuilt whenever the file's size or modification time
+// changes, checked on every read. Unlike a debounced file watcher, this
+// never serves a bead atari has just closed as ready once br has exported
+// the close.
+type JSONLClient struct {
+	BeadUpdater
+
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	index   *jsonlIndex
+}
+
+// NewJSONLClient creates a JSONLClient that reads beads from the JSONL file
+// at path and sends writes to writer.
+func NewJSONLClient(path string, writer BeadUpdater) *JSONLClient {
+	return &JSONLClient{BeadUpdater: writer, path: path}
+}
+
+// Show implements BeadReader and WorkQueueClient.
+func (c *JSONLClient) Show(ctx context.Context, id string) (*Bead, error) {
+	index, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bead, ok := index.beads[id]
+	if !ok {
+		return nil, fmt.Errorf("bead not found: %s", id)
+	}
+	full := index.detail(bead)
+	return &full, nil
+}
+
+// List implements BeadReader and WorkQueueClient. As with br list, closed
+// beads are left out unless a status is given.
+func (c *JSONLClient) List(ctx context.Context, opts *ListOptions) ([]Bead, error) {
+	index, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var beads []Bead
+	for _, id := range index.order {
+		bead := index.beads[id]
+		if opts != nil && opts.Status != "" {
+			if bead.Status != opts.Status {
+				continue
+			}
+		} else if bead.Status == "closed" {
+			continue
+		}
+		beads = append(beads, index.summary(bead))
+	}
+	return beads, nil
+}
+
+// Labels implements BeadReader.
+func (c *JSONLClient) Labels(ctx context.Context, id string) ([]string, error) {
+	index, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bead, ok := index.beads[id]
+	if !ok {
+		return nil, fmt.Errorf("bead not found: %s", id)
+	}
+	return slices.Clone(bead.Labels), nil
+}
+
+// Ready implements WorkQueueClient. A bead is ready when it is open, not
+// deferred to a later time, and every bead blocking it is closed, matching
+// br ready. Results are ordered by priority, then oldest first.
+func (c *JSONLClient) Ready(ctx context.Context, opts *ReadyOptions) ([]Bead, error) {
+	index, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var beads []Bead
+	for _, id := range index.order {
+		bead := index.beads[id]
+		if bead.Status != "open" || (bead.DeferUntil != nil && bead.DeferUntil.After(now)) {
+			continue
+		}
+		if opts != nil {
+			if opts.Label != "" && !slices.Contains(bead.Labels, opts.Label) {
+				continue
+			}
+			if opts.UnassignedOnly && bead.Assignee != "" {
+				continue
+			}
+		}
+		if index.blocked(bead) {
+			continue
+		}
+		beads = append(beads, index.summary(bead))
+	}
+	sort.SliceStable(beads, func(i, j int) bool {
+		if beads[i].Priority != beads[j].Priority {
+			return beads[i].Priority < beads[j].Priority
+		}
+		return beads[i].CreatedAt.Before(beads[j].CreatedAt)
+	})
+	return beads, nil
+}
+
+// load returns the index, rebuilding it if the file changed since it was
+// last read. If the file cannot be parsed, as when br is part way through
+// rewriting it, the previous index is kept and the file is read again on
+// the next call.
+func (c *JSONLClient) load(ctx context.Context) (*jsonlIndex, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("read beads: %w", err)
+	}
+	if c.index != nil && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return c.index, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("read beads: %w", err)
+	}
+	index, err := parseJSONLIndex(data)
+	if err != nil {
+		if c.index != nil {
+			return c.index, nil
+		}
+		return nil, fmt.Errorf("parse %s: %w", c.path, err)
+	}
+	c.index, c.modTime, c.size = index, info.ModTime(), info.Size()
+	return index, nil
+}
+
+// jsonlRecord is one line of br's JSONL export.
+type jsonlRecord struct {
+	Bead
+	DeferUntil   *time.Time        `json:"defer_until,omitempty"`
+	Dependencies []jsonlDependency `json:"dependencies,omitempty"`
+}
+
+// jsonlDependency is a dependency as exported: IssueID depends on
+// DependsOnID. For "parent-child", DependsOnID is the parent.
+type jsonlDependency struct {
+	IssueID     string `json:"issue_id"`
+	DependsOnID string `json:"depends_on_id"`
+	Type        string `json:"type"`
+}
+
+// jsonlIndex holds the exported beads with dependencies resolved both ways.
+type jsonlIndex struct {
+	beads      map[string]*jsonlRecord
+	order      []string                     // file order
+	dependents map[string][]jsonlDependency // by DependsOnID
+}
+
+// parseJSONLIndex builds an index from the contents of a JSONL export.
+// Deleted (tombstone) beads are left out.
+func parseJSONLIndex(data []byte) (*jsonlIndex, error) {
+	index := &jsonlIndex{
+		beads:      make(map[string]*jsonlRecord),
+		dependents: make(map[string][]jsonlDependency),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec jsonlRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if rec.ID == "" || rec.Status == "tombstone" {
+			continue
+		}
+		if _, seen := index.beads[rec.ID]; !seen {
+			index.order = append(index.order, rec.ID)
+		}
+		index.beads[rec.ID] = &rec
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range index.order {
+		for _, dep := range index.beads[id].Dependencies {
+			index.dependents[dep.DependsOnID] = append(index.dependents[dep.DependsOnID], dep)
+		}
+	}
+	return index, nil
+}
+
+// blocked reports whether any bead blocking rec is still open.
+func (x *jsonlIndex) blocked(rec *jsonlRecord) bool {
+	for _, dep := range rec.Dependencies {
+		if dep.Type != "blocks" {
+			continue
+		}
+		if blocker, ok := x.beads[dep.DependsOnID]; ok && blocker.Status != "closed" {
+			return true
+		}
+	}
+	return false
+}
+
+// summary returns a bead as br list and br ready report it: with parent and
+// counts, without the dependency lists.
+func (x *jsonlIndex) summary(rec *jsonlRecord) Bead {
+	bead := rec.Bead
+	bead.Labels = slices.Clone(rec.Labels)
+	bead.Dependencies, bead.Dependents = nil, nil
+	for _, dep := range rec.Dependencies {
+		if dep.Type == "parent-child" {
+			bead.Parent = dep.DependsOnID
+		}
+	}
+	bead.DependencyCount = len(rec.Dependencies)
+	bead.DependentCount = len(x.dependents[rec.ID])
+	return bead
+}
+
+// detail returns a bead as br show reports it, with its dependencies and
+// dependents.
+func (x *jsonlIndex) detail(rec *jsonlRecord) Bead {
+	bead := x.summary(rec)
+	for _, dep := range rec.Dependencies {
+		bead.Dependencies = append(bead.Dependencies, x.reference(dep.DependsOnID, dep.Type))
+	}
+	for _, dep := range x.dependents[rec.ID] {
+		bead.Dependents = append(bead.Dependents, x.reference(dep.IssueID, dep.Type))
+	}
+	return bead
+}
+
+// reference describes a related bead. Beads missing from the export are
+// referenced by ID alone.
+func (x *jsonlIndex) reference(id, depType string) BeadReference {
+	ref := BeadReference{ID: id, DependencyType: depType}
+	if rec, ok := x.beads[id]; ok {
+		ref.Title, ref.Status = rec.Title, rec.Status
+	}
+	return ref
+}
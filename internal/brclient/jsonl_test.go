@@ -0,0 +1,201 @@
+package brclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testJSONL is an export with an epic whose second task is blocked by the
+// first, plus deferred, labelled, assigned, closed and deleted beads.
+const testJSONL = `{"id":"bd-epic","title":"Epic","status":"open","priority":1,"issue_type":"epic","created_at":"2026-01-01T00:00:00Z"}
+{"id":"bd-a","title":"First","status":"open","priority":2,"issue_type":"task","created_at":"2026-01-02T00:00:00Z","labels":["automated"],"dependencies":[{"issue_id":"bd-a","depends_on_id":"bd-epic","type":"parent-child"}]}
+{"id":"bd-b","title":"Second","status":"open","priority":1,"issue_type":"task","created_at":"2026-01-03T00:00:00Z","dependencies":[{"issue_id":"bd-b","depends_on_id":"bd-epic","type":"parent-child"},{"issue_id":"bd-b","depends_on_id":"bd-a","type":"blocks"}]}
+{"id":"bd-later","title":"Later","status":"open","priority":0,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","defer_until":"2999-01-01T00:00:00Z"}
+{"id":"bd-mine","title":"Mine","status":"open","priority":3,"issue_type":"task","created_at":"2026-01-01T00:00:00Z","assignee":"someone"}
+{"id":"bd-wip","title":"In progress","status":"in_progress","priority":0,"issue_type":"task","created_at":"2026-01-01T00:00:00Z"}
+{"id":"bd-done","title":"Done","status":"closed","priority":0,"issue_type":"task","created_at":"2026-01-01T00:00:00Z"}
+{"id":"bd-gone","title":"Gone","status":"tombstone","priority":0,"issue_type":"task","created_at":"2026-01-01T00:00:00Z"}
+`
+
+func writeJSONL(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func newTestJSONLClient(t *testing.T) (*JSONLClient, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "issues.jsonl")
+	writeJSONL(t, path, testJSONL)
+	return NewJSONLClient(path, NewMockClient()), path
+}
+
+func ids(beads []Bead) []string {
+	out := make([]string, len(beads))
+	for i, b := range beads {
+		out[i] = b.ID
+	}
+	return out
+}
+
+func TestJSONLClient_Ready(t *testing.T) {
+	c, _ := newTestJSONLClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		opts *ReadyOptions
+		want []string
+	}{
+		{"all", nil, []string{"bd-epic", "bd-a", "bd-mine"}},
+		{"label", &ReadyOptions{Label: "automated"}, []string{"bd-a"}},
+		{"unassigned", &ReadyOptions{UnassignedOnly: true}, []string{"bd-epic", "bd-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beads, err := c.Ready(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("Ready() error: %v", err)
+			}
+			if got := ids(beads); !slices.Equal(got, tt.want) {
+				t.Errorf("Ready() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONLClient_List(t *testing.T) {
+	c, _ := newTestJSONLClient(t)
+	ctx := context.Background()
+
+	beads, err := c.List(ctx, nil)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	want := []string{"bd-epic", "bd-a", "bd-b", "bd-later", "bd-mine", "bd-wip"}
+	if got := ids(beads); !slices.Equal(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	if beads[1].Parent != "bd-epic" {
+		t.Errorf("bd-a parent = %q, want bd-epic", beads[1].Parent)
+	}
+	if beads[1].DependentCount != 1 || beads[0].DependentCount != 2 {
+		t.Errorf("dependent counts = %d, %d, want 1, 2", beads[1].DependentCount, beads[0].DependentCount)
+	}
+
+	closed, err := c.List(ctx, &ListOptions{Status: "closed"})
+	if err != nil {
+		t.Fatalf("List(closed) error: %v", err)
+	}
+	if got := ids(closed); !slices.Equal(got, []string{"bd-done"}) {
+		t.Errorf("List(closed) = %v, want [bd-done]", got)
+	}
+}
+
+func TestJSONLClient_Show(t *testing.T) {
+	c, _ := newTestJSONLClient(t)
+	ctx := context.Background()
+
+	bead, err := c.Show(ctx, "bd-b")
+	if err != nil {
+		t.Fatalf("Show() error: %v", err)
+	}
+	if bead.Parent != "bd-epic" {
+		t.Errorf("Parent = %q, want bd-epic", bead.Parent)
+	}
+	wantDeps := []BeadReference{
+		{ID: "bd-epic", Title: "Epic", Status: "open", DependencyType: "parent-child"},
+		{ID: "bd-a", Title: "First", Status: "open", DependencyType: "blocks"},
+	}
+	if !slices.Equal(bead.Dependencies, wantDeps) {
+		t.Errorf("Dependencies = %+v, want %+v", bead.Dependencies, wantDeps)
+	}
+
+	a, err := c.Show(ctx, "bd-a")
+	if err != nil {
+		t.Fatalf("Show() error: %v", err)
+	}
+	wantDependents := []BeadReference{{ID: "bd-b", Title: "Second", Status: "open", DependencyType: "blocks"}}
+	if !slices.Equal(a.Dependents, wantDependents) {
+		t.Errorf("Dependents = %+v, want %+v", a.Dependents, wantDependents)
+	}
+	if labels, _ := c.Labels(ctx, "bd-a"); !slices.Equal(labels, []string{"automated"}) {
+		t.Errorf("Labels() = %v, want [automated]", labels)
+	}
+
+	if _, err := c.Show(ctx, "bd-gone"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Show(deleted) error = %v, want not found", err)
+	}
+}
+
+func TestJSONLClient_Reload(t *testing.T) {
+	c, path := newTestJSONLClient(t)
+	ctx := context.Background()
+
+	if _, err := c.Ready(ctx, nil); err != nil {
+		t.Fatalf("Ready() error: %v", err)
+	}
+
+	// Closing bd-a unblocks bd-b
+	updated := strings.Replace(testJSONL, `"id":"bd-a","title":"First","status":"open"`, `"id":"bd-a","title":"First","status":"closed"`, 1)
+	writeJSONL(t, path, updated)
+	future := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	beads, err := c.Ready(ctx, nil)
+	if err != nil {
+		t.Fatalf("Ready() error: %v", err)
+	}
+	if got := ids(beads); !slices.Equal(got, []string{"bd-epic", "bd-b", "bd-mine"}) {
+		t.Errorf("Ready() after close = %v, want [bd-epic bd-b bd-mine]", got)
+	}
+
+	// A half-written file keeps the last good index
+	writeJSONL(t, path, updated+`{"id":"bd-new","tit`)
+	beads, err = c.Ready(ctx, nil)
+	if err != nil {
+		t.Fatalf("Ready() with partial file error: %v", err)
+	}
+	if len(beads) != 3 {
+		t.Errorf("Ready() with partial file = %v, want the previous 3 beads", ids(beads))
+	}
+}
+
+func TestJSONLClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	missing := NewJSONLClient(filepath.Join(dir, "missing.jsonl"), NewMockClient())
+	if _, err := missing.Ready(ctx, nil); err == nil {
+		t.Error("Ready() on a missing file succeeded")
+	}
+
+	path := filepath.Join(dir, "bad.jsonl")
+	writeJSONL(t, path, "not json\n")
+	bad := NewJSONLClient(path, NewMockClient())
+	if _, err := bad.List(ctx, nil); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("List() on a bad file error = %v, want a line 1 error", err)
+	}
+}
+
+func TestJSONLClient_WritesGoToWriter(t *testing.T) {
+	writer := NewMockClient()
+	c := NewJSONLClient(filepath.Join(t.TempDir(), "issues.jsonl"), writer)
+
+	if err := c.Close(context.Background(), "bd-a", "done"); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if len(writer.CloseCalls) != 1 || writer.CloseCalls[0].ID != "bd-a" {
+		t.Errorf("writer close calls = %+v, want one for bd-a", writer.CloseCalls)
+	}
+}
+
+// JSONLClient must satisfy the full client interface.
+var _ Client = (*JSONLClient)(nil)
@@ -0,0 +1,174 @@
+// Package budget tracks spending against the configured per-bead, per-epic
+// and daily cost limits.
+package budget
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/history"
+)
+
+// Budget scopes.
+const (
+	ScopeBead  = "bead"
+	ScopeEpic  = "epic"
+	ScopeDaily = "daily"
+)
+
+// Breach describes a limit that has been reached.
+type Breach struct {
+	Scope    string  // ScopeBead, ScopeEpic or ScopeDaily
+	ID       string  // bead or epic ID (empty for ScopeDaily)
+	SpentUSD float64 // spending counted against the limit
+	LimitUSD float64 // configured limit
+}
+
+// String returns a human-readable description of the breach.
+func (b *Breach) String() string {
+	switch b.Scope {
+	case ScopeBead:
+		return fmt.Sprintf("bead %s spent $%.2f, over its $%.2f budget", b.ID, b.SpentUSD, b.LimitUSD)
+	case ScopeEpic:
+		return fmt.Sprintf("epic %s spent $%.2f, over its $%.2f budget", b.ID, b.SpentUSD, b.LimitUSD)
+	default:
+		return fmt.Sprintf("spent $%.2f today, over the $%.2f daily budget", b.SpentUSD, b.LimitUSD)
+	}
+}
+
+// Tracker records spending and reports limit breaches. Spending is kept in
+// memory for the life of the process, seeded by Restore from earlier runs;
+// the daily total resets at local midnight. A zero limit disables that check.
+type Tracker struct {
+	cfg config.BudgetConfig
+
+	mu    sync.Mutex
+	beads map[string]float64
+	epics map[string]float64
+	day   string // local date the daily total belongs to (YYYY-MM-DD)
+	daily float64
+
+	now func() time.Time
+}
+
+// New creates a Tracker for the given limits.
+func New(cfg config.BudgetConfig) *Tracker {
+	return &Tracker{
+		cfg:   cfg,
+		beads: make(map[string]float64),
+		epics: make(map[string]float64),
+		now:   time.Now,
+	}
+}
+
+// Record adds cost spent on a bead. epicID may be empty for beads that do
+// not belong to an epic.
+func (t *Tracker) Record(beadID, epicID string, cost float64) {
+	if cost <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollDay()
+	t.beads[beadID] += cost
+	if epicID != "" {
+		t.epics[epicID] += cost
+	}
+	t.daily += cost
+}
+
+// Restore counts the spending of earlier runs from the history archive, so
+// restarting atari does not reset the daily and per-epic totals. Per-bead
+// totals are not restored: a bead's budget covers one run of it.
+func (t *Tracker) Restore(records []history.Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollDay()
+	for _, rec := range records {
+		if rec.CostUSD <= 0 {
+			continue
+		}
+		if rec.EpicID != "" {
+			t.epics[rec.EpicID] += rec.CostUSD
+		}
+		if rec.EndedAt.Local().Format(time.DateOnly) == t.day {
+			t.daily += rec.CostUSD
+		}
+	}
+}
+
+// BeadSpent returns the recorded spending for a bead.
+func (t *Tracker) BeadSpent(beadID string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.beads[beadID]
+}
+
+// CheckBead reports whether a bead has reached its per-bead limit. live is
+// the cost of the bead's running session, which is not yet recorded.
+func (t *Tracker) CheckBead(beadID string, live float64) *Breach {
+	if t.cfg.PerBeadUSD <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	spent := t.beads[beadID] + live
+	t.mu.Unlock()
+
+	if spent < t.cfg.PerBeadUSD {
+		return nil
+	}
+	return &Breach{Scope: ScopeBead, ID: beadID, SpentUSD: spent, LimitUSD: t.cfg.PerBeadUSD}
+}
+
+// Check reports whether the epic or daily limit has been reached. The epic
+// limit is checked first; epicID may be empty to check only the daily limit.
+func (t *Tracker) Check(epicID string) *Breach {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if epicID != "" && t.cfg.PerEpicUSD > 0 && t.epics[epicID] >= t.cfg.PerEpicUSD {
+		return &Breach{Scope: ScopeEpic, ID: epicID, SpentUSD: t.epics[epicID], LimitUSD: t.cfg.PerEpicUSD}
+	}
+
+	t.rollDay()
+	if t.cfg.DailyUSD > 0 && t.daily >= t.cfg.DailyUSD {
+		return &Breach{Scope: ScopeDaily, SpentUSD: t.daily, LimitUSD: t.cfg.DailyUSD}
+	}
+	return nil
+}
+
+// Waive clears the spending counted against a breached limit, granting a
+// fresh budget of the same size. Used when the user resumes past a breach.
+func (t *Tracker) Waive(b *Breach) {
+	if b == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch b.Scope {
+	case ScopeBead:
+		delete(t.beads, b.ID)
+	case ScopeEpic:
+		delete(t.epics, b.ID)
+	case ScopeDaily:
+		t.daily = 0
+	}
+}
+
+// rollDay resets the daily total when the local date changes.
+// Must be called with mu held.
+func (t *Tracker) rollDay() {
+	today := t.now().Format(time.DateOnly)
+	if today != t.day {
+		t.day = today
+		t.daily = 0
+	}
+}
@@ -0,0 +1,185 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/history"
+)
+
+func TestCheckBead(t *testing.T) {
+	tr := New(config.BudgetConfig{PerBeadUSD: 5})
+
+	tr.Record("bd-001", "", 3)
+	if b := tr.CheckBead("bd-001", 1); b != nil {
+		t.Errorf("expected no breach at $4, got %v", b)
+	}
+
+	b := tr.CheckBead("bd-001", 2.5)
+	if b == nil {
+		t.Fatal("expected breach at $5.50")
+	}
+	if b.Scope != ScopeBead || b.ID != "bd-001" {
+		t.Errorf("unexpected breach: %+v", b)
+	}
+	if b.SpentUSD != 5.5 || b.LimitUSD != 5 {
+		t.Errorf("breach spent/limit = %v/%v, want 5.5/5", b.SpentUSD, b.LimitUSD)
+	}
+
+	// Other beads are unaffected
+	if b := tr.CheckBead("bd-002", 0); b != nil {
+		t.Errorf("expected no breach for other bead, got %v", b)
+	}
+}
+
+func TestCheckBead_Disabled(t *testing.T) {
+	tr := New(config.BudgetConfig{})
+	tr.Record("bd-001", "", 1000)
+
+	if b := tr.CheckBead("bd-001", 1000); b != nil {
+		t.Errorf("expected no breach with zero limit, got %v", b)
+	}
+	if b := tr.Check("epic-1"); b != nil {
+		t.Errorf("expected no breach with zero limits, got %v", b)
+	}
+}
+
+func TestCheck_Epic(t *testing.T) {
+	tr := New(config.BudgetConfig{PerEpicUSD: 10})
+
+	tr.Record("bd-001", "epic-1", 6)
+	tr.Record("bd-002", "epic-1", 4)
+	tr.Record("bd-003", "epic-2", 9)
+
+	b := tr.Check("epic-1")
+	if b == nil {
+		t.Fatal("expected epic-1 breach")
+	}
+	if b.Scope != ScopeEpic || b.ID != "epic-1" || b.SpentUSD != 10 {
+		t.Errorf("unexpected breach: %+v", b)
+	}
+	if b := tr.Check("epic-2"); b != nil {
+		t.Errorf("expected no epic-2 breach, got %v", b)
+	}
+	if b := tr.Check(""); b != nil {
+		t.Errorf("expected no breach without epic, got %v", b)
+	}
+}
+
+func TestCheck_Daily(t *testing.T) {
+	tr := New(config.BudgetConfig{DailyUSD: 20})
+	now := time.Date(2026, 1, 15, 23, 0, 0, 0, time.Local)
+	tr.now = func() time.Time { return now }
+
+	tr.Record("bd-001", "epic-1", 15)
+	tr.Record("bd-002", "", 5)
+
+	b := tr.Check("epic-1")
+	if b == nil || b.Scope != ScopeDaily {
+		t.Fatalf("expected daily breach, got %v", b)
+	}
+
+	// A new day starts a fresh daily total
+	now = now.Add(2 * time.Hour)
+	if b := tr.Check(""); b != nil {
+		t.Errorf("expected daily total to reset at midnight, got %v", b)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tr := New(config.BudgetConfig{PerBeadUSD: 5, PerEpicUSD: 10, DailyUSD: 20})
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.Local)
+	tr.now = func() time.Time { return now }
+
+	tr.Restore([]history.Record{
+		{BeadID: "bd-001", EpicID: "epic-1", CostUSD: 6, EndedAt: now.AddDate(0, 0, -1)},
+		{BeadID: "bd-002", EpicID: "epic-1", CostUSD: 4, EndedAt: now.Add(-time.Hour)},
+		{BeadID: "bd-003", CostUSD: 15, EndedAt: now.Add(-2 * time.Hour)},
+	})
+
+	// Epic totals count every earlier run
+	b := tr.Check("epic-1")
+	if b == nil || b.Scope != ScopeEpic || b.SpentUSD != 10 {
+		t.Fatalf("expected epic-1 breach at $10, got %v", b)
+	}
+
+	// The daily total counts only today's runs
+	b = tr.Check("")
+	if b != nil {
+		t.Fatalf("expected no daily breach at $19, got %v", b)
+	}
+	tr.Record("bd-004", "", 1)
+	b = tr.Check("")
+	if b == nil || b.Scope != ScopeDaily || b.SpentUSD != 20 {
+		t.Errorf("expected daily breach at $20, got %v", b)
+	}
+
+	// Per-bead totals start fresh
+	if spent := tr.BeadSpent("bd-003"); spent != 0 {
+		t.Errorf("expected bead spend not restored, got %v", spent)
+	}
+}
+
+func TestWaive(t *testing.T) {
+	tr := New(config.BudgetConfig{PerBeadUSD: 1, PerEpicUSD: 1, DailyUSD: 5})
+	tr.Record("bd-001", "epic-1", 2)
+
+	b := tr.Check("epic-1")
+	if b == nil || b.Scope != ScopeEpic {
+		t.Fatalf("expected epic breach, got %v", b)
+	}
+	tr.Waive(b)
+	if b := tr.Check("epic-1"); b != nil {
+		t.Errorf("expected no breach after waiving epic, got %v", b)
+	}
+
+	bb := tr.CheckBead("bd-001", 0)
+	if bb == nil {
+		t.Fatal("expected bead breach")
+	}
+	tr.Waive(bb)
+	if tr.BeadSpent("bd-001") != 0 {
+		t.Errorf("expected bead spend cleared, got %v", tr.BeadSpent("bd-001"))
+	}
+
+	tr.Record("bd-002", "", 4)
+	db := tr.Check("")
+	if db == nil || db.Scope != ScopeDaily {
+		t.Fatalf("expected daily breach, got %v", db)
+	}
+	tr.Waive(db)
+	if b := tr.Check(""); b != nil {
+		t.Errorf("expected no breach after waiving daily, got %v", b)
+	}
+
+	// Waiving nil is a no-op
+	tr.Waive(nil)
+}
+
+func TestRecord_IgnoresNonPositive(t *testing.T) {
+	tr := New(config.BudgetConfig{PerBeadUSD: 1})
+	tr.Record("bd-001", "", 0)
+	tr.Record("bd-001", "", -1)
+
+	if tr.BeadSpent("bd-001") != 0 {
+		t.Errorf("expected no spend recorded, got %v", tr.BeadSpent("bd-001"))
+	}
+}
+
+func TestBreachString(t *testing.T) {
+	tests := []struct {
+		breach Breach
+		want   string
+	}{
+		{Breach{Scope: ScopeBead, ID: "bd-001", SpentUSD: 5.5, LimitUSD: 5}, "bead bd-001 spent $5.50, over its $5.00 budget"},
+		{Breach{Scope: ScopeEpic, ID: "epic-1", SpentUSD: 12, LimitUSD: 10}, "epic epic-1 spent $12.00, over its $10.00 budget"},
+		{Breach{Scope: ScopeDaily, SpentUSD: 40, LimitUSD: 20}, "spent $40.00 today, over the $20.00 daily budget"},
+	}
+	for _, tt := range tests {
+		if got := tt.breach.String(); !strings.Contains(got, tt.want) {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -1,20 +1,38 @@
 // Package config provides configuration types and defaults for atari.
 package config
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Config holds all configuration for atari.
 type Config struct {
 	Claude      ClaudeConfig      `yaml:"claude" mapstructure:"claude"`
+	Agent       AgentConfig       `yaml:"agent" mapstructure:"agent"`
 	WorkQueue   WorkQueueConfig   `yaml:"workqueue" mapstructure:"workqueue"`
 	Backoff     BackoffConfig     `yaml:"backoff" mapstructure:"backoff"`
+	Retry       RetryConfig       `yaml:"retry" mapstructure:"retry"`
 	Paths       PathsConfig       `yaml:"paths" mapstructure:"paths"`
 	BDActivity  BDActivityConfig  `yaml:"bdactivity" mapstructure:"bdactivity"`
+	Beads       BeadsConfig       `yaml:"beads" mapstructure:"beads"`
 	LogRotation LogRotationConfig `yaml:"log_rotation" mapstructure:"log_rotation"`
 	Observer    ObserverConfig    `yaml:"observer" mapstructure:"observer"`
 	Graph       GraphConfig       `yaml:"graph" mapstructure:"graph"`
 	FollowUp    FollowUpConfig    `yaml:"follow_up" mapstructure:"follow_up"`
 	Shutdown    ShutdownConfig    `yaml:"shutdown" mapstructure:"shutdown"`
+	Git         GitConfig         `yaml:"git" mapstructure:"git"`
+	Budget      BudgetConfig      `yaml:"budget" mapstructure:"budget"`
+	Verify      VerifyConfig      `yaml:"verify" mapstructure:"verify"`
+	Model       ModelConfig       `yaml:"model" mapstructure:"model"`
+	HTTP        HTTPConfig        `yaml:"http" mapstructure:"http"`
+	Notify      NotifyConfig      `yaml:"notify" mapstructure:"notify"`
+	Transcripts TranscriptConfig  `yaml:"transcripts" mapstructure:"transcripts"`
+	Schedule    ScheduleConfig    `yaml:"schedule" mapstructure:"schedule"`
+	Until       UntilConfig       `yaml:"until" mapstructure:"until"`
+	Workers     int               `yaml:"workers" mapstructure:"workers"`         // Parallel sessions, each in its own git worktree when > 1, which requires git.branch_per_bead (default: 1)
+	PromptMode  string            `yaml:"prompt_mode" mapstructure:"prompt_mode"` // "simple" (default) or "template" for Go text/template prompts
+	Prompts     []PromptRule      `yaml:"prompts" mapstructure:"prompts"`         // Per-bead prompt templates, first match wins (falls back to Prompt/PromptFile)
 	Prompt     string `yaml:"prompt" mapstructure:"prompt"`
 	PromptFile string `yaml:"prompt_file" mapstructure:"prompt_file"` // Path to prompt template file (takes priority over Prompt)
 }
@@ -26,15 +44,40 @@ type ClaudeConfig struct {
 	ExtraArgs []string      `yaml:"extra_args" mapstructure:"extra_args"` // Additional CLI args (--max-turns handled separately)
 }
 
+// AgentConfig selects the coding agent that runs sessions.
+type AgentConfig struct {
+	Backend    string   `yaml:"backend" mapstructure:"backend"`         // "claude" (default) or "jsonl"
+	Command    []string `yaml:"command" mapstructure:"command"`         // jsonl backend: program and arguments; the prompt is written to stdin
+	ResumeFlag string   `yaml:"resume_flag" mapstructure:"resume_flag"` // jsonl backend: flag passed before a session ID to resume (empty = no resume)
+	ModelFlag  string   `yaml:"model_flag" mapstructure:"model_flag"`   // jsonl backend: flag passed before the selected model (empty = not passed)
+}
+
 // WorkQueueConfig holds work queue polling settings.
 type WorkQueueConfig struct {
-	PollInterval   time.Duration `yaml:"poll_interval" mapstructure:"poll_interval"`
-	Label          string        `yaml:"label" mapstructure:"label"`
-	Epic           string        `yaml:"epic" mapstructure:"epic"`                       // Restrict work to beads under this epic
-	UnassignedOnly bool          `yaml:"unassigned_only" mapstructure:"unassigned_only"` // Only claim unassigned beads
-	ExcludeLabels  []string      `yaml:"exclude_labels" mapstructure:"exclude_labels"`   // Labels to exclude from selection
-	SelectionMode  string        `yaml:"selection_mode" mapstructure:"selection_mode"`   // Selection mode: "top-level" or "global"
-	EagerSwitch    bool          `yaml:"eager_switch" mapstructure:"eager_switch"`       // Switch beads eagerly when higher priority available
+	PollInterval   time.Duration   `yaml:"poll_interval" mapstructure:"poll_interval"`
+	Label          string          `yaml:"label" mapstructure:"label"`
+	Epic           string          `yaml:"epic" mapstructure:"epic"`                       // Restrict work to beads under this epic
+	UnassignedOnly bool            `yaml:"unassigned_only" mapstructure:"unassigned_only"` // Only claim unassigned beads
+	ExcludeLabels  []string        `yaml:"exclude_labels" mapstructure:"exclude_labels"`   // Labels to exclude from selection
+	SelectionMode  string          `yaml:"selection_mode" mapstructure:"selection_mode"`   // Selection mode: "top-level", "global", or a strategy ("priority", "aging", "unblocking", "cheapest", "weighted")
+	Selection      SelectionConfig `yaml:"selection" mapstructure:"selection"`             // Tuning for the selection strategies
+	EagerSwitch    bool            `yaml:"eager_switch" mapstructure:"eager_switch"`       // Switch beads eagerly when higher priority available
+}
+
+// SelectionConfig tunes the bead selection strategies.
+type SelectionConfig struct {
+	Strategy      string           `yaml:"strategy" mapstructure:"strategy"`             // top-level: strategy that orders top-level items and their beads (empty = priority)
+	AgingInterval time.Duration    `yaml:"aging_interval" mapstructure:"aging_interval"` // aging: time waiting that raises a bead one priority level
+	Weights       SelectionWeights `yaml:"weights" mapstructure:"weights"`               // weighted: how much each factor counts
+}
+
+// SelectionWeights are the factor weights for the weighted strategy. Each
+// factor is scaled to 0-1 across the ready beads before weighting.
+type SelectionWeights struct {
+	Priority   float64 `yaml:"priority" mapstructure:"priority"`     // higher priority (lower number)
+	Age        float64 `yaml:"age" mapstructure:"age"`               // waiting longer
+	Unblocking float64 `yaml:"unblocking" mapstructure:"unblocking"` // more beads depend on it
+	Cost       float64 `yaml:"cost" mapstructure:"cost"`             // cheaper expected cost from run history
 }
 
 // BackoffConfig holds exponential backoff settings for failed beads.
@@ -47,10 +90,13 @@ type BackoffConfig struct {
 
 // PathsConfig holds file paths for state, logs, and socket.
 type PathsConfig struct {
-	State  string `yaml:"state" mapstructure:"state"`
-	Log    string `yaml:"log" mapstructure:"log"`
-	Socket string `yaml:"socket" mapstructure:"socket"`
-	PID    string `yaml:"pid" mapstructure:"pid"`
+	State     string `yaml:"state" mapstructure:"state"`
+	Log       string `yaml:"log" mapstructure:"log"`
+	Socket    string `yaml:"socket" mapstructure:"socket"`
+	PID       string `yaml:"pid" mapstructure:"pid"`
+	Worktrees string `yaml:"worktrees" mapstructure:"worktrees"` // Directory for worker git worktrees (used when workers > 1)
+	History   string `yaml:"history" mapstructure:"history"`     // Append-only archive of every bead attempt
+	Sessions  string `yaml:"sessions" mapstructure:"sessions"`   // Directory for raw session transcripts
 }
 
 // BDActivityConfig holds BD activity watcher settings.
@@ -58,6 +104,14 @@ type BDActivityConfig struct {
 	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
 }
 
+// BeadsConfig selects where atari reads bead data from and how long reads
+// are cached. Writes always go through br.
+type BeadsConfig struct {
+	Source   string        `yaml:"source" mapstructure:"source"`       // "br" (default) runs br for every read; "jsonl" reads br's JSONL export
+	JSONL    string        `yaml:"jsonl" mapstructure:"jsonl"`         // jsonl source: path to the export, relative to the project root
+	CacheTTL time.Duration `yaml:"cache_ttl" mapstructure:"cache_ttl"` // how long bead reads are reused (0 = no caching)
+}
+
 // LogRotationConfig holds settings for log file rotation.
 // Used for the TUI debug log (lumberjack-based automatic rotation).
 type LogRotationConfig struct {
@@ -95,6 +149,79 @@ type ShutdownConfig struct {
 	GracefulTimeout time.Duration `yaml:"graceful_timeout" mapstructure:"graceful_timeout"` // Timeout before force stop (default: 60s)
 }
 
+// GitConfig holds settings for the branch-per-bead workflow.
+type GitConfig struct {
+	BranchPerBead bool   `yaml:"branch_per_bead" mapstructure:"branch_per_bead"` // Work on atari/<bead-id> and merge into TargetBranch on close
+	TargetBranch  string `yaml:"target_branch" mapstructure:"target_branch"`     // Branch to merge into (default: branch checked out at start)
+}
+
+// BudgetConfig holds cost limits in USD. A zero limit disables that check.
+type BudgetConfig struct {
+	PerBeadUSD float64 `yaml:"per_bead_usd" mapstructure:"per_bead_usd"` // Stop a session at the next turn once its bead has spent this much
+	PerEpicUSD float64 `yaml:"per_epic_usd" mapstructure:"per_epic_usd"` // Stall the drain once an epic has spent this much
+	DailyUSD   float64 `yaml:"daily_usd" mapstructure:"daily_usd"`       // Stall the drain once this much has been spent today
+}
+
+// UntilConfig holds conditions that end the drain. When one is met the
+// drain finishes the beads in progress and stops. Zero values disable a check.
+type UntilConfig struct {
+	MaxBeads    int           `yaml:"max_beads" mapstructure:"max_beads"`       // Stop after this many beads are completed
+	EpicClosed  string        `yaml:"epic_closed" mapstructure:"epic_closed"`   // Stop once this epic is closed
+	MaxCostUSD  float64       `yaml:"max_cost_usd" mapstructure:"max_cost_usd"` // Stop once the drain has spent this much
+	MaxDuration time.Duration `yaml:"max_duration" mapstructure:"max_duration"` // Stop once the drain has run this long
+}
+
+// VerifyConfig holds the commands atari runs itself after a bead is closed.
+type VerifyConfig struct {
+	Commands []string      `yaml:"commands" mapstructure:"commands"` // Shell commands that must all exit 0 (empty disables verification)
+	Timeout  time.Duration `yaml:"timeout" mapstructure:"timeout"`   // Time limit for each command
+}
+
+// HTTPConfig holds settings for the daemon's optional HTTP API and metrics endpoint.
+type HTTPConfig struct {
+	Listen string `yaml:"listen" mapstructure:"listen"` // Address to listen on, e.g. "127.0.0.1:9181" (empty = disabled)
+	Token  string `yaml:"token" mapstructure:"token"`   // Bearer token clients must send (empty = generate one into .atari/http-token)
+}
+
+// NotifyConfig holds settings for drain milestone notifications: stalls,
+// abandoned beads, closed epics, budget exhaustion and drain stop.
+type NotifyConfig struct {
+	Desktop     bool            `yaml:"desktop" mapstructure:"desktop"`           // Show desktop notifications via notify-send
+	Webhooks    []WebhookConfig `yaml:"webhooks" mapstructure:"webhooks"`         // HTTP endpoints that receive a JSON POST
+	Events      []string        `yaml:"events" mapstructure:"events"`             // Notification kinds to send (empty = all)
+	MinInterval time.Duration   `yaml:"min_interval" mapstructure:"min_interval"` // Minimum time between notifications of the same kind and bead (0 = no limit)
+}
+
+// WebhookConfig describes one webhook destination.
+type WebhookConfig struct {
+	URL      string            `yaml:"url" mapstructure:"url"`
+	Template string            `yaml:"template" mapstructure:"template"` // Go text/template for the request body (empty = default JSON payload)
+	Headers  map[string]string `yaml:"headers" mapstructure:"headers"`   // Extra request headers, e.g. Authorization
+	Events   []string          `yaml:"events" mapstructure:"events"`     // Further restricts the kinds sent to this webhook (empty = all)
+}
+
+// TranscriptConfig holds settings for saving each session's raw agent output.
+type TranscriptConfig struct {
+	Enabled    bool          `yaml:"enabled" mapstructure:"enabled"`           // Save transcripts under paths.sessions (default: true)
+	MaxPerBead int           `yaml:"max_per_bead" mapstructure:"max_per_bead"` // Attempts kept per bead, oldest removed first (0 = unlimited)
+	MaxAge     time.Duration `yaml:"max_age" mapstructure:"max_age"`           // Remove transcripts older than this at startup (0 = keep forever)
+}
+
+// ScheduleConfig restricts when the drain starts work. With no windows the
+// drain works whenever it runs.
+type ScheduleConfig struct {
+	Windows  []ScheduleWindow `yaml:"windows" mapstructure:"windows"`   // Times work may start; outside them the drain pauses
+	Timezone string           `yaml:"timezone" mapstructure:"timezone"` // IANA time zone for the windows (default: local)
+}
+
+// ScheduleWindow is a recurring period on the given days. A window whose end
+// is not after its start runs past midnight into the next day.
+type ScheduleWindow struct {
+	Days  string `yaml:"days" mapstructure:"days"`   // Cron-style days of the week, e.g. "mon-fri", "sat,sun", "1-5" (default: every day)
+	Start string `yaml:"start" mapstructure:"start"` // Opening time as HH:MM (default: 00:00)
+	End   string `yaml:"end" mapstructure:"end"`     // Closing time as HH:MM (default: midnight at the end of the day)
+}
+
 // DefaultFollowUpPrompt is the prompt sent to follow-up sessions to verify and close beads.
 const DefaultFollowUpPrompt = `The previous session worked on bead {{.BeadID}} ("{{.BeadTitle}}") but did not close it.
 
@@ -173,11 +300,23 @@ func Default() *Config {
 			MaxTurns:  0, // 0 = unlimited; set to 10 for faster graceful pause
 			ExtraArgs: []string{},
 		},
+		Agent: AgentConfig{
+			Backend: "claude",
+		},
 		WorkQueue: WorkQueueConfig{
 			PollInterval:  5 * time.Second,
 			Label:         "",
 			SelectionMode: "top-level",
-			EagerSwitch:   false,
+			Selection: SelectionConfig{
+				AgingInterval: 24 * time.Hour,
+				Weights: SelectionWeights{
+					Priority:   1,
+					Age:        0.5,
+					Unblocking: 0.5,
+					Cost:       0.5,
+				},
+			},
+			EagerSwitch: false,
 		},
 		Backoff: BackoffConfig{
 			Initial:     time.Minute,
@@ -185,15 +324,32 @@ func Default() *Config {
 			Multiplier:  2.0,
 			MaxFailures: 5,
 		},
+		Retry: RetryConfig{
+			RateLimit: RetryPolicy{Action: RetryPause, Wait: 30 * time.Minute},
+			Auth:      RetryPolicy{Action: RetryPause},
+			Timeout:   RetryPolicy{Action: RetryLongBackoff, Wait: 30 * time.Minute},
+			Transient: RetryPolicy{Action: RetryBackoff},
+			Crash:     RetryPolicy{Action: RetryBackoff},
+			GaveUp:    RetryPolicy{Action: RetryBackoff},
+			Other:     RetryPolicy{Action: RetryBackoff},
+		},
 		Paths: PathsConfig{
-			State:  ".atari/state.json",
-			Log:    ".atari/atari.log",
-			Socket: ".atari/atari.sock",
-			PID:    ".atari/atari.pid",
+			State:     ".atari/state.json",
+			Log:       ".atari/atari.log",
+			Socket:    ".atari/atari.sock",
+			PID:       ".atari/atari.pid",
+			Worktrees: ".atari/worktrees",
+			History:   ".atari/history.jsonl",
+			Sessions:  ".atari/sessions",
 		},
 		BDActivity: BDActivityConfig{
 			Enabled: true,
 		},
+		Beads: BeadsConfig{
+			Source:   "br",
+			JSONL:    ".beads/issues.jsonl",
+			CacheTTL: 0,
+		},
 		LogRotation: LogRotationConfig{
 			MaxSizeMB:  100,
 			MaxBackups: 3,
@@ -220,6 +376,29 @@ func Default() *Config {
 		Shutdown: ShutdownConfig{
 			GracefulTimeout: 60 * time.Second,
 		},
-		Prompt: DefaultPrompt,
+		Verify: VerifyConfig{
+			Timeout: 10 * time.Minute,
+		},
+		Notify: NotifyConfig{
+			MinInterval: time.Minute,
+		},
+		Transcripts: TranscriptConfig{
+			Enabled:    true,
+			MaxPerBead: 10,
+			MaxAge:     14 * 24 * time.Hour,
+		},
+		Workers:    1,
+		PromptMode: PromptModeSimple,
+		Prompt:     DefaultPrompt,
+	}
+}
+
+// ValidateWorkers checks that a worker pool can share its work. Each worker
+// commits in its own worktree, and only bead branches merged into the target
+// bring one worker's commits to the others.
+func (c *Config) ValidateWorkers() error {
+	if c.Workers > 1 && !c.Git.BranchPerBead {
+		return fmt.Errorf("workers: %d workers require git.branch_per_bead", c.Workers)
 	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -76,6 +77,7 @@ func TestDefaultPathsConfig(t *testing.T) {
 		{"Log", cfg.Paths.Log, ".atari/atari.log"},
 		{"Socket", cfg.Paths.Socket, ".atari/atari.sock"},
 		{"PID", cfg.Paths.PID, ".atari/atari.pid"},
+		{"Worktrees", cfg.Paths.Worktrees, ".atari/worktrees"},
 	}
 
 	for _, tc := range paths {
@@ -85,6 +87,31 @@ func TestDefaultPathsConfig(t *testing.T) {
 	}
 }
 
+func TestDefaultWorkers(t *testing.T) {
+	cfg := Default()
+
+	if cfg.Workers != 1 {
+		t.Errorf("Workers = %d, want %d", cfg.Workers, 1)
+	}
+}
+
+func TestValidateWorkers(t *testing.T) {
+	cfg := Default()
+	if err := cfg.ValidateWorkers(); err != nil {
+		t.Errorf("ValidateWorkers() with one worker: %v", err)
+	}
+
+	cfg.Workers = 2
+	if err := cfg.ValidateWorkers(); err == nil || !strings.Contains(err.Error(), "git.branch_per_bead") {
+		t.Errorf("ValidateWorkers() without branch_per_bead = %v, want an error naming git.branch_per_bead", err)
+	}
+
+	cfg.Git.BranchPerBead = true
+	if err := cfg.ValidateWorkers(); err != nil {
+		t.Errorf("ValidateWorkers() with branch_per_bead: %v", err)
+	}
+}
+
 func TestDefaultPrompt(t *testing.T) {
 	cfg := Default()
 
@@ -144,3 +171,62 @@ func TestDefaultGraphConfig(t *testing.T) {
 		t.Error("Graph.RefreshOnEvent = true, want false")
 	}
 }
+
+func TestDefaultGitConfig(t *testing.T) {
+	cfg := Default()
+
+	if cfg.Git.BranchPerBead {
+		t.Error("Git.BranchPerBead = true, want false")
+	}
+
+	if cfg.Git.TargetBranch != "" {
+		t.Errorf("Git.TargetBranch = %q, want empty", cfg.Git.TargetBranch)
+	}
+}
+
+func TestDefaultBudgetConfig(t *testing.T) {
+	cfg := Default()
+
+	if cfg.Budget.PerBeadUSD != 0 || cfg.Budget.PerEpicUSD != 0 || cfg.Budget.DailyUSD != 0 {
+		t.Errorf("Budget = %+v, want all limits disabled", cfg.Budget)
+	}
+}
+
+func TestDefaultVerifyConfig(t *testing.T) {
+	cfg := Default()
+
+	if len(cfg.Verify.Commands) != 0 {
+		t.Errorf("Verify.Commands = %v, want none", cfg.Verify.Commands)
+	}
+	if cfg.Verify.Timeout != 10*time.Minute {
+		t.Errorf("Verify.Timeout = %v, want 10m", cfg.Verify.Timeout)
+	}
+}
+
+func TestDefaultNotifyConfig(t *testing.T) {
+	cfg := Default()
+
+	if cfg.Notify.Desktop || len(cfg.Notify.Webhooks) != 0 {
+		t.Errorf("Notify = %+v, want notifications disabled", cfg.Notify)
+	}
+	if cfg.Notify.MinInterval != time.Minute {
+		t.Errorf("Notify.MinInterval = %v, want 1m", cfg.Notify.MinInterval)
+	}
+}
+
+func TestDefaultTranscriptConfig(t *testing.T) {
+	cfg := Default()
+
+	if !cfg.Transcripts.Enabled {
+		t.Error("Transcripts.Enabled = false, want true")
+	}
+	if cfg.Transcripts.MaxPerBead != 10 {
+		t.Errorf("Transcripts.MaxPerBead = %d, want 10", cfg.Transcripts.MaxPerBead)
+	}
+	if cfg.Transcripts.MaxAge != 14*24*time.Hour {
+		t.Errorf("Transcripts.MaxAge = %v, want 336h", cfg.Transcripts.MaxAge)
+	}
+	if cfg.Paths.Sessions != ".atari/sessions" {
+		t.Errorf("Paths.Sessions = %q, want .atari/sessions", cfg.Paths.Sessions)
+	}
+}
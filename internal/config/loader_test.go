@@ -359,3 +359,231 @@ func TestLoadConfig_ObserverDefaults(t *testing.T) {
 		t.Errorf("Observer.Layout = %q, want %q (default)", cfg.Observer.Layout, "horizontal")
 	}
 }
+
+func TestLoadConfig_PromptRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.MkdirAll(ProjectConfigDir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	configContent := `
+prompts:
+  - name: bugs
+    issue_type: bug
+    prompt_file: .atari/prompts/bug.md
+  - name: docs
+    labels: [docs]
+    prompt: "Write docs for {{.BeadID}}"
+`
+	configPath := filepath.Join(ProjectConfigDir, ProjectConfigFile)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(viper.New())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if len(cfg.Prompts) != 2 {
+		t.Fatalf("len(Prompts) = %d, want 2", len(cfg.Prompts))
+	}
+	if cfg.Prompts[0].Name != "bugs" || cfg.Prompts[0].IssueType != "bug" || cfg.Prompts[0].PromptFile != ".atari/prompts/bug.md" {
+		t.Errorf("Prompts[0] = %+v", cfg.Prompts[0])
+	}
+	if len(cfg.Prompts[1].Labels) != 1 || cfg.Prompts[1].Labels[0] != "docs" || cfg.Prompts[1].Prompt != "Write docs for {{.BeadID}}" {
+		t.Errorf("Prompts[1] = %+v", cfg.Prompts[1])
+	}
+}
+
+func TestLoadConfig_ModelRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.MkdirAll(ProjectConfigDir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	configContent := `
+model:
+  default: sonnet
+  rules:
+    - priorities: [0, 1]
+      model: opus
+    - labels: [chore]
+      model: haiku
+  escalate:
+    after_failures: 2
+    model: opus
+`
+	configPath := filepath.Join(ProjectConfigDir, ProjectConfigFile)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(viper.New())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.Model.Default != "sonnet" || len(cfg.Model.Rules) != 2 {
+		t.Fatalf("Model = %+v", cfg.Model)
+	}
+	if r := cfg.Model.Rules[0]; len(r.Priorities) != 2 || r.Priorities[1] != 1 || r.Model != "opus" {
+		t.Errorf("Rules[0] = %+v", r)
+	}
+	if r := cfg.Model.Rules[1]; len(r.Labels) != 1 || r.Labels[0] != "chore" || r.Model != "haiku" {
+		t.Errorf("Rules[1] = %+v", r)
+	}
+	if cfg.Model.Escalate.AfterFailures != 2 || cfg.Model.Escalate.Model != "opus" {
+		t.Errorf("Escalate = %+v", cfg.Model.Escalate)
+	}
+}
+
+func TestLoadConfig_Notify(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.MkdirAll(ProjectConfigDir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	configContent := `
+notify:
+  desktop: true
+  events: [drain.stall, bead.abandoned]
+  min_interval: 5m
+  webhooks:
+    - url: https://hooks.example.com/T000
+      template: '{"text": {{json .Message}}}'
+      headers:
+        Authorization: Bearer secret
+      events: [drain.stall]
+`
+	configPath := filepath.Join(ProjectConfigDir, ProjectConfigFile)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(viper.New())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	n := cfg.Notify
+	if !n.Desktop || len(n.Events) != 2 || n.MinInterval != 5*time.Minute || len(n.Webhooks) != 1 {
+		t.Fatalf("Notify = %+v", n)
+	}
+	hook := n.Webhooks[0]
+	if hook.URL != "https://hooks.example.com/T000" || hook.Template != `{"text": {{json .Message}}}` {
+		t.Errorf("Webhooks[0] = %+v", hook)
+	}
+	if len(hook.Events) != 1 || hook.Events[0] != "drain.stall" {
+		t.Errorf("Webhooks[0].Events = %v", hook.Events)
+	}
+	if len(hook.Headers) != 1 {
+		t.Errorf("Webhooks[0].Headers = %v", hook.Headers)
+	}
+}
+
+func TestLoadConfig_Schedule(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.MkdirAll(ProjectConfigDir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	configContent := `
+schedule:
+  timezone: Europe/London
+  windows:
+    - days: sun-thu
+      start: 19:00
+      end: 07:00
+    - days: sat,sun
+`
+	configPath := filepath.Join(ProjectConfigDir, ProjectConfigFile)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(viper.New())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	s := cfg.Schedule
+	if s.Timezone != "Europe/London" || len(s.Windows) != 2 {
+		t.Fatalf("Schedule = %+v", s)
+	}
+	if w := s.Windows[0]; w.Days != "sun-thu" || w.Start != "19:00" || w.End != "07:00" {
+		t.Errorf("Windows[0] = %+v", w)
+	}
+	if w := s.Windows[1]; w.Days != "sat,sun" || w.Start != "" || w.End != "" {
+		t.Errorf("Windows[1] = %+v", w)
+	}
+}
+
+func TestLoadConfig_Selection(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.MkdirAll(ProjectConfigDir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	configContent := `
+workqueue:
+  selection_mode: weighted
+  selection:
+    aging_interval: 12h
+    weights:
+      unblocking: 2
+      cost: 0
+`
+	configPath := filepath.Join(ProjectConfigDir, ProjectConfigFile)
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(viper.New())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.WorkQueue.SelectionMode != "weighted" {
+		t.Errorf("SelectionMode = %q", cfg.WorkQueue.SelectionMode)
+	}
+	sel := cfg.WorkQueue.Selection
+	if sel.AgingInterval != 12*time.Hour {
+		t.Errorf("AgingInterval = %v, want 12h", sel.AgingInterval)
+	}
+	// Unset weights keep their defaults
+	want := SelectionWeights{Priority: 1, Age: 0.5, Unblocking: 2, Cost: 0}
+	if sel.Weights != want {
+		t.Errorf("Weights = %+v, want %+v", sel.Weights, want)
+	}
+}
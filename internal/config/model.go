@@ -0,0 +1,66 @@
+package config
+
+import "slices"
+
+// ModelConfig chooses the model for each work session. Rules are checked in
+// order and the first match wins; Escalate overrides the choice on retries.
+type ModelConfig struct {
+	Default  string          `yaml:"default" mapstructure:"default"` // Model when no rule matches (empty = the agent's default)
+	Rules    []ModelRule     `yaml:"rules" mapstructure:"rules"`
+	Escalate ModelEscalation `yaml:"escalate" mapstructure:"escalate"`
+}
+
+// ModelRule selects a model for beads matching all of its set conditions.
+// A rule without conditions matches every bead.
+type ModelRule struct {
+	Priorities []int    `yaml:"priorities" mapstructure:"priorities"` // Bead has any of these priorities
+	Labels     []string `yaml:"labels" mapstructure:"labels"`         // Bead has any of these labels
+	IssueType  string   `yaml:"issue_type" mapstructure:"issue_type"` // Bead issue type, e.g. "chore"
+	Model      string   `yaml:"model" mapstructure:"model"`
+}
+
+// ModelEscalation switches to a stronger model once a bead has been
+// attempted and failed AfterFailures times (0 = never escalate).
+type ModelEscalation struct {
+	AfterFailures int    `yaml:"after_failures" mapstructure:"after_failures"`
+	Model         string `yaml:"model" mapstructure:"model"`
+}
+
+// ModelTarget describes the bead a model is selected for.
+type ModelTarget struct {
+	Priority  int
+	Labels    []string
+	IssueType string
+	Failures  int // earlier attempts on this bead that did not complete it
+}
+
+// matches reports whether the rule applies to the target.
+func (r *ModelRule) matches(t ModelTarget) bool {
+	if len(r.Priorities) > 0 && !slices.Contains(r.Priorities, t.Priority) {
+		return false
+	}
+	if len(r.Labels) > 0 && !slices.ContainsFunc(r.Labels, func(l string) bool {
+		return slices.Contains(t.Labels, l)
+	}) {
+		return false
+	}
+	if r.IssueType != "" && r.IssueType != t.IssueType {
+		return false
+	}
+	return true
+}
+
+// SelectModel returns the model for a session on the target bead, or "" to
+// leave the choice to the agent.
+func (c *Config) SelectModel(t ModelTarget) string {
+	esc := c.Model.Escalate
+	if esc.AfterFailures > 0 && esc.Model != "" && t.Failures >= esc.AfterFailures {
+		return esc.Model
+	}
+	for i := range c.Model.Rules {
+		if c.Model.Rules[i].matches(t) {
+			return c.Model.Rules[i].Model
+		}
+	}
+	return c.Model.Default
+}
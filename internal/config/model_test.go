@@ -0,0 +1,45 @@
+package config
+
+import "testing"
+
+func TestSelectModel(t *testing.T) {
+	cfg := &Config{Model: ModelConfig{
+		Default: "sonnet",
+		Rules: []ModelRule{
+			{Priorities: []int{0, 1}, Model: "opus"},
+			{Labels: []string{"complex"}, Model: "opus"},
+			{IssueType: "chore", Model: "haiku"},
+			{Labels: []string{"docs"}, IssueType: "task", Model: "haiku"},
+		},
+		Escalate: ModelEscalation{AfterFailures: 2, Model: "opus"},
+	}}
+
+	tests := []struct {
+		name   string
+		target ModelTarget
+		want   string
+	}{
+		{"high priority", ModelTarget{Priority: 1, IssueType: "task"}, "opus"},
+		{"complex label", ModelTarget{Priority: 3, Labels: []string{"backend", "complex"}}, "opus"},
+		{"chore", ModelTarget{Priority: 3, IssueType: "chore"}, "haiku"},
+		{"all conditions must match", ModelTarget{Priority: 3, Labels: []string{"docs"}, IssueType: "bug"}, "sonnet"},
+		{"docs task", ModelTarget{Priority: 3, Labels: []string{"docs"}, IssueType: "task"}, "haiku"},
+		{"default", ModelTarget{Priority: 2, IssueType: "task"}, "sonnet"},
+		{"below escalation threshold", ModelTarget{Priority: 3, IssueType: "chore", Failures: 1}, "haiku"},
+		{"escalated", ModelTarget{Priority: 3, IssueType: "chore", Failures: 2}, "opus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.SelectModel(tt.target); got != tt.want {
+				t.Errorf("SelectModel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectModel_Unconfigured(t *testing.T) {
+	cfg := Default()
+	if got := cfg.SelectModel(ModelTarget{Priority: 0, Failures: 5}); got != "" {
+		t.Errorf("SelectModel() = %q, want empty for the agent's default", got)
+	}
+}
@@ -3,9 +3,14 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 )
 
+// DefaultPromptName is reported as the prompt name when no rule in
+// Config.Prompts matches and Prompt/PromptFile is used.
+const DefaultPromptName = "default"
+
 // PromptVars holds variables for prompt template expansion.
 type PromptVars struct {
 	BeadID          string
@@ -15,6 +20,78 @@ type PromptVars struct {
 	BeadParent      string // Parent epic/task ID if any
 }
 
+// PromptRule selects a prompt template for beads matching all of its set
+// conditions. A rule without conditions matches every bead.
+type PromptRule struct {
+	Name       string   `yaml:"name" mapstructure:"name"`               // Reported on SessionStartEvent (default: prompt-<index>)
+	Labels     []string `yaml:"labels" mapstructure:"labels"`           // Bead has any of these labels
+	IssueType  string   `yaml:"issue_type" mapstructure:"issue_type"`   // Bead issue type, e.g. "bug"
+	Parent     string   `yaml:"parent" mapstructure:"parent"`           // Bead's parent epic ID
+	Prompt     string   `yaml:"prompt" mapstructure:"prompt"`           // Inline template
+	PromptFile string   `yaml:"prompt_file" mapstructure:"prompt_file"` // Template file (takes priority over Prompt)
+}
+
+// PromptTarget describes the bead a prompt template is selected for.
+type PromptTarget struct {
+	Labels    []string
+	IssueType string
+	Parent    string
+}
+
+// matches reports whether the rule applies to the target.
+func (r *PromptRule) matches(t PromptTarget) bool {
+	if len(r.Labels) > 0 && !slices.ContainsFunc(r.Labels, func(l string) bool {
+		return slices.Contains(t.Labels, l)
+	}) {
+		return false
+	}
+	if r.IssueType != "" && r.IssueType != t.IssueType {
+		return false
+	}
+	if r.Parent != "" && r.Parent != t.Parent {
+		return false
+	}
+	return true
+}
+
+// SelectPrompt returns the name and template of the first rule in Prompts
+// matching the target, or LoadPrompt's template named DefaultPromptName when
+// none match. Returns an error if the chosen template cannot be loaded.
+func (c *Config) SelectPrompt(t PromptTarget) (name, template string, err error) {
+	for i := range c.Prompts {
+		if c.Prompts[i].matches(t) {
+			return c.Prompts[i].load(i)
+		}
+	}
+
+	template, err = c.LoadPrompt()
+	if err != nil {
+		return "", "", err
+	}
+	return DefaultPromptName, template, nil
+}
+
+// load returns the rule's name and template. index is the rule's position in
+// Config.Prompts, used to name unnamed rules.
+func (r *PromptRule) load(index int) (name, template string, err error) {
+	name = r.Name
+	if name == "" {
+		name = fmt.Sprintf("prompt-%d", index+1)
+	}
+	switch {
+	case r.PromptFile != "":
+		content, err := os.ReadFile(r.PromptFile)
+		if err != nil {
+			return "", "", fmt.Errorf("load prompt file %q for %s: %w", r.PromptFile, name, err)
+		}
+		return name, string(content), nil
+	case r.Prompt != "":
+		return name, r.Prompt, nil
+	default:
+		return "", "", fmt.Errorf("prompt %s has neither prompt nor prompt_file", name)
+	}
+}
+
 // LoadPrompt returns the prompt template string based on configuration priority:
 // PromptFile (load from file) > Prompt (inline) > DefaultPrompt.
 // Returns an error if PromptFile is set but the file cannot be read.
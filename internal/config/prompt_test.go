@@ -179,3 +179,84 @@ func TestExpandPrompt_NoInjection(t *testing.T) {
 		t.Errorf("ExpandPrompt() = %q, want %q (should not expand injected variables)", got, want)
 	}
 }
+
+func TestSelectPrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	bugFile := filepath.Join(tmpDir, "bug.md")
+	if err := os.WriteFile(bugFile, []byte("Fix bug {{.BeadID}}"), 0644); err != nil {
+		t.Fatalf("failed to write prompt file: %v", err)
+	}
+
+	cfg := &Config{
+		Prompt: "default prompt",
+		Prompts: []PromptRule{
+			{Name: "bugs", IssueType: "bug", PromptFile: bugFile},
+			{Name: "docs", Labels: []string{"docs", "documentation"}, Prompt: "docs prompt"},
+			{Name: "refactor-epic", Parent: "epic-9", IssueType: "task", Prompt: "refactor prompt"},
+			{Labels: []string{"docs"}, Prompt: "never reached"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		target   PromptTarget
+		wantName string
+		want     string
+	}{
+		{"issue type", PromptTarget{IssueType: "bug", Labels: []string{"docs"}}, "bugs", "Fix bug {{.BeadID}}"},
+		{"any label", PromptTarget{IssueType: "task", Labels: []string{"ui", "documentation"}}, "docs", "docs prompt"},
+		{"all conditions", PromptTarget{IssueType: "task", Parent: "epic-9"}, "refactor-epic", "refactor prompt"},
+		{"partial conditions fall back", PromptTarget{IssueType: "feature", Parent: "epic-9"}, DefaultPromptName, "default prompt"},
+		{"no match", PromptTarget{}, DefaultPromptName, "default prompt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, got, err := cfg.SelectPrompt(tt.target)
+			if err != nil {
+				t.Fatalf("SelectPrompt() error = %v", err)
+			}
+			if name != tt.wantName {
+				t.Errorf("SelectPrompt() name = %q, want %q", name, tt.wantName)
+			}
+			if got != tt.want {
+				t.Errorf("SelectPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPrompt_UnnamedRule(t *testing.T) {
+	cfg := &Config{
+		Prompts: []PromptRule{
+			{IssueType: "bug", Prompt: "bug prompt"},
+			{Prompt: "catch-all prompt"},
+		},
+	}
+
+	name, got, err := cfg.SelectPrompt(PromptTarget{IssueType: "chore"})
+	if err != nil {
+		t.Fatalf("SelectPrompt() error = %v", err)
+	}
+	if name != "prompt-2" || got != "catch-all prompt" {
+		t.Errorf("SelectPrompt() = %q, %q, want prompt-2, catch-all prompt", name, got)
+	}
+}
+
+func TestSelectPrompt_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule PromptRule
+	}{
+		{"missing file", PromptRule{Name: "bugs", PromptFile: "/nonexistent/bug.md"}},
+		{"no template", PromptRule{Name: "bugs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Prompts: []PromptRule{tt.rule}}
+			if _, _, err := cfg.SelectPrompt(PromptTarget{}); err == nil {
+				t.Error("SelectPrompt() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retry actions, as in RetryPolicy.Action.
+const (
+	RetryNow         = "retry"        // retry the bead right away
+	RetryBackoff     = "backoff"      // retry after the exponential backoff
+	RetryLongBackoff = "long_backoff" // retry once Wait has passed
+	RetryPause       = "pause"        // pause the drain; the attempt does not count against the bead
+	RetryAbandon     = "abandon"      // do not retry; the bead is abandoned as at max failures
+)
+
+// RetryConfig sets the retry policy for each class of failure. A class whose
+// action is empty uses RetryBackoff.
+type RetryConfig struct {
+	RateLimit RetryPolicy `yaml:"rate_limit" mapstructure:"rate_limit"` // the agent hit an API rate or usage limit
+	Auth      RetryPolicy `yaml:"auth" mapstructure:"auth"`             // the agent could not log in or its account needs attention
+	Timeout   RetryPolicy `yaml:"timeout" mapstructure:"timeout"`       // the inactivity watchdog stopped the session
+	Transient RetryPolicy `yaml:"transient" mapstructure:"transient"`   // the API was overloaded or the connection dropped
+	Crash     RetryPolicy `yaml:"crash" mapstructure:"crash"`           // the agent exited with an error
+	GaveUp    RetryPolicy `yaml:"gave_up" mapstructure:"gave_up"`       // the agent ended without closing the bead
+	Other     RetryPolicy `yaml:"other" mapstructure:"other"`           // verification, merge or any other failure
+}
+
+// RetryPolicy says what happens after a failure of one class.
+type RetryPolicy struct {
+	Action string        `yaml:"action" mapstructure:"action"` // "retry", "backoff", "long_backoff", "pause" or "abandon"
+	Wait   time.Duration `yaml:"wait" mapstructure:"wait"`     // long_backoff: time before retrying; pause: time before resuming (0 = until atari resume)
+}
+
+// Policy returns the retry policy for a failure class. Unknown classes,
+// such as those missing from older state files, use Other.
+func (r *RetryConfig) Policy(class string) RetryPolicy {
+	var p RetryPolicy
+	switch class {
+	case "rate_limit":
+		p = r.RateLimit
+	case "auth":
+		p = r.Auth
+	case "timeout":
+		p = r.Timeout
+	case "transient":
+		p = r.Transient
+	case "crash":
+		p = r.Crash
+	case "gave_up":
+		p = r.GaveUp
+	default:
+		p = r.Other
+	}
+	if p.Action == "" {
+		p.Action = RetryBackoff
+	}
+	return p
+}
+
+// ValidateRetry checks that every retry policy names a known action and
+// that long backoffs have a wait.
+func (c *Config) ValidateRetry() error {
+	policies := []struct {
+		class  string
+		policy RetryPolicy
+	}{
+		{"rate_limit", c.Retry.RateLimit},
+		{"auth", c.Retry.Auth},
+		{"timeout", c.Retry.Timeout},
+		{"transient", c.Retry.Transient},
+		{"crash", c.Retry.Crash},
+		{"gave_up", c.Retry.GaveUp},
+		{"other", c.Retry.Other},
+	}
+	for _, p := range policies {
+		switch p.policy.Action {
+		case "", RetryNow, RetryBackoff, RetryPause, RetryAbandon:
+		case RetryLongBackoff:
+			if p.policy.Wait <= 0 {
+				return fmt.Errorf("retry.%s: long_backoff requires a wait", p.class)
+			}
+		default:
+			return fmt.Errorf("retry.%s: unknown action %q (want retry, backoff, long_backoff, pause or abandon)", p.class, p.policy.Action)
+		}
+		if p.policy.Wait < 0 {
+			return fmt.Errorf("retry.%s: wait must not be negative", p.class)
+		}
+	}
+	return nil
+}
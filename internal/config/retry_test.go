@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	retry := Default().Retry
+	tests := []struct {
+		class  string
+		action string
+		wait   time.Duration
+	}{
+		{"rate_limit", RetryPause, 30 * time.Minute},
+		{"auth", RetryPause, 0},
+		{"timeout", RetryLongBackoff, 30 * time.Minute},
+		{"transient", RetryBackoff, 0},
+		{"crash", RetryBackoff, 0},
+		{"gave_up", RetryBackoff, 0},
+		{"other", RetryBackoff, 0},
+		{"", RetryBackoff, 0}, // recorded before failures were classified
+	}
+	for _, tt := range tests {
+		got := retry.Policy(tt.class)
+		if got.Action != tt.action || got.Wait != tt.wait {
+			t.Errorf("Policy(%q) = %+v, want %s with wait %s", tt.class, got, tt.action, tt.wait)
+		}
+	}
+
+	// An unset action falls back to backoff
+	retry.GaveUp = RetryPolicy{}
+	if got := retry.Policy("gave_up"); got.Action != RetryBackoff {
+		t.Errorf("Policy(gave_up) with no action = %q, want %q", got.Action, RetryBackoff)
+	}
+}
+
+func TestValidateRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		wantErr string
+	}{
+		{name: "default", policy: Default().Retry.Crash},
+		{name: "abandon", policy: RetryPolicy{Action: RetryAbandon}},
+		{name: "long backoff", policy: RetryPolicy{Action: RetryLongBackoff, Wait: time.Hour}},
+		{name: "long backoff without wait", policy: RetryPolicy{Action: RetryLongBackoff}, wantErr: "requires a wait"},
+		{name: "unknown action", policy: RetryPolicy{Action: "later"}, wantErr: `unknown action "later"`},
+		{name: "negative wait", policy: RetryPolicy{Action: RetryPause, Wait: -time.Minute}, wantErr: "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Retry.Crash = tt.policy
+			err := cfg.ValidateRetry()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateRetry() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "retry.crash") || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateRetry() error = %v, want retry.crash and %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Retry(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	if err := os.MkdirAll(ProjectConfigDir, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	configContent := `
+retry:
+  rate_limit:
+    wait: 1h
+  timeout:
+    action: abandon
+`
+	if err := os.WriteFile(filepath.Join(ProjectConfigDir, ProjectConfigFile), []byte(configContent), 0644); err != nil {
+		t.Fatalf("write config failed: %v", err)
+	}
+
+	cfg, err := LoadConfig(viper.New())
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := cfg.Retry.RateLimit; got.Action != RetryPause || got.Wait != time.Hour {
+		t.Errorf("Retry.RateLimit = %+v, want pause with a 1h wait", got)
+	}
+	if got := cfg.Retry.Timeout.Action; got != RetryAbandon {
+		t.Errorf("Retry.Timeout.Action = %q, want %q", got, RetryAbandon)
+	}
+	if got := cfg.Retry.Transient.Action; got != RetryBackoff {
+		t.Errorf("Retry.Transient.Action = %q, want the default %q", got, RetryBackoff)
+	}
+}
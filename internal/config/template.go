@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/events"
+)
+
+// Prompt modes.
+const (
+	PromptModeSimple   = "simple"   // fixed {{.Variable}} substitution (ExpandPrompt)
+	PromptModeTemplate = "template" // Go text/template with PromptContext (RenderPrompt)
+)
+
+// PromptContext is the data available to prompt templates when PromptMode
+// is "template". The embedded PromptVars keep simple-mode variables working.
+type PromptContext struct {
+	PromptVars
+	Bead         *brclient.Bead           // the bead being worked on, with notes and labels
+	Dependencies []brclient.BeadReference // beads this bead depends on, with titles and status
+	History      *events.BeadHistory      // attempts and last error (nil before the first attempt)
+	Epic         *brclient.Bead           // the bead's parent, if any
+}
+
+// promptFuncs are the helper functions available to prompt templates.
+// They only transform strings; templates cannot run commands or read files.
+var promptFuncs = template.FuncMap{
+	"truncate":   truncatePrompt,
+	"quote":      strconv.Quote,
+	"shellquote": shellQuote,
+	"indent":     indent,
+	"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+	"default":    defaultString,
+}
+
+// ParsePrompt parses a prompt template for the "template" mode. Missing
+// map keys are errors, so typos are reported instead of rendering as empty.
+func ParsePrompt(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// RenderPrompt parses and executes a prompt template with the given context.
+func RenderPrompt(name, text string, ctx PromptContext) (string, error) {
+	tmpl, err := ParsePrompt(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, ctx); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+// ValidatePrompts loads every configured prompt template and, with the
+// "template" mode, parses it and renders it against a sample bead so that
+// syntax errors and misspelled fields are reported at startup. Each template
+// is also rendered for a bead with no epic, dependencies or history, which
+// catches fields used without a {{with}} guard.
+func (c *Config) ValidatePrompts() error {
+	switch c.PromptMode {
+	case "", PromptModeSimple, PromptModeTemplate:
+	default:
+		return fmt.Errorf("unknown prompt_mode %q (want %q or %q)", c.PromptMode, PromptModeSimple, PromptModeTemplate)
+	}
+
+	templates := make(map[string]string)
+	text, err := c.LoadPrompt()
+	if err != nil {
+		return err
+	}
+	templates[DefaultPromptName] = text
+
+	for i := range c.Prompts {
+		name, text, err := c.Prompts[i].load(i)
+		if err != nil {
+			return err
+		}
+		templates[name] = text
+	}
+
+	if c.PromptMode != PromptModeTemplate {
+		return nil
+	}
+	sample := samplePromptContext()
+	minimal := minimalPromptContext()
+	for name, text := range templates {
+		if _, err := RenderPrompt(name, text, sample); err != nil {
+			return err
+		}
+		if _, err := RenderPrompt(name, text, minimal); err != nil {
+			return fmt.Errorf("%w (for a bead with no epic, dependencies or history)", err)
+		}
+	}
+	return nil
+}
+
+// minimalPromptContext returns the least a bead's context can hold: the
+// optional fields that are nil for a parentless, first-attempt bead are nil.
+func minimalPromptContext() PromptContext {
+	return PromptContext{
+		PromptVars: PromptVars{
+			BeadID:    "bd-sample",
+			BeadTitle: "Sample bead",
+		},
+		Bead: &brclient.Bead{ID: "bd-sample", Title: "Sample bead", Status: "in_progress", IssueType: "task"},
+	}
+}
+
+// samplePromptContext returns a fully populated context for validation.
+func samplePromptContext() PromptContext {
+	deps := []brclient.BeadReference{{ID: "bd-dep", Title: "Dependency", Status: "closed", DependencyType: "blocks"}}
+	return PromptContext{
+		PromptVars: PromptVars{
+			BeadID:          "bd-sample",
+			BeadTitle:       "Sample bead",
+			BeadDescription: "Sample description",
+			BeadParent:      "bd-epic",
+		},
+		Bead: &brclient.Bead{
+			ID:           "bd-sample",
+			Title:        "Sample bead",
+			Description:  "Sample description",
+			Status:       "in_progress",
+			IssueType:    "task",
+			Labels:       []string{"sample"},
+			Parent:       "bd-epic",
+			Notes:        "Sample notes",
+			Dependencies: deps,
+		},
+		Dependencies: deps,
+		History: &events.BeadHistory{
+			ID:          "bd-sample",
+			Status:      events.HistoryWorking,
+			Attempts:    2,
+			LastAttempt: time.Now(),
+			LastError:   "sample error",
+		},
+		Epic: &brclient.Bead{ID: "bd-epic", Title: "Sample epic", Description: "Epic description", IssueType: "epic"},
+	}
+}
+
+// truncatePrompt shortens s to at most n runes, marking the cut with "...".
+func truncatePrompt(n int, s string) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell argument.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// indent prefixes every non-empty line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultString returns def when s is empty.
+func defaultString(def, s string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
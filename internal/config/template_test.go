@@ -0,0 +1,180 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/events"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	ctx := PromptContext{
+		PromptVars: PromptVars{BeadID: "bd-1", BeadTitle: "Fix it"},
+		Bead: &brclient.Bead{
+			ID:     "bd-1",
+			Notes:  "line one\nline two",
+			Labels: []string{"backend", "urgent"},
+		},
+		Dependencies: []brclient.BeadReference{{ID: "bd-0", Title: "Setup", Status: "closed"}},
+		History:      &events.BeadHistory{Attempts: 2, LastError: "tests failed"},
+		Epic:         &brclient.Bead{ID: "bd-epic", Title: "Big feature"},
+	}
+
+	tmpl := `Work on {{.BeadID}}: {{.BeadTitle}}
+Labels: {{join ", " .Bead.Labels}}
+{{- range .Dependencies}}
+Depends on {{.ID}} ({{.Status}})
+{{- end}}
+{{- if .Epic}}
+Epic: {{.Epic.Title}}
+{{- end}}
+{{- if .History}}{{if gt .History.Attempts 1}}
+Previous error: {{.History.LastError}}
+{{- end}}{{end}}
+Notes:
+{{indent 2 .Bead.Notes}}`
+
+	got, err := RenderPrompt("test", tmpl, ctx)
+	if err != nil {
+		t.Fatalf("RenderPrompt() error = %v", err)
+	}
+
+	want := `Work on bd-1: Fix it
+Labels: backend, urgent
+Depends on bd-0 (closed)
+Epic: Big feature
+Previous error: tests failed
+Notes:
+  line one
+  line two`
+	if got != want {
+		t.Errorf("RenderPrompt() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderPrompt_NilOptionalFields(t *testing.T) {
+	tmpl := `{{.BeadID}}{{if .Epic}} in {{.Epic.ID}}{{end}}{{with .History}} attempt {{.Attempts}}{{end}}`
+
+	got, err := RenderPrompt("test", tmpl, PromptContext{PromptVars: PromptVars{BeadID: "bd-1"}})
+	if err != nil {
+		t.Fatalf("RenderPrompt() error = %v", err)
+	}
+	if got != "bd-1" {
+		t.Errorf("RenderPrompt() = %q, want %q", got, "bd-1")
+	}
+}
+
+func TestRenderPrompt_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"syntax", "{{.BeadID", "parse prompt"},
+		{"unknown field", "{{.BeadNmae}}", "render prompt"},
+		{"unknown function", "{{exec .BeadID}}", "parse prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderPrompt("test", tt.tmpl, samplePromptContext())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("RenderPrompt() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptFuncs(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{truncate 8 "hello world"}}`, "hello..."},
+		{`{{truncate 20 "short"}}`, "short"},
+		{`{{quote "say \"hi\""}}`, `"say \"hi\""`},
+		{`{{shellquote "it's"}}`, `'it'\''s'`},
+		{`{{default "none" ""}}`, "none"},
+		{`{{default "none" "set"}}`, "set"},
+		{`{{.BeadDescription | truncate 6}}`, "Sam..."},
+	}
+	for _, tt := range tests {
+		got, err := RenderPrompt("test", tt.tmpl, samplePromptContext())
+		if err != nil {
+			t.Errorf("RenderPrompt(%s) error = %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RenderPrompt(%s) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "simple mode ignores template syntax",
+			cfg:  Config{PromptMode: PromptModeSimple, Prompt: "{{.Unknown"},
+		},
+		{
+			name: "template mode valid",
+			cfg: Config{
+				PromptMode: PromptModeTemplate,
+				Prompt:     "{{.BeadID}} {{.Bead.Notes}}",
+				Prompts:    []PromptRule{{Name: "bugs", IssueType: "bug", Prompt: "{{with .Epic}}{{.Title}}{{end}}"}},
+			},
+		},
+		{
+			name:    "template mode bad default",
+			cfg:     Config{PromptMode: PromptModeTemplate, Prompt: "{{.Bead.Nots}}"},
+			wantErr: "render prompt default",
+		},
+		{
+			name:    "template mode unguarded epic",
+			cfg:     Config{PromptMode: PromptModeTemplate, Prompt: "Epic: {{.Epic.Title}}"},
+			wantErr: "no epic, dependencies or history",
+		},
+		{
+			name:    "template mode unguarded history",
+			cfg:     Config{PromptMode: PromptModeTemplate, Prompt: "{{.History.LastError}}"},
+			wantErr: "render prompt default",
+		},
+		{
+			name: "template mode bad rule",
+			cfg: Config{
+				PromptMode: PromptModeTemplate,
+				Prompt:     "{{.BeadID}}",
+				Prompts:    []PromptRule{{Name: "bugs", IssueType: "bug", Prompt: "{{if}}"}},
+			},
+			wantErr: "parse prompt bugs",
+		},
+		{
+			name:    "missing prompt file",
+			cfg:     Config{PromptFile: "/nonexistent/prompt.md"},
+			wantErr: "prompt",
+		},
+		{
+			name:    "unknown mode",
+			cfg:     Config{PromptMode: "jinja", Prompt: "x"},
+			wantErr: "unknown prompt_mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.ValidatePrompts()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePrompts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidatePrompts() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
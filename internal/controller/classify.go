@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// Text in the agent's stderr or error result that identifies a failure
+// class, matched case-insensitively. The patterns follow the shape of the
+// CLI's own messages, "API Error: <status> <body>" with the API's error type
+// in the body, rather than loose words a bead's own work might mention.
+var (
+	rateLimitPatterns = []string{
+		"api error: 429", `"rate_limit_error"`, "claude ai usage limit reached",
+	}
+	authPatterns = []string{
+		"api error: 401", "api error: 403", `"authentication_error"`, `"permission_error"`,
+		"please run /login", "oauth token has expired", "credit balance is too low",
+	}
+	transientPatterns = []string{
+		"api error: 5", `"overloaded_error"`, `"api_error"`, "api error: connection error",
+		"api error: request timed out", "econnreset", "econnrefused", "etimedout",
+	}
+)
+
+// classifySession works out what kind of failure ended a session: a
+// timeout if the inactivity watchdog stopped it, a rate limit, login or
+// transient API problem if its stderr or error result says so, giving up if
+// it ran out of turns, and otherwise a crash. The result is only read when
+// it reports an error, since otherwise it is the agent's own summary. A
+// process killed by a signal other than the watchdog's is treated as
+// transient, since nothing about the bead caused it.
+func classifySession(timedOut bool, stderr string, waitErr error, result *events.SessionEndEvent) workqueue.FailureClass {
+	if timedOut {
+		return workqueue.FailureTimeout
+	}
+
+	text := stderr
+	if result != nil && result.IsError {
+		text += "\n" + result.Result
+	}
+	if class := classifyText(text); class != "" {
+		return class
+	}
+
+	if result != nil && result.Subtype == "error_max_turns" {
+		return workqueue.FailureGaveUp
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(waitErr, &exitErr) && exitErr.ExitCode() == -1 {
+		return workqueue.FailureTransient
+	}
+	return workqueue.FailureCrash
+}
+
+// resultFailure classifies a session that exited cleanly but reported an
+// API error in its result: a rate limit, login or transient problem that
+// the bead's follow-up session would run into as well. It returns "" for
+// any other result.
+func resultFailure(result *events.SessionEndEvent) workqueue.FailureClass {
+	if result == nil || !result.IsError {
+		return ""
+	}
+	return classifyText(result.Result)
+}
+
+// classifyText matches text against the rate limit, login and transient
+// error patterns, in that order. It returns "" if none match.
+func classifyText(text string) workqueue.FailureClass {
+	text = strings.ToLower(text)
+	matches := func(patterns []string) bool {
+		for _, p := range patterns {
+			if strings.Contains(text, p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case matches(rateLimitPatterns):
+		return workqueue.FailureRateLimit
+	case matches(authPatterns):
+		return workqueue.FailureAuth
+	case matches(transientPatterns):
+		return workqueue.FailureTransient
+	}
+	return ""
+}
@@ -4,21 +4,30 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/npratt/atari/internal/bdactivity"
+	"github.com/npratt/atari/internal/beadbranch"
 	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/budget"
 	"github.com/npratt/atari/internal/config"
 	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/history"
 	"github.com/npratt/atari/internal/observer"
 	"github.com/npratt/atari/internal/runner"
+	"github.com/npratt/atari/internal/schedule"
 	"github.com/npratt/atari/internal/session"
+	"github.com/npratt/atari/internal/transcript"
+	"github.com/npratt/atari/internal/verify"
 	"github.com/npratt/atari/internal/viewmodel"
 	"github.com/npratt/atari/internal/workqueue"
+	"github.com/npratt/atari/internal/worktree"
 )
 
 // State represents the controller's current state.
@@ -38,11 +47,15 @@ const (
 const (
 	StallTypeAbandoned = "abandoned"
 	StallTypeReview    = "review"
+	StallTypeBudget    = "budget"
 )
 
 // Actor identification for filtering bead creations.
 const atariDrainActor = "atari-drain"
 
+// gitTimeout bounds the git commands run for the branch-per-bead workflow.
+const gitTimeout = 2 * time.Minute
+
 // Debounce settings for bead creation detection.
 const (
 	creationDebounceInterval = 300 * time.Millisecond
@@ -77,25 +90,47 @@ type Controller struct {
 	state   State
 	stateMu sync.RWMutex
 
-	currentBead      string
-	currentBeadTitle string
-	currentBeadStart time.Time
-	beadMu           sync.RWMutex
+	// Worker pool (one entry per configured worker)
+	workers    []*worker
+	workerDone chan struct{} // signalled when a pooled worker finishes a bead
+	poolMu     sync.Mutex    // serializes worker dispatch with idle detection
+	worktrees  *worktree.Manager
+
+	// Branch-per-bead workflow (optional, enabled by config.Git.BranchPerBead)
+	branches *beadbranch.Manager
+
+	// Spending against configured cost limits
+	budget *budget.Tracker
+
+	// Post-close verification commands (optional, enabled by config.Verify.Commands)
+	verifier *verify.Verifier
+
+	// Coding agent that runs sessions (default: the Claude CLI)
+	backend session.AgentBackend
+
+	// Raw session output for auditing (optional, enabled by config.Transcripts.Enabled)
+	transcripts *transcript.Store
+
+	// Windows when work may start (optional, enabled by config.Schedule.Windows)
+	schedule       *schedule.Schedule
+	scheduledPause bool // paused because a window closed (protected by scheduleMu)
+	scheduleMu     sync.Mutex
+	now            func() time.Time
+
+	// Resumes a drain paused by a failure's retry policy (protected by retryMu)
+	retryResume *time.Timer
+	retryMu     sync.Mutex
 
 	// Stalled state context (protected by stallMu)
 	stalledBeadID       string
 	stalledBeadTitle    string
 	stallReason         string
 	stalledAt           time.Time
-	stallType           string   // "abandoned" or "review"
-	stalledCreatedBeads []string // bead IDs created during session (for review stalls)
+	stallType           string         // "abandoned", "review", or "budget"
+	stalledCreatedBeads []string       // bead IDs created during session (for review stalls)
+	stalledBreach       *budget.Breach // limit that caused a budget stall
 	stallMu             sync.RWMutex
 
-	// Created beads tracking (protected by createdBeadsMu)
-	createdBeadsDuringSession []string
-	createdBeadsMu            sync.Mutex
-	beadEventChan             <-chan events.Event
-
 	ctx      context.Context
 	cancel   context.CancelFunc
 	cancelMu sync.Mutex
@@ -115,13 +150,17 @@ type Controller struct {
 	totalCostUSD float64
 	startTime    time.Time
 
-	// Session progress tracking
-	currentTurnCount int
-	currentTurnMu    sync.RWMutex
+	// Drain-until progress (protected by statsMu)
+	completedAtStart int       // beads completed before Run, excluded from max-beads
+	untilMet         *untilMet // condition that stopped the drain, if any
 
 	// Validated epic info (populated during startup if epic configured)
 	epicID    string
 	epicTitle string
+
+	// Beads to work on next, ahead of the work queue (protected by queueMu)
+	queued  []string
+	queueMu sync.Mutex
 }
 
 // ControllerOption configures a Controller.
@@ -134,6 +173,61 @@ func WithStateSink(sink *events.StateSink) ControllerOption {
 	}
 }
 
+// WithWorktrees sets the worktree manager used to isolate workers when
+// config.Workers is greater than 1.
+func WithWorktrees(m *worktree.Manager) ControllerOption {
+	return func(c *Controller) {
+		c.worktrees = m
+	}
+}
+
+// WithBranches enables the branch-per-bead workflow: each bead runs on its
+// own branch, which is merged into the target branch when the bead closes.
+func WithBranches(m *beadbranch.Manager) ControllerOption {
+	return func(c *Controller) {
+		c.branches = m
+	}
+}
+
+// WithVerifier enables the verification gate: a closed bead is only accepted
+// once the verifier's commands pass in the worker's directory.
+func WithVerifier(v *verify.Verifier) ControllerOption {
+	return func(c *Controller) {
+		c.verifier = v
+	}
+}
+
+// WithBackend sets the agent backend used to run sessions.
+func WithBackend(b session.AgentBackend) ControllerOption {
+	return func(c *Controller) {
+		c.backend = b
+	}
+}
+
+// WithTranscripts saves every session's raw output to the store.
+func WithTranscripts(s *transcript.Store) ControllerOption {
+	return func(c *Controller) {
+		c.transcripts = s
+	}
+}
+
+// WithPastSpending counts spending from earlier runs, read from the history
+// archive, against the epic and daily budgets.
+func WithPastSpending(records []history.Record) ControllerOption {
+	return func(c *Controller) {
+		c.budget.Restore(records)
+	}
+}
+
+// WithSchedule restricts work to the schedule's windows. The drain pauses
+// when a window closes, letting a running session finish its turn, and
+// resumes when the next one opens.
+func WithSchedule(s *schedule.Schedule) ControllerOption {
+	return func(c *Controller) {
+		c.schedule = s
+	}
+}
+
 // New creates a Controller with the given dependencies.
 // The processRunner parameter is optional - pass nil to disable BD activity watching.
 func New(cfg *config.Config, wq *workqueue.Manager, router *events.Router, brClient brclient.Client, processRunner runner.ProcessRunner, logger *slog.Logger, opts ...ControllerOption) *Controller {
@@ -154,7 +248,20 @@ func New(cfg *config.Config, wq *workqueue.Manager, router *events.Router, brCli
 		stopSignal:          make(chan struct{}, 1),
 		gracefulStopSignal:  make(chan struct{}, 1),
 		retrySignal:         make(chan struct{}, 1),
+		budget:              budget.New(cfg.Budget),
+		backend:             session.ClaudeBackend{},
+		now:                 time.Now,
+	}
+
+	// Build worker pool (always at least one worker)
+	numWorkers := cfg.Workers
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	for i := 1; i <= numWorkers; i++ {
+		c.workers = append(c.workers, newWorker(i))
 	}
+	c.workerDone = make(chan struct{}, numWorkers)
 
 	// Apply options
 	for _, opt := range opts {
@@ -184,12 +291,29 @@ func (c *Controller) Run(ctx context.Context) error {
 		return err
 	}
 
+	// Prepare worker worktrees if running more than one worker
+	if err := c.prepareWorktrees(c.ctx); err != nil {
+		return err
+	}
+
+	// Resolve the merge target for the branch-per-bead workflow
+	if c.branches != nil {
+		if err := c.branches.ResolveTarget(c.ctx); err != nil {
+			return fmt.Errorf("branch per bead: %w", err)
+		}
+	}
+
 	// Restore active top-level from persisted state (for top-level selection mode)
 	c.restoreActiveTopLevel()
 
 	// Restore stall context from persisted state (if any)
 	c.restoreStallContext()
 
+	// Count max-beads from here, after any restored history
+	c.statsMu.Lock()
+	c.completedAtStart = c.workQueue.Stats().Completed
+	c.statsMu.Unlock()
+
 	// Start BD activity watcher if configured (best-effort, non-fatal)
 	if c.bdWatcher != nil {
 		if err := c.bdWatcher.Start(c.ctx); err != nil {
@@ -200,6 +324,13 @@ func (c *Controller) Run(ctx context.Context) error {
 		}
 	}
 
+	// Pause and resume with the schedule's windows
+	if c.schedule != nil {
+		scheduleCtx, stopSchedule := context.WithCancel(c.ctx)
+		defer stopSchedule()
+		go c.watchSchedule(scheduleCtx)
+	}
+
 	// Get working directory for DrainStartEvent
 	workDir := "."
 
@@ -233,7 +364,11 @@ func (c *Controller) Run(ctx context.Context) error {
 		case StateIdle:
 			c.runIdle()
 		case StateWorking:
-			// This state is handled within runIdle after selecting a bead
+			// A single worker is handled within runIdle after selecting a bead.
+			// A worker pool keeps dispatching while workers run.
+			if c.pooled() {
+				c.runIdle()
+			}
 		case StatePaused:
 			c.runPaused()
 		case StateStalled:
@@ -255,9 +390,14 @@ func (c *Controller) runIdle() {
 		c.logger.Info("paused while idle")
 		return
 	case <-c.gracefulPauseSignal:
-		// When idle, graceful pause acts like regular pause
+		// When idle, graceful pause acts like regular pause.
+		// Busy pooled workers stop their sessions at the next turn boundary.
 		c.setState(StatePaused)
-		c.logger.Info("paused while idle (graceful)")
+		if n := c.pauseWorkers(); n > 0 {
+			c.logger.Info("paused with workers stopping at turn boundary", "active_workers", n)
+		} else {
+			c.logger.Info("paused while idle (graceful)")
+		}
 		return
 	case <-c.stopSignal:
 		c.setState(StateStopping)
@@ -272,6 +412,24 @@ func (c *Controller) runIdle() {
 	default:
 	}
 
+	// Stop once a drain-until condition is met, letting running beads finish
+	if met := c.checkUntil(); met != nil {
+		c.stopUntil(met)
+		return
+	}
+
+	// Start no work outside the schedule's windows
+	if c.outsideSchedule() {
+		return
+	}
+
+	// Wait for a free worker before polling for more work
+	w := c.idleWorker()
+	if w == nil || c.atBeadLimit() {
+		c.waitForWorker(c.config.WorkQueue.PollInterval)
+		return
+	}
+
 	// Poll for work using appropriate selection method.
 	// Epic flag takes precedence (handled within workqueue.Next via config).
 	// Selection mode only matters when no epic is specified.
@@ -292,7 +450,8 @@ func (c *Controller) runIdle() {
 		c.logger.Debug("no bead selected", "reason", reason.String())
 
 		// If all beads hit max failures, enter stalled state
-		if reason == workqueue.ReasonMaxFailure {
+		// (only once no other worker is still making progress)
+		if reason == workqueue.ReasonMaxFailure && c.busyWorkers() == 0 {
 			stalledBead := c.findStalledBead()
 			if stalledBead != nil {
 				lastError := ""
@@ -308,18 +467,34 @@ func (c *Controller) runIdle() {
 
 		// Try to close any eligible epics
 		go c.closeEligibleEpics("")
-		c.sleep(c.config.WorkQueue.PollInterval)
+		c.waitForWorker(c.config.WorkQueue.PollInterval)
+		return
+	}
+
+	// Stall instead of starting more work once an epic or daily budget is spent
+	epicID := c.budgetEpic(bead)
+	if breach := c.budget.Check(epicID); breach != nil {
+		c.workQueue.Unclaim(bead.ID)
+		c.triggerBudgetStall(breach)
 		return
 	}
 
 	// Work available - run session
-	c.runWorkingOnBead(bead)
+	if c.pooled() {
+		c.startWorker(w, bead, epicID)
+		return
+	}
+	c.runWorkingOnBead(w, bead, epicID)
 }
 
 // selectNextBead uses the appropriate selection method based on configuration.
-// Epic flag takes precedence over selection mode.
+// Beads queued with QueueBead come first. Epic flag takes precedence over selection mode.
 // Returns the selected bead, a reason why no bead was selected (if nil), and any error.
 func (c *Controller) selectNextBead() (*workqueue.Bead, workqueue.SelectionReason, error) {
+	if bead := c.nextQueuedBead(); bead != nil {
+		return bead, workqueue.ReasonSuccess, nil
+	}
+
 	// If epic is configured, use global selection (workqueue.Next already filters by epic)
 	if c.config.WorkQueue.Epic != "" {
 		return c.workQueue.Next(c.ctx)
@@ -380,11 +555,11 @@ func (c *Controller) getActiveTopLevelTitle(id string) string {
 	return bead.Title
 }
 
-// getBeadParent fetches the parent ID for a bead.
-// Returns empty string on error or if no parent exists.
-func (c *Controller) getBeadParent(beadID string) string {
+// showBead fetches full details for a bead, including notes and dependencies.
+// Returns nil if the bead cannot be fetched.
+func (c *Controller) showBead(beadID string) *brclient.Bead {
 	if c.brClient == nil {
-		return ""
+		return nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -392,14 +567,19 @@ func (c *Controller) getBeadParent(beadID string) string {
 
 	bead, err := c.brClient.Show(ctx, beadID)
 	if err != nil {
-		c.logger.Warn("failed to get bead parent", "bead_id", beadID, "error", err)
-		return ""
-	}
-	if bead == nil {
-		return ""
+		c.logger.Warn("failed to show bead", "bead_id", beadID, "error", err)
+		return nil
 	}
+	return bead
+}
 
-	return bead.Parent
+// getBeadParent fetches the parent ID for a bead.
+// Returns empty string if the bead has no parent or on error.
+func (c *Controller) getBeadParent(beadID string) string {
+	if bead := c.showBead(beadID); bead != nil {
+		return bead.Parent
+	}
+	return ""
 }
 
 // restoreActiveTopLevel restores the active top-level from persisted state.
@@ -476,6 +656,25 @@ func (c *Controller) restoreStallContext() {
 		return
 	}
 
+	// Budget stalls are restored as-is: the drain stays stalled until the
+	// user resumes, whatever the limits or recorded spending are now.
+	if state.StallType == StallTypeBudget {
+		c.stallMu.Lock()
+		c.stalledBeadID = ""
+		c.stalledBeadTitle = ""
+		c.stallReason = state.StallReason
+		c.stalledAt = state.StalledAt
+		c.stallType = state.StallType
+		c.stalledCreatedBeads = nil
+		c.stallMu.Unlock()
+
+		c.setState(StateStalled)
+
+		c.logger.Info("restored budget stall context from state",
+			"reason", state.StallReason)
+		return
+	}
+
 	if state.StalledBeadID == "" {
 		return
 	}
@@ -533,17 +732,77 @@ func (c *Controller) verifyTopLevelHasReadyWork(topLevelID string) bool {
 	return hasEligible
 }
 
-// runWorkingOnBead executes a Claude session for the given bead.
-func (c *Controller) runWorkingOnBead(bead *workqueue.Bead) {
+// runWorkingOnBead executes a Claude session for the given bead on the
+// single worker and transitions state based on pending signals.
+func (c *Controller) runWorkingOnBead(w *worker, bead *workqueue.Bead, epicID string) {
 	c.setState(StateWorking)
-	c.setCurrentBead(bead.ID, bead.Title)
-	defer c.clearCurrentBead()
+	c.runBead(w, bead, epicID)
+
+	// Transition based on pending signals
+	select {
+	case <-c.stopSignal:
+		c.setState(StateStopping)
+		return
+	case <-c.gracefulStopSignal:
+		c.setState(StateStopping)
+		c.logger.Info("stopping after iteration (graceful)")
+		return
+	case <-c.pauseSignal:
+		c.setState(StatePaused)
+		c.logger.Info("paused after iteration")
+		return
+	case <-c.gracefulPauseSignal:
+		// Graceful pause was requested but session ended naturally
+		c.setState(StatePaused)
+		c.logger.Info("paused after iteration (graceful)")
+		return
+	default:
+		c.setState(StateIdle)
+	}
+}
+
+// startWorker runs the bead on a pooled worker in the background.
+// The controller stays in the working state until every worker is idle.
+func (c *Controller) startWorker(w *worker, bead *workqueue.Bead, epicID string) {
+	c.poolMu.Lock()
+	// Reserve the worker before the goroutine starts so it is not picked again
+	w.setBead(bead.ID, bead.Title)
+	c.compareAndSetState(StateIdle, StateWorking)
+	c.poolMu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.runBead(w, bead, epicID)
+
+		c.poolMu.Lock()
+		if c.busyWorkers() == 0 {
+			c.compareAndSetState(StateWorking, StateIdle)
+		}
+		c.poolMu.Unlock()
+
+		select {
+		case c.workerDone <- struct{}{}:
+		default:
+		}
+	}()
+}
+
+// runBead executes a Claude session for the given bead on the worker and
+// records the outcome. Spending is counted against epicID's budget.
+// It does not change the controller state.
+func (c *Controller) runBead(w *worker, bead *workqueue.Bead, epicID string) {
+	w.setBead(bead.ID, bead.Title)
+	w.setEpic(epicID)
+	defer w.clearBead()
+	defer c.workQueue.Release(bead.ID)
 	iteration := c.incrementIteration()
 
 	c.logger.Info("starting iteration",
 		"iteration", iteration,
 		"bead_id", bead.ID,
 		"title", bead.Title,
+		"worker", w.id,
 	)
 
 	// Get attempt count from history
@@ -565,50 +824,45 @@ func (c *Controller) runWorkingOnBead(bead *workqueue.Bead) {
 		Attempt:       attempt,
 		TopLevelID:    topLevelID,
 		TopLevelTitle: topLevelTitle,
+		EpicID:        epicID,
 	})
 
+	// Save the raw output of this attempt's sessions
+	c.openTranscript(w, bead.ID, attempt)
+	defer c.closeTranscript(w)
+
 	startTime := time.Now()
 
+	// Move the worker onto the bead's branch before the session commits.
+	// Running on whatever is checked out instead would mix the bead's
+	// commits into another bead's branch.
+	if err := c.startBeadBranch(w, bead); err != nil {
+		c.handleSessionError(bead, err, time.Since(startTime))
+		return
+	}
+	defer c.leaveBeadBranch(w, bead)
+
 	// Run the session
-	result, err := c.runSession(bead)
+	result, err := c.runSession(w, bead)
 
 	duration := time.Since(startTime)
 
 	// Handle session outcome using extracted helpers
 	if err != nil {
 		c.handleSessionError(bead, err, duration)
+	} else if result.Budget != nil {
+		c.accumulateCost(result.TotalCostUSD)
+		c.handleBudgetExceeded(bead, result.Budget, result.NumTurns, result.TotalCostUSD, duration)
 	} else if result.GracefulPause {
 		c.handleGracefulPause(bead, result, duration)
 	} else if c.isBeadClosed(bead.ID) {
-		c.handleBeadClosed(bead, result, duration)
+		c.handleBeadClosed(w, bead, result, duration)
 	} else {
-		c.handleFollowUp(bead, result, duration)
+		c.handleFollowUp(w, bead, result, duration)
 	}
 
 	// Check for eager switching to higher priority work (after successful completion)
 	c.checkEagerSwitch()
-
-	// Transition based on pending signals
-	select {
-	case <-c.stopSignal:
-		c.setState(StateStopping)
-		return
-	case <-c.gracefulStopSignal:
-		c.setState(StateStopping)
-		c.logger.Info("stopping after iteration (graceful)")
-		return
-	case <-c.pauseSignal:
-		c.setState(StatePaused)
-		c.logger.Info("paused after iteration")
-		return
-	case <-c.gracefulPauseSignal:
-		// Graceful pause was requested but session ended naturally
-		c.setState(StatePaused)
-		c.logger.Info("paused after iteration (graceful)")
-		return
-	default:
-		c.setState(StateIdle)
-	}
 }
 
 // handleSessionError records failure outcome when a session encounters an error.
@@ -618,7 +872,7 @@ func (c *Controller) handleSessionError(bead *workqueue.Bead, err error, duratio
 		"error", err,
 		"duration", duration,
 	)
-	c.workQueue.RecordFailure(bead.ID, err)
+	c.recordFailure(bead, err)
 
 	// Check if bead was abandoned
 	history := c.workQueue.History()
@@ -663,14 +917,27 @@ func (c *Controller) handleGracefulPause(bead *workqueue.Bead, result *SessionRe
 }
 
 // handleBeadClosed records success when the main session closed the bead.
-func (c *Controller) handleBeadClosed(bead *workqueue.Bead, result *SessionResult, duration time.Duration) {
+func (c *Controller) handleBeadClosed(w *worker, bead *workqueue.Bead, result *SessionResult, duration time.Duration) {
 	c.logger.Info("session completed and bead closed",
 		"bead_id", bead.ID,
 		"duration", duration,
 	)
+
+	if err := c.verifyBead(w, bead); err != nil {
+		c.accumulateCost(result.TotalCostUSD)
+		c.handleVerifyFailure(bead, err, result.NumTurns, result.TotalCostUSD, duration)
+		return
+	}
+
+	if err := c.mergeBeadBranch(w, bead); err != nil {
+		c.accumulateCost(result.TotalCostUSD)
+		c.handleMergeFailure(w, bead, err, result.NumTurns, result.TotalCostUSD, duration)
+		return
+	}
+
 	c.workQueue.RecordSuccess(bead.ID)
 
-	go c.closeEligibleEpics(bead.ID)
+	c.goCloseEligibleEpics(bead.ID)
 
 	c.accumulateCost(result.TotalCostUSD)
 
@@ -687,15 +954,115 @@ func (c *Controller) handleBeadClosed(bead *workqueue.Bead, result *SessionResul
 	// Wait for debounce to catch any final events
 	c.waitForCreationDebounce()
 
-	createdBeads := c.getCreatedBeads()
+	createdBeads := w.getCreatedBeads()
 	if len(createdBeads) > 0 {
 		reason := fmt.Sprintf("new bead(s) created for review: %s", strings.Join(createdBeads, ", "))
 		c.triggerReviewStall(createdBeads, reason)
 	}
 }
 
+// handleMergeFailure reopens a closed bead whose branch could not be merged
+// into the target branch, leaving a note so the next attempt or a human can
+// resolve it. The bead branch is kept with its commits.
+func (c *Controller) handleMergeFailure(w *worker, bead *workqueue.Bead, mergeErr error, numTurns int, totalCost float64, duration time.Duration) {
+	branch := w.currentBranch()
+	c.logger.Warn("failed to merge bead branch",
+		"bead_id", bead.ID,
+		"branch", branch,
+		"target", c.branches.Target(),
+		"error", mergeErr,
+	)
+
+	notes := fmt.Sprintf("Atari: could not merge %s into %s: %v. Resetting to open; the branch keeps the previous work and must be rebased onto %s.",
+		branch, c.branches.Target(), mergeErr, c.branches.Target())
+	if resetErr := c.resetBeadToOpen(bead.ID, notes); resetErr != nil {
+		c.logger.Error("failed to reset bead to open",
+			"bead_id", bead.ID,
+			"error", resetErr,
+		)
+	}
+
+	failErr := fmt.Errorf("merge into %s failed", c.branches.Target())
+	c.recordFailure(bead, failErr)
+
+	c.emit(&events.IterationEndEvent{
+		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
+		BeadID:       bead.ID,
+		Success:      false,
+		NumTurns:     numTurns,
+		DurationMs:   duration.Milliseconds(),
+		TotalCostUSD: totalCost,
+		Error:        failErr.Error(),
+	})
+}
+
+// handleVerifyFailure reopens a closed bead whose verification commands
+// failed, attaching the command output as notes for the next attempt.
+func (c *Controller) handleVerifyFailure(bead *workqueue.Bead, verifyErr error, numTurns int, totalCost float64, duration time.Duration) {
+	c.logger.Warn("verification failed after close",
+		"bead_id", bead.ID,
+		"error", verifyErr,
+	)
+
+	notes := fmt.Sprintf("Atari: verification failed after close: %v. Resetting to open.", verifyErr)
+	var failure *verify.Failure
+	if errors.As(verifyErr, &failure) && failure.Output != "" {
+		notes += "\n\nOutput of " + failure.Command + ":\n" + failure.Output
+	}
+	if resetErr := c.resetBeadToOpen(bead.ID, notes); resetErr != nil {
+		c.logger.Error("failed to reset bead to open",
+			"bead_id", bead.ID,
+			"error", resetErr,
+		)
+	}
+
+	failErr := fmt.Errorf("verification failed: %w", verifyErr)
+	c.recordFailure(bead, failErr)
+
+	c.emit(&events.IterationEndEvent{
+		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
+		BeadID:       bead.ID,
+		Success:      false,
+		NumTurns:     numTurns,
+		DurationMs:   duration.Milliseconds(),
+		TotalCostUSD: totalCost,
+		Error:        failErr.Error(),
+	})
+}
+
+// handleBudgetExceeded reopens a bead that reached its per-bead budget and
+// skips it for the rest of the drain. Retrying the bead grants a fresh budget.
+func (c *Controller) handleBudgetExceeded(bead *workqueue.Bead, breach *budget.Breach, numTurns int, totalCost float64, duration time.Duration) {
+	c.logger.Warn("bead over budget, stopping work",
+		"bead_id", bead.ID,
+		"spent_usd", breach.SpentUSD,
+		"limit_usd", breach.LimitUSD,
+	)
+
+	notes := fmt.Sprintf("Atari: stopped work because %s. Resetting to open; retry the bead or raise budget.per_bead_usd to continue.", breach)
+	if resetErr := c.resetBeadToOpen(bead.ID, notes); resetErr != nil {
+		c.logger.Error("failed to reset bead to open",
+			"bead_id", bead.ID,
+			"error", resetErr,
+		)
+	}
+
+	budgetErr := fmt.Errorf("%s", breach)
+	c.workQueue.RecordStopped(bead.ID, budgetErr)
+
+	c.emit(&events.IterationEndEvent{
+		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
+		BeadID:       bead.ID,
+		Success:      false,
+		NumTurns:     numTurns,
+		DurationMs:   duration.Milliseconds(),
+		TotalCostUSD: totalCost,
+		Error:        budgetErr.Error(),
+	})
+}
+
 // handleFollowUp manages the follow-up session when the main session didn't close the bead.
-func (c *Controller) handleFollowUp(bead *workqueue.Bead, mainResult *SessionResult, duration time.Duration) {
+func (c *Controller) handleFollowUp(w *worker, bead *workqueue.Bead, mainResult *SessionResult, duration time.Duration) {
 	c.logger.Warn("session completed but bead not closed, attempting follow-up",
 		"bead_id", bead.ID,
 		"duration", duration,
@@ -703,7 +1070,14 @@ func (c *Controller) handleFollowUp(bead *workqueue.Bead, mainResult *SessionRes
 
 	totalCost := mainResult.TotalCostUSD
 
-	followUpClosed, followUpResult, followUpErr := c.runFollowUpSession(bead)
+	// Don't spend more on a bead that is already over its budget
+	if breach := c.budget.CheckBead(bead.ID, 0); breach != nil {
+		c.accumulateCost(totalCost)
+		c.handleBudgetExceeded(bead, breach, mainResult.NumTurns, totalCost, duration)
+		return
+	}
+
+	followUpClosed, followUpResult, followUpErr := c.runFollowUpSession(w, bead)
 
 	if followUpResult != nil {
 		totalCost += followUpResult.TotalCostUSD
@@ -711,7 +1085,7 @@ func (c *Controller) handleFollowUp(bead *workqueue.Bead, mainResult *SessionRes
 	c.accumulateCost(totalCost)
 
 	if followUpClosed {
-		c.handleFollowUpSuccess(bead, mainResult, followUpResult, totalCost, duration)
+		c.handleFollowUpSuccess(w, bead, mainResult, followUpResult, totalCost, duration)
 	} else if followUpErr == nil && c.getBeadStatus(bead.ID) == "open" {
 		c.handleFollowUpResetToOpen(bead, mainResult, followUpResult, totalCost, duration)
 	} else {
@@ -720,14 +1094,25 @@ func (c *Controller) handleFollowUp(bead *workqueue.Bead, mainResult *SessionRes
 }
 
 // handleFollowUpSuccess records success when the follow-up session closed the bead.
-func (c *Controller) handleFollowUpSuccess(bead *workqueue.Bead, mainResult, followUpResult *SessionResult, totalCost float64, duration time.Duration) {
+func (c *Controller) handleFollowUpSuccess(w *worker, bead *workqueue.Bead, mainResult, followUpResult *SessionResult, totalCost float64, duration time.Duration) {
 	c.logger.Info("follow-up session closed bead",
 		"bead_id", bead.ID,
 		"total_duration", duration,
 	)
+
+	if err := c.verifyBead(w, bead); err != nil {
+		c.handleVerifyFailure(bead, err, mainResult.NumTurns+followUpResult.NumTurns, totalCost, duration)
+		return
+	}
+
+	if err := c.mergeBeadBranch(w, bead); err != nil {
+		c.handleMergeFailure(w, bead, err, mainResult.NumTurns+followUpResult.NumTurns, totalCost, duration)
+		return
+	}
+
 	c.workQueue.RecordSuccess(bead.ID)
 
-	go c.closeEligibleEpics(bead.ID)
+	c.goCloseEligibleEpics(bead.ID)
 
 	c.emit(&events.IterationEndEvent{
 		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
@@ -741,7 +1126,7 @@ func (c *Controller) handleFollowUpSuccess(bead *workqueue.Bead, mainResult, fol
 	// Wait for debounce to catch any final events
 	c.waitForCreationDebounce()
 
-	createdBeads := c.getCreatedBeads()
+	createdBeads := w.getCreatedBeads()
 	if len(createdBeads) > 0 {
 		reason := fmt.Sprintf("new bead(s) created for review: %s", strings.Join(createdBeads, ", "))
 		c.triggerReviewStall(createdBeads, reason)
@@ -753,8 +1138,8 @@ func (c *Controller) handleFollowUpResetToOpen(bead *workqueue.Bead, mainResult,
 	c.logger.Info("follow-up reset bead to open for retry",
 		"bead_id", bead.ID,
 	)
-	incompleteErr := fmt.Errorf("bead reset to open for retry")
-	c.workQueue.RecordFailure(bead.ID, incompleteErr)
+	incompleteErr := workqueue.Classify(workqueue.FailureGaveUp, fmt.Errorf("bead reset to open for retry"))
+	c.recordFailure(bead, incompleteErr)
 
 	c.emit(&events.IterationEndEvent{
 		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
@@ -781,8 +1166,13 @@ func (c *Controller) handleFollowUpFailure(bead *workqueue.Bead, mainResult *Ses
 		)
 	}
 
-	incompleteErr := fmt.Errorf("session and follow-up both failed to close bead")
-	c.workQueue.RecordFailure(bead.ID, incompleteErr)
+	// A follow-up that failed outright keeps its class; otherwise the agent gave up
+	class := workqueue.FailureGaveUp
+	if followUpErr != nil {
+		class = workqueue.ClassOf(followUpErr)
+	}
+	incompleteErr := workqueue.Classify(class, fmt.Errorf("session and follow-up both failed to close bead"))
+	c.recordFailure(bead, incompleteErr)
 
 	c.emit(&events.IterationEndEvent{
 		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
@@ -916,23 +1306,32 @@ func (c *Controller) findHigherPriorityTopLevel(currentID string) string {
 type SessionResult struct {
 	NumTurns      int
 	TotalCostUSD  float64
-	GracefulPause bool   // true if session was paused gracefully (work not complete)
-	SessionID     string // Claude session ID for resume capability
+	GracefulPause bool           // true if session was paused gracefully (work not complete)
+	SessionID     string         // Claude session ID for resume capability
+	Budget        *budget.Breach // set if the session was stopped for exceeding the per-bead budget
 }
 
-// runSession executes a single Claude session for the bead.
-func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
+// runSession executes a single Claude session for the bead on the worker.
+func (c *Controller) runSession(w *worker, bead *workqueue.Bead) (*SessionResult, error) {
 	// Reset turn count at session start
-	c.currentTurnMu.Lock()
-	c.currentTurnCount = 0
-	c.currentTurnMu.Unlock()
+	w.setTurns(0)
 
 	// Subscribe to bead events and track created beads during session
-	c.clearCreatedBeads()
-	c.subscribeToBeadEvents()
-	defer c.unsubscribeFromBeadEvents()
+	w.clearCreatedBeads()
+	beadEvents := c.subscribeToBeadEvents(w)
+	defer c.unsubscribeFromBeadEvents(beadEvents)
+
+	// Choose the model for this bead; follow-ups and resumes reuse it
+	model := c.config.SelectModel(config.ModelTarget{
+		Priority:  bead.Priority,
+		Labels:    bead.Labels,
+		IssueType: bead.IssueType,
+		Failures:  c.previousFailures(bead.ID),
+	})
+	w.setModel(model)
+	c.workQueue.RecordModel(bead.ID, model)
 
-	sess := session.New(c.config, c.router)
+	sess := c.newSession(w, c.config)
 
 	// Check if this bead has a stored session ID for resume
 	if resumeID := c.getStoredSessionID(bead.ID); resumeID != "" {
@@ -942,15 +1341,23 @@ func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
 		sess.SetResumeID(resumeID)
 	}
 
-	// Load prompt template
-	promptTemplate, err := c.config.LoadPrompt()
+	// Fetch bead details for prompt selection and expansion
+	details := c.showBead(bead.ID)
+	var beadParent string
+	if details != nil {
+		beadParent = details.Parent
+	}
+
+	// Select the prompt template for this bead
+	promptName, promptTemplate, err := c.config.SelectPrompt(config.PromptTarget{
+		Labels:    bead.Labels,
+		IssueType: bead.IssueType,
+		Parent:    beadParent,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("load prompt: %w", err)
 	}
 
-	// Fetch bead parent for prompt expansion
-	beadParent := c.getBeadParent(bead.ID)
-
 	// Expand template variables
 	vars := config.PromptVars{
 		BeadID:          bead.ID,
@@ -959,7 +1366,15 @@ func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
 		Label:           c.config.WorkQueue.Label,
 		BeadParent:      beadParent,
 	}
-	prompt := config.ExpandPrompt(promptTemplate, vars)
+	prompt, err := c.expandPrompt(promptName, promptTemplate, bead, details, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Carry forward what went wrong on a retry
+	if section := c.previousAttempts(bead.ID); section != "" {
+		prompt += "\n\n" + section
+	}
 
 	c.wg.Add(1)
 	defer c.wg.Done()
@@ -968,7 +1383,10 @@ func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
 		BaseEvent: events.NewInternalEvent(events.EventSessionStart),
 		BeadID:    bead.ID,
 		Title:     bead.Title,
+		Prompt:    promptName,
+		Model:     model,
 	})
+	c.beginTranscript(w, bead.Title, promptName, prompt)
 
 	// Track if we're attempting to resume
 	attemptingResume := c.getStoredSessionID(bead.ID) != ""
@@ -981,7 +1399,7 @@ func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
 				"error", err)
 
 			// Create new session without resume ID
-			sess = session.New(c.config, c.router)
+			sess = c.newSession(w, c.config)
 			if err := sess.Start(c.ctx, prompt); err != nil {
 				return nil, fmt.Errorf("start session: %w", err)
 			}
@@ -990,24 +1408,45 @@ func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
 		}
 	}
 
-	// Check for graceful pause request and wire up turn boundary callback
-	select {
-	case <-c.gracefulPauseSignal:
-		sess.RequestPause()
-		c.logger.Info("graceful pause active for session", "bead_id", bead.ID)
-	default:
-		// No graceful pause requested
+	// Expose the session so pooled graceful pauses can reach it
+	w.setSession(sess)
+	defer w.setSession(nil)
+
+	// Check for graceful pause request and wire up turn boundary callback.
+	// A worker pool handles graceful pause in runIdle instead, so that one
+	// session cannot consume the signal meant for all of them.
+	if !c.pooled() {
+		select {
+		case <-c.gracefulPauseSignal:
+			sess.RequestPause()
+			c.logger.Info("graceful pause active for session", "bead_id", bead.ID)
+		default:
+			// No graceful pause requested
+		}
 	}
 
 	// Parse stream in goroutine
-	parser := session.NewParser(sess.Stdout(), c.router, sess)
+	parser := c.backend.NewDecoder(sess.Stdout(), c.router, sess)
+
+	// Track the live cost estimate until the session's spending is recorded
+	parser.SetOnCostUpdate(w.setSessionCost)
+	defer w.setSessionCost(0)
 
-	// Set up turn boundary callback for graceful pause and turn tracking
+	// Set up turn boundary callback for graceful pause, budget and turn tracking
+	var overBudget atomic.Pointer[budget.Breach]
 	parser.SetOnTurnComplete(func() {
-		// Update controller's turn count
-		c.currentTurnMu.Lock()
-		c.currentTurnCount = parser.TurnCount()
-		c.currentTurnMu.Unlock()
+		// Update worker's turn count
+		w.setTurns(parser.TurnCount())
+
+		// Stop once the bead's spending reaches its per-bead limit
+		if overBudget.Load() == nil {
+			if breach := c.budget.CheckBead(bead.ID, parser.EstimatedCost()); breach != nil {
+				overBudget.Store(breach)
+				c.logger.Info("stopping session at turn boundary, over budget", "bead_id", bead.ID)
+				sess.Stop()
+				return
+			}
+		}
 
 		// Check for graceful pause request
 		if sess.PauseRequested() {
@@ -1021,55 +1460,107 @@ func (c *Controller) runSession(bead *workqueue.Bead) (*SessionResult, error) {
 		parseDone <- parser.Parse()
 	}()
 
-	// Wait for session to complete
+	// Read the output to EOF before reaping the process: Wait closes the
+	// stdout pipe, losing any lines still unread from an agent that exits
+	// quickly
+	<-parseDone
 	waitErr := sess.Wait()
+	c.invalidateBeadCache()
 
-	// Wait for parser to finish
-	<-parseDone
+	// A budget stop is not an error; the bead is reopened by the caller
+	if breach := overBudget.Load(); breach != nil {
+		result := &SessionResult{Budget: breach}
+		fillSessionResult(result, parser)
+		c.recordSpend(w, bead, result.TotalCostUSD)
+		return result, nil
+	}
 
 	// If we stopped due to graceful pause, signal the controller to pause
-	// and don't treat the process termination as an error
+	// and don't treat the process termination as an error.
+	// A worker pool has already transitioned to paused when requesting it.
 	if sess.PauseRequested() {
-		select {
-		case c.pauseSignal <- struct{}{}:
-		default:
+		if !c.pooled() {
+			select {
+			case c.pauseSignal <- struct{}{}:
+			default:
+			}
 		}
 		// Graceful pause stops are not errors, but work is not complete
 		result := &SessionResult{GracefulPause: true}
-		if parserResult := parser.Result(); parserResult != nil {
-			result.NumTurns = parserResult.NumTurns
-			result.TotalCostUSD = parserResult.TotalCostUSD
-			result.SessionID = parserResult.SessionID
-		}
+		fillSessionResult(result, parser)
+		c.recordSpend(w, bead, result.TotalCostUSD)
 		return result, nil
 	}
 
 	if waitErr != nil {
-		// Include stderr in error message if available
-		stderr := sess.Stderr()
-		if stderr != "" {
-			return nil, fmt.Errorf("session error: %w\nstderr: %s", waitErr, stderr)
-		}
-		return nil, fmt.Errorf("session error: %w", waitErr)
+		// A failed session has no result, but its estimated spending still counts
+		cost := parser.EstimatedCost()
+		c.accumulateCost(cost)
+		c.recordSpend(w, bead, cost)
+
+		// Include the timeout cause and stderr in the error message if available
+		return nil, c.sessionError("session error", sess, waitErr, parser.Result())
 	}
 
 	// Retrieve session result from parser
 	result := &SessionResult{}
+	if parser.Result() == nil {
+		c.logger.Warn("session completed without result event", "bead_id", bead.ID)
+	}
+	fillSessionResult(result, parser)
+	c.recordSpend(w, bead, result.TotalCostUSD)
+
+	// A rate limit or API error would stop a follow-up session too
+	if class := resultFailure(parser.Result()); class != "" {
+		c.accumulateCost(result.TotalCostUSD)
+		return nil, workqueue.Classify(class, fmt.Errorf("session error: %s", parser.Result().Result))
+	}
+
+	return result, nil
+}
+
+// expandPrompt fills in a prompt template for a bead. In "template" mode the
+// template also sees the bead's full details, dependencies, attempt history
+// and parent epic; details may be nil if they could not be fetched.
+func (c *Controller) expandPrompt(name, tmpl string, bead, details *brclient.Bead, vars config.PromptVars) (string, error) {
+	if c.config.PromptMode != config.PromptModeTemplate {
+		return config.ExpandPrompt(tmpl, vars), nil
+	}
+
+	ctx := config.PromptContext{PromptVars: vars, Bead: bead}
+	if details != nil {
+		ctx.Bead = details
+		ctx.Dependencies = details.Dependencies
+	}
+	if h, ok := c.workQueue.History()[bead.ID]; ok {
+		ctx.History = h
+	}
+	if vars.BeadParent != "" {
+		ctx.Epic = c.showBead(vars.BeadParent)
+	}
+	return config.RenderPrompt(name, tmpl, ctx)
+}
+
+// fillSessionResult copies turns, cost and session ID from the parser's result
+// event. Without one (the session was stopped or ended early) the cost falls
+// back to the parser's live estimate.
+func fillSessionResult(result *SessionResult, parser session.StreamDecoder) {
 	if parserResult := parser.Result(); parserResult != nil {
 		result.NumTurns = parserResult.NumTurns
 		result.TotalCostUSD = parserResult.TotalCostUSD
 		result.SessionID = parserResult.SessionID
-	} else {
-		c.logger.Warn("session completed without result event", "bead_id", bead.ID)
+		return
 	}
-
-	return result, nil
+	result.TotalCostUSD = parser.EstimatedCost()
 }
 
 // runPaused waits for resume or stop signal.
 func (c *Controller) runPaused() {
 	select {
 	case <-c.resumeSignal:
+		// A manual resume ends a scheduled pause too, so a later manual
+		// pause is not resumed by the schedule
+		c.setScheduledPause(false)
 		c.setState(StateIdle)
 		c.logger.Info("resumed")
 	case <-c.stopSignal:
@@ -1097,9 +1588,11 @@ func (c *Controller) runStopping() {
 	}
 
 	// Emit stop event
+	reason, condition := c.stopReason()
 	c.emit(&events.DrainStopEvent{
 		BaseEvent: events.NewInternalEvent(events.EventDrainStop),
-		Reason:    "graceful stop completed",
+		Reason:    reason,
+		Condition: condition,
 	})
 
 	c.setState(StateStopped)
@@ -1162,6 +1655,7 @@ func (c *Controller) ForceStop() {
 
 // Pause requests the controller to pause after the current iteration.
 func (c *Controller) Pause() {
+	c.cancelRetryResume()
 	select {
 	case c.pauseSignal <- struct{}{}:
 		c.logger.Info("pause requested")
@@ -1183,6 +1677,7 @@ func (c *Controller) GracefulPause() {
 
 // Resume requests the controller to resume from paused state.
 func (c *Controller) Resume() {
+	c.cancelRetryResume()
 	select {
 	case c.resumeSignal <- struct{}{}:
 		c.logger.Info("resume requested")
@@ -1200,24 +1695,39 @@ func (c *Controller) State() State {
 type Stats struct {
 	Iteration    int
 	QueueStats   workqueue.QueueStats
-	CurrentBead  string
-	CurrentTurns int // turns completed in current session (0 if idle)
+	CurrentBead  string                 // first busy worker's bead ("" if idle)
+	CurrentTurns int                    // turns completed in that worker's session (0 if idle)
+	TotalCostUSD float64                // spending so far, including running sessions' estimates
+	Workers      []viewmodel.WorkerInfo // per-worker progress
+	BeadCache    *brclient.CacheStats   // bead read counters, nil when reads are not cached
 }
 
 // Stats returns current statistics.
 func (c *Controller) Stats() Stats {
-	c.currentTurnMu.RLock()
-	turns := c.currentTurnCount
-	c.currentTurnMu.RUnlock()
-
 	statsSnap := c.getStatsSnapshot()
 
-	return Stats{
+	stats := Stats{
 		Iteration:    statsSnap.Iteration,
 		QueueStats:   c.workQueue.Stats(),
 		CurrentBead:  c.CurrentBead(),
-		CurrentTurns: turns,
+		CurrentTurns: c.CurrentTurns(),
+		TotalCostUSD: statsSnap.TotalCostUSD + c.liveCost(),
+		Workers:      c.workerInfos(),
+	}
+	if cache, ok := c.brClient.(*brclient.CachingClient); ok {
+		cacheStats := cache.CacheStats()
+		stats.BeadCache = &cacheStats
+	}
+	return stats
+}
+
+// liveCost returns the summed cost estimates of all running sessions.
+func (c *Controller) liveCost() float64 {
+	var total float64
+	for _, w := range c.workers {
+		total += w.liveCost()
 	}
+	return total
 }
 
 // Iteration returns the current iteration count.
@@ -1243,11 +1753,13 @@ func (c *Controller) Abandoned() int {
 }
 
 // CurrentTurns returns the number of turns completed in the current session.
+// With multiple workers, this is the session of the first busy worker.
 // Returns 0 if no session is active.
 func (c *Controller) CurrentTurns() int {
-	c.currentTurnMu.RLock()
-	defer c.currentTurnMu.RUnlock()
-	return c.currentTurnCount
+	if w := c.currentWorker(); w != nil {
+		return w.turnCount()
+	}
+	return 0
 }
 
 // incrementIteration increments and returns the new iteration count (thread-safe).
@@ -1297,17 +1809,14 @@ func (c *Controller) GetStats() viewmodel.TUIStats {
 	queueStats := c.workQueue.Stats()
 	blockedBeads := c.workQueue.GetBlockedBeads()
 
-	c.currentTurnMu.RLock()
-	turns := c.currentTurnCount
-	c.currentTurnMu.RUnlock()
-
 	stats := viewmodel.TUIStats{
 		Completed:    queueStats.Completed,
 		Failed:       queueStats.Failed,
 		Abandoned:    queueStats.Abandoned,
 		InBackoff:    queueStats.InBackoff,
 		CurrentBead:  c.CurrentBead(),
-		CurrentTurns: turns,
+		CurrentTurns: c.CurrentTurns(),
+		Workers:      c.workerInfos(),
 	}
 
 	// Set TopBlockedBead if there are any blocked beads
@@ -1325,6 +1834,14 @@ func (c *Controller) GetStats() viewmodel.TUIStats {
 		stats.CreatedBeads = stallInfo.CreatedBeads
 	}
 
+	if status := c.ScheduleStatus(); status != nil {
+		stats.Schedule = &viewmodel.ScheduleInfo{
+			Open:   status.Open,
+			Window: status.Window,
+			Next:   status.Next,
+		}
+	}
+
 	return stats
 }
 
@@ -1343,6 +1860,29 @@ func (c *Controller) setState(s State) {
 	c.state = s
 	c.stateMu.Unlock()
 
+	c.stateChanged(oldState, s)
+}
+
+// compareAndSetState updates the state to s only if it is currently from.
+// The compare and the store happen under one lock, so a concurrent Pause or
+// Stop transition is never overwritten. Returns true if the state was changed
+// (thread-safe).
+func (c *Controller) compareAndSetState(from, s State) bool {
+	c.stateMu.Lock()
+	if c.state != from {
+		c.stateMu.Unlock()
+		return false
+	}
+	c.state = s
+	c.stateMu.Unlock()
+
+	c.stateChanged(from, s)
+	return true
+}
+
+// stateChanged emits a DrainStateChangedEvent and reports the new state to
+// bd agent if it differs from the old one. Called without stateMu held.
+func (c *Controller) stateChanged(oldState, s State) {
 	// Only emit and report if state actually changed
 	if oldState == s {
 		return
@@ -1361,28 +1901,13 @@ func (c *Controller) setState(s State) {
 
 // CurrentBead returns the ID of the bead currently being worked on,
 // or an empty string if no bead is active (thread-safe).
+// With multiple workers, this is the bead of the first busy worker.
 func (c *Controller) CurrentBead() string {
-	c.beadMu.RLock()
-	defer c.beadMu.RUnlock()
-	return c.currentBead
-}
-
-// setCurrentBead updates the current bead info (thread-safe).
-func (c *Controller) setCurrentBead(beadID, title string) {
-	c.beadMu.Lock()
-	c.currentBead = beadID
-	c.currentBeadTitle = title
-	c.currentBeadStart = time.Now()
-	c.beadMu.Unlock()
-}
-
-// clearCurrentBead clears the current bead info (thread-safe).
-func (c *Controller) clearCurrentBead() {
-	c.beadMu.Lock()
-	c.currentBead = ""
-	c.currentBeadTitle = ""
-	c.currentBeadStart = time.Time{}
-	c.beadMu.Unlock()
+	if w := c.currentWorker(); w != nil {
+		id, _, _ := w.bead()
+		return id
+	}
+	return ""
 }
 
 // triggerStall sets up the stalled state and updates the bead in br.
@@ -1436,16 +1961,22 @@ func (c *Controller) triggerStall(beadID, beadTitle, reason string) {
 func (c *Controller) clearStall(action string) {
 	c.stallMu.Lock()
 	beadID := c.stalledBeadID
+	stallType := c.stallType
+	breach := c.stalledBreach
 	c.stalledBeadID = ""
 	c.stalledBeadTitle = ""
 	c.stallReason = ""
 	c.stalledAt = time.Time{}
 	c.stallType = ""
 	c.stalledCreatedBeads = nil
+	c.stalledBreach = nil
 	c.stallMu.Unlock()
 
+	// Moving past a budget stall grants a fresh budget for the breached limit
+	c.budget.Waive(breach)
+
 	// Emit stall cleared event for persistence
-	if beadID != "" {
+	if beadID != "" || stallType == StallTypeBudget {
 		c.emit(&events.StallClearedEvent{
 			BaseEvent: events.NewInternalEvent(events.EventDrainStallCleared),
 			BeadID:    beadID,
@@ -1460,7 +1991,7 @@ type StallInfo struct {
 	BeadTitle    string
 	Reason       string
 	StalledAt    time.Time
-	StallType    string   // "abandoned" or "review"
+	StallType    string   // "abandoned", "review", or "budget"
 	CreatedBeads []string // bead IDs created during session (for review stalls)
 }
 
@@ -1469,7 +2000,7 @@ func (c *Controller) getStallInfo() *StallInfo {
 	c.stallMu.RLock()
 	defer c.stallMu.RUnlock()
 
-	if c.stalledBeadID == "" && c.stallType != StallTypeReview {
+	if c.stalledBeadID == "" && c.stallType != StallTypeReview && c.stallType != StallTypeBudget {
 		return nil
 	}
 
@@ -1584,7 +2115,7 @@ func (c *Controller) runStalled() {
 	// Use a ticker to periodically check if the stalled bead was deleted externally
 	// Only applicable for abandoned stalls (review stalls have no bead to check)
 	var ticker *time.Ticker
-	if stallInfo.StallType != StallTypeReview {
+	if stallInfo.BeadID != "" {
 		ticker = time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
 	}
@@ -1604,6 +2135,13 @@ func (c *Controller) runStalled() {
 			c.setState(StateIdle)
 			return
 		}
+		// Budget stalls: clear, granting a fresh budget, and continue
+		if stallInfo.StallType == StallTypeBudget {
+			c.logger.Info("resuming from budget stall", "reason", stallInfo.Reason)
+			c.clearStall("resume")
+			c.setState(StateIdle)
+			return
+		}
 		// Abandoned stalls: clear stall and go back to idle (bead will be retried)
 		c.logger.Info("retrying stalled bead",
 			"bead_id", stallInfo.BeadID)
@@ -1620,6 +2158,13 @@ func (c *Controller) runStalled() {
 			c.setState(StateIdle)
 			return
 		}
+		// Budget stalls: clear, granting a fresh budget, and continue
+		if stallInfo.StallType == StallTypeBudget {
+			c.logger.Info("resuming from budget stall", "reason", stallInfo.Reason)
+			c.clearStall("resume")
+			c.setState(StateIdle)
+			return
+		}
 		// Abandoned stalls: mark bead as skipped, clear stall, go to idle
 		c.logger.Info("resuming from stall, skipping bead",
 			"bead_id", stallInfo.BeadID)
@@ -1646,7 +2191,7 @@ func (c *Controller) runStalled() {
 	case <-tickerC:
 		// Periodic check: if the stalled bead was deleted, auto-clear
 		// Only applies to abandoned stalls (review stalls have no bead to check)
-		if stallInfo.StallType != StallTypeReview && !c.beadExists(stallInfo.BeadID) {
+		if !c.beadExists(stallInfo.BeadID) {
 			c.logger.Info("stalled bead was deleted externally, auto-clearing",
 				"bead_id", stallInfo.BeadID)
 			c.clearStall("auto_cleared")
@@ -1689,6 +2234,9 @@ func (c *Controller) RetryBead(beadID string) error {
 	// Reset bead in workqueue (idempotent if already pending)
 	c.workQueue.ResetBead(beadID)
 
+	// A retried bead starts with a fresh per-bead budget
+	c.budget.Waive(&budget.Breach{Scope: budget.ScopeBead, ID: beadID})
+
 	// If currently stalled on this bead, clear stall and go to idle
 	c.stallMu.RLock()
 	stalledOnThisBead := c.stalledBeadID == beadID
@@ -1716,22 +2264,20 @@ func (c *Controller) StalledBeadID() string {
 // GetDrainState returns the current drain state for observer context.
 // Implements observer.DrainStateProvider interface.
 func (c *Controller) GetDrainState() observer.DrainState {
-	c.beadMu.RLock()
-	beadID := c.currentBead
-	beadTitle := c.currentBeadTitle
-	beadStart := c.currentBeadStart
-	c.beadMu.RUnlock()
-
-	c.currentTurnMu.RLock()
-	turns := c.currentTurnCount
-	c.currentTurnMu.RUnlock()
+	var beadID, beadTitle string
+	var beadStart time.Time
+	turns := 0
+	if w := c.currentWorker(); w != nil {
+		beadID, beadTitle, beadStart = w.bead()
+		turns = w.turnCount()
+	}
 
 	statsSnap := c.getStatsSnapshot()
 
 	state := observer.DrainState{
 		Status:       string(c.getState()),
 		Uptime:       statsSnap.Uptime,
-		TotalCost:    statsSnap.TotalCostUSD,
+		TotalCost:    statsSnap.TotalCostUSD + c.liveCost(),
 		CurrentTurns: turns,
 	}
 
@@ -1809,7 +2355,7 @@ func (c *Controller) sleep(d time.Duration) {
 
 // runFollowUpSession runs a minimal session to verify and close an unclosed bead.
 // Returns true if the bead was closed, false otherwise.
-func (c *Controller) runFollowUpSession(bead *workqueue.Bead) (bool, *SessionResult, error) {
+func (c *Controller) runFollowUpSession(w *worker, bead *workqueue.Bead) (bool, *SessionResult, error) {
 	if !c.config.FollowUp.Enabled {
 		return false, nil, nil
 	}
@@ -1823,7 +2369,7 @@ func (c *Controller) runFollowUpSession(bead *workqueue.Bead) (bool, *SessionRes
 	followUpConfig := *c.config
 	followUpConfig.Claude.MaxTurns = c.config.FollowUp.MaxTurns
 
-	sess := session.New(&followUpConfig, c.router)
+	sess := c.newSession(w, &followUpConfig)
 
 	// Fetch bead parent for prompt expansion
 	beadParent := c.getBeadParent(bead.ID)
@@ -1843,29 +2389,33 @@ func (c *Controller) runFollowUpSession(bead *workqueue.Bead) (bool, *SessionRes
 		BeadID:    bead.ID,
 		Title:     bead.Title + " (follow-up)",
 	})
+	c.beginTranscript(w, bead.Title+" (follow-up)", "follow-up", prompt)
 
 	if err := sess.Start(c.ctx, prompt); err != nil {
 		return false, nil, fmt.Errorf("start follow-up session: %w", err)
 	}
 
 	// Parse stream
-	parser := session.NewParser(sess.Stdout(), c.router, sess)
+	parser := c.backend.NewDecoder(sess.Stdout(), c.router, sess)
+	parser.SetOnCostUpdate(w.setSessionCost)
+	defer w.setSessionCost(0)
 
 	parseDone := make(chan error, 1)
 	go func() {
 		parseDone <- parser.Parse()
 	}()
 
-	// Wait for session to complete
-	waitErr := sess.Wait()
+	// Read the output to EOF before reaping the process, as in runSession
 	<-parseDone
+	waitErr := sess.Wait()
+	c.invalidateBeadCache()
 
 	if waitErr != nil {
-		stderr := sess.Stderr()
-		if stderr != "" {
-			return false, nil, fmt.Errorf("follow-up session error: %w\nstderr: %s", waitErr, stderr)
-		}
-		return false, nil, fmt.Errorf("follow-up session error: %w", waitErr)
+		cost := parser.EstimatedCost()
+		c.accumulateCost(cost)
+		c.recordSpend(w, bead, cost)
+
+		return false, nil, c.sessionError("follow-up session error", sess, waitErr, parser.Result())
 	}
 
 	// Get session result
@@ -1873,6 +2423,13 @@ func (c *Controller) runFollowUpSession(bead *workqueue.Bead) (bool, *SessionRes
 	if parserResult := parser.Result(); parserResult != nil {
 		result.NumTurns = parserResult.NumTurns
 		result.TotalCostUSD = parserResult.TotalCostUSD
+	} else {
+		result.TotalCostUSD = parser.EstimatedCost()
+	}
+	c.recordSpend(w, bead, result.TotalCostUSD)
+
+	if class := resultFailure(parser.Result()); class != "" {
+		return false, result, workqueue.Classify(class, fmt.Errorf("follow-up session error: %s", parser.Result().Result))
 	}
 
 	// Check if follow-up closed the bead
@@ -1891,6 +2448,15 @@ func (c *Controller) runFollowUpSession(bead *workqueue.Bead) (bool, *SessionRes
 	return closed, result, nil
 }
 
+// invalidateBeadCache drops cached bead reads once a session ends, so the
+// checks that follow see what the session changed through br. The cache
+// also watches for session ends, but that event may not have arrived yet.
+func (c *Controller) invalidateBeadCache() {
+	if cache, ok := c.brClient.(*brclient.CachingClient); ok {
+		cache.Invalidate()
+	}
+}
+
 // resetBeadToOpen resets a stuck bead from in_progress to open status.
 func (c *Controller) resetBeadToOpen(beadID, notes string) error {
 	if c.brClient == nil {
@@ -1926,9 +2492,22 @@ func (c *Controller) getBeadStatus(beadID string) string {
 	return bead.Status
 }
 
+// previousFailures returns how many earlier attempts on a bead failed.
+// Attempts stopped by a graceful pause, a budget limit or a drain-wide
+// pause are not failures.
+func (c *Controller) previousFailures(beadID string) int {
+	if h, ok := c.workQueue.History()[beadID]; ok {
+		return h.Failures
+	}
+	return 0
+}
+
 // getStoredSessionID retrieves the stored session ID for a bead from history.
-// Returns empty string if no session ID is stored.
+// Returns empty string if no session ID is stored or the backend cannot resume.
 func (c *Controller) getStoredSessionID(beadID string) string {
+	if !c.backend.SupportsResume() {
+		return ""
+	}
 	history := c.workQueue.History()
 	if h, ok := history[beadID]; ok && h.LastSessionID != "" {
 		return h.LastSessionID
@@ -1986,6 +2565,16 @@ func (c *Controller) validateEpic(ctx context.Context) error {
 	return nil
 }
 
+// goCloseEligibleEpics runs closeEligibleEpics in the background. Stopping
+// the drain waits for it.
+func (c *Controller) goCloseEligibleEpics(triggeringBeadID string) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.closeEligibleEpics(triggeringBeadID)
+	}()
+}
+
 // closeEligibleEpics closes epics where all children are completed.
 // This is called asynchronously after a successful bead completion or when idle.
 // Errors are logged but not propagated - this is a best-effort operation.
@@ -2029,27 +2618,28 @@ func (c *Controller) closeEligibleEpics(triggeringBeadID string) {
 	}
 }
 
-// subscribeToBeadEvents subscribes to bead change events for tracking creations.
-func (c *Controller) subscribeToBeadEvents() {
+// subscribeToBeadEvents subscribes to bead change events for tracking
+// creations during the worker's session. Returns nil if no router is set.
+func (c *Controller) subscribeToBeadEvents(w *worker) <-chan events.Event {
 	if c.router == nil {
-		return
+		return nil
 	}
-	c.beadEventChan = c.router.SubscribeBuffered(100)
-	go c.watchBeadCreations()
+	ch := c.router.SubscribeBuffered(100)
+	go c.watchBeadCreations(w, ch)
+	return ch
 }
 
 // unsubscribeFromBeadEvents unsubscribes from bead change events.
-func (c *Controller) unsubscribeFromBeadEvents() {
-	if c.router != nil && c.beadEventChan != nil {
-		c.router.Unsubscribe(c.beadEventChan)
-		c.beadEventChan = nil
+func (c *Controller) unsubscribeFromBeadEvents(ch <-chan events.Event) {
+	if c.router != nil && ch != nil {
+		c.router.Unsubscribe(ch)
 	}
 }
 
 // watchBeadCreations monitors bead change events for new beads created by atari-drain.
-func (c *Controller) watchBeadCreations() {
-	for evt := range c.beadEventChan {
-		if c.getState() != StateWorking {
+func (c *Controller) watchBeadCreations(w *worker, ch <-chan events.Event) {
+	for evt := range ch {
+		if !w.busy() {
 			continue
 		}
 		beadEvt, ok := evt.(*events.BeadChangedEvent)
@@ -2064,32 +2654,11 @@ func (c *Controller) watchBeadCreations() {
 		if beadEvt.NewState == nil || beadEvt.NewState.CreatedBy != atariDrainActor {
 			continue
 		}
-		c.addCreatedBead(beadEvt.BeadID)
+		w.addCreatedBead(beadEvt.BeadID)
+		c.logger.Info("detected bead creation during session", "bead_id", beadEvt.BeadID, "worker", w.id)
 	}
 }
 
-// clearCreatedBeads clears the list of created beads for a new session.
-func (c *Controller) clearCreatedBeads() {
-	c.createdBeadsMu.Lock()
-	c.createdBeadsDuringSession = nil
-	c.createdBeadsMu.Unlock()
-}
-
-// addCreatedBead adds a bead ID to the list of created beads.
-func (c *Controller) addCreatedBead(beadID string) {
-	c.createdBeadsMu.Lock()
-	c.createdBeadsDuringSession = append(c.createdBeadsDuringSession, beadID)
-	c.createdBeadsMu.Unlock()
-	c.logger.Info("detected bead creation during session", "bead_id", beadID)
-}
-
-// getCreatedBeads returns a copy of the created beads list.
-func (c *Controller) getCreatedBeads() []string {
-	c.createdBeadsMu.Lock()
-	defer c.createdBeadsMu.Unlock()
-	return append([]string{}, c.createdBeadsDuringSession...)
-}
-
 // waitForCreationDebounce waits for a short period to catch any final events.
 func (c *Controller) waitForCreationDebounce() {
 	time.Sleep(creationDebounceInterval)
@@ -2119,3 +2688,283 @@ func (c *Controller) triggerReviewStall(beadIDs []string, reason string) {
 	c.setState(StateStalled)
 	c.logger.Warn("review stall triggered", "created_beads", beadIDs, "reason", reason)
 }
+
+// triggerBudgetStall stalls the drain because an epic or daily cost limit was
+// reached. Resuming clears the spending counted against that limit.
+func (c *Controller) triggerBudgetStall(breach *budget.Breach) {
+	reason := breach.String()
+
+	c.stallMu.Lock()
+	c.stalledBeadID = ""
+	c.stalledBeadTitle = ""
+	c.stallReason = reason
+	c.stalledAt = time.Now()
+	c.stallType = StallTypeBudget
+	c.stalledCreatedBeads = nil
+	c.stalledBreach = breach
+	c.stallMu.Unlock()
+
+	c.emit(&events.StallEvent{
+		BaseEvent: events.NewInternalEvent(events.EventDrainStall),
+		Reason:    reason,
+		StallType: StallTypeBudget,
+	})
+
+	c.setState(StateStalled)
+	c.logger.Warn("budget stall triggered",
+		"scope", breach.Scope,
+		"id", breach.ID,
+		"spent_usd", breach.SpentUSD,
+		"limit_usd", breach.LimitUSD)
+}
+
+// budgetEpic returns the epic a bead's spending counts against: the
+// configured epic, else the active top-level item, else the bead's parent.
+func (c *Controller) budgetEpic(bead *workqueue.Bead) string {
+	if c.config.WorkQueue.Epic != "" {
+		return c.config.WorkQueue.Epic
+	}
+	if topLevel := c.workQueue.ActiveTopLevel(); topLevel != "" && topLevel != bead.ID {
+		return topLevel
+	}
+	return bead.Parent
+}
+
+// recordSpend counts a finished session's cost against the bead's budgets.
+func (c *Controller) recordSpend(w *worker, bead *workqueue.Bead, cost float64) {
+	c.budget.Record(bead.ID, w.epic(), cost)
+}
+
+// pooled reports whether the controller runs more than one worker.
+func (c *Controller) pooled() bool {
+	return len(c.workers) > 1
+}
+
+// prepareWorktrees creates or reuses a git worktree for each pooled worker.
+// It is a no-op with a single worker, which runs in the project directory.
+func (c *Controller) prepareWorktrees(ctx context.Context) error {
+	if !c.pooled() {
+		return nil
+	}
+	if c.worktrees == nil {
+		return fmt.Errorf("workers > 1 requires a worktree manager")
+	}
+	// Nothing else brings one worker's commits to the others
+	if c.branches == nil {
+		return fmt.Errorf("workers > 1 requires git.branch_per_bead")
+	}
+
+	for _, w := range c.workers {
+		path, err := c.worktrees.Ensure(ctx, w.name)
+		if err != nil {
+			return fmt.Errorf("prepare worktree for %s: %w", w.name, err)
+		}
+		w.workDir = path
+		c.logger.Info("worker worktree ready", "worker", w.id, "path", path)
+	}
+	return nil
+}
+
+// newSession creates a session manager that runs in the worker's directory.
+func (c *Controller) newSession(w *worker, cfg *config.Config) *session.Manager {
+	sess := session.New(cfg, c.router)
+	sess.SetWorkDir(w.workDir)
+	beadID, _, _ := w.bead()
+	sess.SetBeadID(beadID)
+	sess.SetBackend(c.backend)
+	sess.SetModel(w.currentModel())
+	if t := w.currentTranscript(); t != nil {
+		sess.SetTranscript(t.Stdout(), t.Stderr())
+	}
+	return sess
+}
+
+// openTranscript starts saving the raw output of the worker's sessions for a
+// bead attempt. A transcript that cannot be opened is logged and skipped.
+func (c *Controller) openTranscript(w *worker, beadID string, attempt int) {
+	if c.transcripts == nil {
+		return
+	}
+	t, err := c.transcripts.Open(beadID, attempt)
+	if err != nil {
+		c.logger.Warn("failed to open session transcript", "bead_id", beadID, "error", err)
+		return
+	}
+	w.setTranscript(t)
+}
+
+// closeTranscript closes the worker's transcript, if any.
+func (c *Controller) closeTranscript(w *worker) {
+	if t := w.currentTranscript(); t != nil {
+		w.setTranscript(nil)
+		if err := t.Close(); err != nil {
+			c.logger.Warn("failed to close session transcript", "error", err)
+		}
+	}
+}
+
+// beginTranscript records the start of a session in the worker's transcript.
+func (c *Controller) beginTranscript(w *worker, title, promptName, prompt string) {
+	if t := w.currentTranscript(); t != nil {
+		t.BeginSession(transcript.SessionLine{
+			Title:      title,
+			Backend:    c.backend.Name(),
+			Model:      w.currentModel(),
+			PromptName: promptName,
+			Prompt:     prompt,
+		})
+	}
+}
+
+// idleWorker returns the first worker without a bead, or nil if all are busy.
+func (c *Controller) idleWorker() *worker {
+	for _, w := range c.workers {
+		if !w.busy() {
+			return w
+		}
+	}
+	return nil
+}
+
+// currentWorker returns the first busy worker, or nil if all are idle.
+func (c *Controller) currentWorker() *worker {
+	for _, w := range c.workers {
+		if w.busy() {
+			return w
+		}
+	}
+	return nil
+}
+
+// busyWorkers returns the number of workers currently assigned a bead.
+func (c *Controller) busyWorkers() int {
+	n := 0
+	for _, w := range c.workers {
+		if w.busy() {
+			n++
+		}
+	}
+	return n
+}
+
+// workerInfos returns a snapshot of every worker's progress.
+func (c *Controller) workerInfos() []viewmodel.WorkerInfo {
+	infos := make([]viewmodel.WorkerInfo, 0, len(c.workers))
+	for _, w := range c.workers {
+		info := w.info()
+		if info.BeadID != "" {
+			info.SelectedBecause = c.workQueue.Explain(info.BeadID)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// pauseWorkers asks every busy pooled worker to stop its session at the next
+// turn boundary. Returns the number of workers asked to pause.
+func (c *Controller) pauseWorkers() int {
+	if !c.pooled() {
+		return 0
+	}
+	n := 0
+	for _, w := range c.workers {
+		if w.requestPause() {
+			n++
+		}
+	}
+	return n
+}
+
+// waitForWorker waits up to d for a pooled worker to finish its bead,
+// respecting context cancellation.
+func (c *Controller) waitForWorker(d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-c.workerDone:
+	case <-c.ctx.Done():
+	}
+}
+
+// startBeadBranch checks out the bead's branch in the worker's directory when
+// the branch-per-bead workflow is enabled. On failure the bead must not run.
+func (c *Controller) startBeadBranch(w *worker, bead *workqueue.Bead) error {
+	if c.branches == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	if err := c.branches.Start(ctx, w.workDir, bead.ID); err != nil {
+		return fmt.Errorf("create bead branch: %w", err)
+	}
+	w.setBranch(beadbranch.Name(bead.ID))
+	return nil
+}
+
+// leaveBeadBranch moves the worker off a bead branch that was not merged, so
+// the next bead starts from the target branch rather than from this one's
+// leftover changes. It is a no-op after a merge.
+func (c *Controller) leaveBeadBranch(w *worker, bead *workqueue.Bead) {
+	if c.branches == nil || w.currentBranch() == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	if err := c.branches.Leave(ctx, w.workDir, bead.ID); err != nil {
+		c.logger.Warn("failed to leave bead branch",
+			"bead_id", bead.ID,
+			"branch", w.currentBranch(),
+			"error", err,
+		)
+		c.emit(&events.ErrorEvent{
+			BaseEvent: events.NewInternalEvent(events.EventError),
+			Message:   fmt.Sprintf("leave branch %s: %v", w.currentBranch(), err),
+			Severity:  events.SeverityWarning,
+			BeadID:    bead.ID,
+		})
+		return
+	}
+	w.setBranch("")
+}
+
+// verifyBead runs the verification commands in the worker's directory. It is
+// a no-op when no commands are configured.
+func (c *Controller) verifyBead(w *worker, bead *workqueue.Bead) error {
+	if c.verifier == nil || len(c.verifier.Commands()) == 0 {
+		return nil
+	}
+
+	c.logger.Info("verifying closed bead", "bead_id", bead.ID, "commands", len(c.verifier.Commands()))
+	// Each command is bounded by config.Verify.Timeout; like merges, checks
+	// are not cut short by a stop so that a bead is never half-verified.
+	if err := c.verifier.Run(context.Background(), w.workDir); err != nil {
+		return err
+	}
+	c.logger.Info("verification passed", "bead_id", bead.ID)
+	return nil
+}
+
+// mergeBeadBranch merges the worker's bead branch into the target branch.
+// It is a no-op when the bead did not run on its own branch.
+func (c *Controller) mergeBeadBranch(w *worker, bead *workqueue.Bead) error {
+	if c.branches == nil || w.currentBranch() == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	if err := c.branches.Merge(ctx, w.workDir, bead.ID); err != nil {
+		return err
+	}
+	c.logger.Info("merged bead branch",
+		"bead_id", bead.ID,
+		"branch", w.currentBranch(),
+		"target", c.branches.Target(),
+	)
+	w.setBranch("")
+	return nil
+}
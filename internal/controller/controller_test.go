@@ -2,14 +2,24 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/npratt/atari/internal/beadbranch"
 	"github.com/npratt/atari/internal/brclient"
 	"github.com/npratt/atari/internal/config"
 	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/schedule"
+	"github.com/npratt/atari/internal/testutil"
+	"github.com/npratt/atari/internal/verify"
 	"github.com/npratt/atari/internal/workqueue"
 )
 
@@ -838,7 +848,7 @@ func TestControllerFollowUpConfig(t *testing.T) {
 		c.ctx = context.Background()
 
 		bead := &workqueue.Bead{ID: "test-bead", Title: "Test"}
-		closed, result, err := c.runFollowUpSession(bead)
+		closed, result, err := c.runFollowUpSession(c.workers[0], bead)
 
 		if closed {
 			t.Error("expected closed to be false when follow-up is disabled")
@@ -1589,3 +1599,1168 @@ func TestControllerStatsThreadSafety(t *testing.T) {
 		}
 	})
 }
+
+func TestControllerWorkerPool(t *testing.T) {
+	t.Run("single worker by default", func(t *testing.T) {
+		cfg := testConfig()
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		stats := c.Stats()
+		if len(stats.Workers) != 1 {
+			t.Fatalf("expected 1 worker, got %d", len(stats.Workers))
+		}
+		if c.pooled() {
+			t.Error("single worker should not be pooled")
+		}
+	})
+
+	t.Run("zero workers treated as one", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Workers = 0
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		if len(c.Stats().Workers) != 1 {
+			t.Errorf("expected 1 worker, got %d", len(c.Stats().Workers))
+		}
+	})
+
+	t.Run("builds configured number of workers", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Workers = 3
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		stats := c.Stats()
+		if len(stats.Workers) != 3 {
+			t.Fatalf("expected 3 workers, got %d", len(stats.Workers))
+		}
+		for i, w := range stats.Workers {
+			if w.ID != i+1 {
+				t.Errorf("worker %d: expected ID %d, got %d", i, i+1, w.ID)
+			}
+			if w.BeadID != "" {
+				t.Errorf("worker %d: expected idle, got bead %s", w.ID, w.BeadID)
+			}
+		}
+	})
+
+	t.Run("run without worktree manager fails", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Workers = 2
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if err := c.Run(ctx); err == nil {
+			t.Error("expected error when workers > 1 without a worktree manager")
+		}
+	})
+
+	t.Run("idle worker and busy count", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Workers = 2
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		c.workers[0].setBead("bd-001", "First")
+		if got := c.idleWorker(); got != c.workers[1] {
+			t.Errorf("expected worker 2 to be idle, got %v", got)
+		}
+		if c.busyWorkers() != 1 {
+			t.Errorf("expected 1 busy worker, got %d", c.busyWorkers())
+		}
+		if c.CurrentBead() != "bd-001" {
+			t.Errorf("expected current bead bd-001, got %q", c.CurrentBead())
+		}
+
+		c.workers[1].setBead("bd-002", "Second")
+		if c.idleWorker() != nil {
+			t.Error("expected no idle worker")
+		}
+	})
+
+	t.Run("pause workers only reaches busy workers", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Workers = 2
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		c.workers[1].setBead("bd-002", "Second")
+		if n := c.pauseWorkers(); n != 1 {
+			t.Errorf("expected 1 worker asked to pause, got %d", n)
+		}
+		if !c.workers[1].pausePending {
+			t.Error("expected pending pause on busy worker without session")
+		}
+		if c.workers[0].pausePending {
+			t.Error("idle worker should not have a pending pause")
+		}
+	})
+}
+
+func TestControllerCompareAndSetState(t *testing.T) {
+	cfg := testConfig()
+	mockClient := brclient.NewMockClient()
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+
+	c.setState(StateWorking)
+	if !c.compareAndSetState(StateWorking, StateIdle) || c.State() != StateIdle {
+		t.Fatalf("expected working -> idle, got %s", c.State())
+	}
+
+	// A transition made by the main loop is never overwritten
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		c.setState(StateWorking)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.setState(StatePaused)
+		}()
+		go func() {
+			defer wg.Done()
+			c.compareAndSetState(StateWorking, StateIdle)
+		}()
+		wg.Wait()
+		if c.State() != StatePaused {
+			t.Fatalf("iteration %d: pause lost, state is %s", i, c.State())
+		}
+	}
+}
+
+func TestControllerBranchPerBead(t *testing.T) {
+	// newBranchController builds a controller whose git commands are answered
+	// by fn (return a non-nil error to fail the matching command).
+	newBranchController := func(fn func(args []string) error) (*Controller, *brclient.MockClient, *testutil.MockRunner) {
+		cfg := testConfig()
+		mockClient := brclient.NewMockClient()
+		runner := testutil.NewMockRunner()
+		runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+			return []byte{}, fn(args), true
+		}
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil,
+			WithBranches(beadbranch.New(runner, "/repo", "main")))
+		return c, mockClient, runner
+	}
+	hasCall := func(runner *testutil.MockRunner, sub string) bool {
+		for _, call := range runner.GetCalls() {
+			if strings.Contains(strings.Join(call.Args, " "), sub) {
+				return true
+			}
+		}
+		return false
+	}
+	bead := &workqueue.Bead{ID: "bd-001", Title: "Test"}
+
+	t.Run("closed bead is merged", func(t *testing.T) {
+		c, mockClient, runner := newBranchController(func(args []string) error {
+			if args[2] == "rev-parse" {
+				return errors.New("exit status 1")
+			}
+			return nil
+		})
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+
+		if err := c.startBeadBranch(w, bead); err != nil {
+			t.Fatalf("startBeadBranch: %v", err)
+		}
+		if w.currentBranch() != "atari/bd-001" {
+			t.Fatalf("expected worker on atari/bd-001, got %q", w.currentBranch())
+		}
+		c.handleBeadClosed(w, bead, &SessionResult{}, time.Second)
+
+		if !hasCall(runner, "checkout -q -b atari/bd-001 main") {
+			t.Error("expected bead branch to be created from main")
+		}
+		if !hasCall(runner, "rebase -q main") {
+			t.Error("expected bead branch to be rebased onto main")
+		}
+		if len(mockClient.UpdateStatusCalls) != 0 {
+			t.Errorf("expected no status updates, got %v", mockClient.UpdateStatusCalls)
+		}
+		if h := c.workQueue.History()[bead.ID]; h == nil || h.Status != workqueue.HistoryCompleted {
+			t.Errorf("expected bead recorded as completed, got %+v", h)
+		}
+	})
+
+	t.Run("merge conflict reopens bead with note", func(t *testing.T) {
+		c, mockClient, _ := newBranchController(func(args []string) error {
+			if args[2] == "rebase" && args[3] == "-q" {
+				return errors.New("CONFLICT (content): Merge conflict in README")
+			}
+			if args[2] == "rev-parse" {
+				return errors.New("exit status 1")
+			}
+			return nil
+		})
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+
+		if err := c.startBeadBranch(w, bead); err != nil {
+			t.Fatalf("startBeadBranch: %v", err)
+		}
+		c.handleBeadClosed(w, bead, &SessionResult{}, time.Second)
+
+		if len(mockClient.UpdateStatusCalls) != 1 {
+			t.Fatalf("expected 1 UpdateStatus call, got %d", len(mockClient.UpdateStatusCalls))
+		}
+		call := mockClient.UpdateStatusCalls[0]
+		if call.Status != "open" {
+			t.Errorf("expected status open, got %q", call.Status)
+		}
+		if !strings.Contains(call.Notes, "could not merge atari/bd-001 into main") {
+			t.Errorf("expected merge note, got %q", call.Notes)
+		}
+		if !strings.Contains(call.Notes, "Merge conflict in README") {
+			t.Errorf("expected conflict detail in note, got %q", call.Notes)
+		}
+		if h := c.workQueue.History()[bead.ID]; h == nil || h.Status != workqueue.HistoryFailed {
+			t.Errorf("expected bead recorded as failed, got %+v", h)
+		}
+	})
+
+	t.Run("branch creation failure refuses the bead", func(t *testing.T) {
+		c, mockClient, runner := newBranchController(func(args []string) error {
+			if args[2] == "checkout" {
+				return errors.New("error: local changes would be overwritten")
+			}
+			return nil
+		})
+		w := c.workers[0]
+
+		c.runBead(w, bead, "")
+
+		if w.currentBranch() != "" {
+			t.Errorf("expected no bead branch, got %q", w.currentBranch())
+		}
+		if hasCall(runner, "rebase") {
+			t.Error("expected no merge without a bead branch")
+		}
+		if len(mockClient.UpdateStatusCalls) != 0 {
+			t.Errorf("expected no status updates, got %v", mockClient.UpdateStatusCalls)
+		}
+		h := c.workQueue.History()[bead.ID]
+		if h == nil || h.Status != workqueue.HistoryFailed {
+			t.Fatalf("expected bead recorded as failed, got %+v", h)
+		}
+		if !strings.Contains(h.LastError, "create bead branch") {
+			t.Errorf("expected branch error recorded, got %q", h.LastError)
+		}
+	})
+
+	t.Run("unmerged bead leaves its branch", func(t *testing.T) {
+		c, _, runner := newBranchController(func(args []string) error { return nil })
+		runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+			switch args[2] {
+			case "rev-parse":
+				return nil, errors.New("exit status 1"), true
+			case "symbolic-ref":
+				return []byte("atari/bd-001\n"), nil, true
+			case "status":
+				return []byte(" M main.go\n"), nil, true
+			}
+			return []byte{}, nil, true
+		}
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+
+		if err := c.startBeadBranch(w, bead); err != nil {
+			t.Fatalf("startBeadBranch: %v", err)
+		}
+		c.handleGracefulPause(bead, &SessionResult{}, time.Second)
+		c.leaveBeadBranch(w, bead)
+
+		if !hasCall(runner, "commit -q --no-verify -m atari: unfinished work on bd-001") {
+			t.Error("expected uncommitted changes to be committed to the bead branch")
+		}
+		if !hasCall(runner, "checkout -q main") {
+			t.Error("expected main repository to return to main")
+		}
+		if w.currentBranch() != "" {
+			t.Errorf("expected worker off the bead branch, got %q", w.currentBranch())
+		}
+	})
+
+	t.Run("merged bead is not left again", func(t *testing.T) {
+		c, _, runner := newBranchController(func(args []string) error {
+			if args[2] == "rev-parse" {
+				return errors.New("exit status 1")
+			}
+			return nil
+		})
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+
+		if err := c.startBeadBranch(w, bead); err != nil {
+			t.Fatalf("startBeadBranch: %v", err)
+		}
+		c.handleBeadClosed(w, bead, &SessionResult{}, time.Second)
+		c.leaveBeadBranch(w, bead)
+
+		if hasCall(runner, "status --porcelain") {
+			t.Error("expected no leave after a merge")
+		}
+	})
+}
+
+func TestControllerBudget(t *testing.T) {
+	t.Run("daily budget stalls before starting a bead", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Budget.DailyUSD = 1
+		mockClient := brclient.NewMockClient()
+		mockClient.ReadyResponse = []brclient.Bead{
+			{ID: "bd-001", Title: "Test", Status: "open", Priority: 1},
+		}
+
+		wq := workqueue.New(cfg, mockClient, nil)
+		router := events.NewRouter(10)
+		defer router.Close()
+		c := New(cfg, wq, router, mockClient, nil, nil)
+		c.budget.Record("bd-000", "", 2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Run(ctx)
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+
+		if c.State() != StateStalled {
+			t.Fatalf("expected state %s, got %s", StateStalled, c.State())
+		}
+		stats := c.GetStats()
+		if stats.StallType != StallTypeBudget {
+			t.Errorf("expected stall type %q, got %q", StallTypeBudget, stats.StallType)
+		}
+		if !strings.Contains(stats.StallReason, "daily budget") {
+			t.Errorf("expected daily budget reason, got %q", stats.StallReason)
+		}
+		if _, ok := wq.History()["bd-001"]; ok {
+			t.Error("expected selected bead to be unclaimed")
+		}
+
+		// Resuming grants a fresh daily budget
+		c.Pause() // ignored while stalled
+		c.Retry()
+		time.Sleep(20 * time.Millisecond)
+		if b := c.budget.Check(""); b != nil {
+			t.Errorf("expected daily budget waived, got %v", b)
+		}
+
+		c.Stop()
+		cancel()
+		<-done
+	})
+
+	t.Run("epic budget stalls", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Budget.PerEpicUSD = 5
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		c.budget.Record("bd-001", "epic-1", 6)
+		bead := &workqueue.Bead{ID: "bd-002", Parent: "epic-1"}
+
+		breach := c.budget.Check(c.budgetEpic(bead))
+		if breach == nil {
+			t.Fatal("expected epic breach")
+		}
+		c.triggerBudgetStall(breach)
+
+		info := c.getStallInfo()
+		if info == nil || info.StallType != StallTypeBudget {
+			t.Fatalf("expected budget stall info, got %+v", info)
+		}
+		if info.BeadID != "" {
+			t.Errorf("expected no stalled bead, got %q", info.BeadID)
+		}
+
+		c.clearStall("resume")
+		if b := c.budget.Check("epic-1"); b != nil {
+			t.Errorf("expected epic budget waived after clearing stall, got %v", b)
+		}
+	})
+
+	t.Run("budget epic prefers configured epic", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.WorkQueue.Epic = "epic-cfg"
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		if got := c.budgetEpic(&workqueue.Bead{ID: "bd-001", Parent: "epic-1"}); got != "epic-cfg" {
+			t.Errorf("budgetEpic() = %q, want %q", got, "epic-cfg")
+		}
+	})
+
+	t.Run("bead over budget skips follow-up", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Budget.PerBeadUSD = 2
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		bead := &workqueue.Bead{ID: "bd-001", Title: "Test"}
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+		c.recordSpend(w, bead, 3)
+
+		c.handleFollowUp(w, bead, &SessionResult{NumTurns: 4, TotalCostUSD: 3}, time.Second)
+
+		if len(mockClient.UpdateStatusCalls) != 1 {
+			t.Fatalf("expected 1 UpdateStatus call, got %d", len(mockClient.UpdateStatusCalls))
+		}
+		call := mockClient.UpdateStatusCalls[0]
+		if call.Status != "open" || !strings.Contains(call.Notes, "over its $2.00 budget") {
+			t.Errorf("unexpected reset: %+v", call)
+		}
+		if h := wq.History()[bead.ID]; h == nil || h.Status != workqueue.HistorySkipped {
+			t.Errorf("expected bead skipped, got %+v", h)
+		}
+		if c.getStatsSnapshot().TotalCostUSD != 3 {
+			t.Errorf("expected cost accumulated, got %v", c.getStatsSnapshot().TotalCostUSD)
+		}
+
+		// Retrying the bead grants a fresh per-bead budget
+		if err := c.RetryBead(bead.ID); err != nil {
+			t.Fatalf("RetryBead() error: %v", err)
+		}
+		if b := c.budget.CheckBead(bead.ID, 0); b != nil {
+			t.Errorf("expected bead budget waived after retry, got %v", b)
+		}
+	})
+}
+
+func TestControllerLiveCost(t *testing.T) {
+	cfg := testConfig()
+	cfg.Workers = 2
+	mockClient := brclient.NewMockClient()
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+
+	c.accumulateCost(1.5)
+	c.workers[0].setBead("bd-001", "First")
+	c.workers[0].setSessionCost(0.25)
+	c.workers[1].setBead("bd-002", "Second")
+	c.workers[1].setSessionCost(0.5)
+
+	stats := c.Stats()
+	if stats.TotalCostUSD != 2.25 {
+		t.Errorf("Stats().TotalCostUSD = %v, want 2.25", stats.TotalCostUSD)
+	}
+	if stats.Workers[1].CostUSD != 0.5 {
+		t.Errorf("worker 2 CostUSD = %v, want 0.5", stats.Workers[1].CostUSD)
+	}
+	if got := c.GetDrainState().TotalCost; got != 2.25 {
+		t.Errorf("GetDrainState().TotalCost = %v, want 2.25", got)
+	}
+
+	// Finishing a bead drops its live estimate
+	c.workers[0].clearBead()
+	if got := c.Stats().TotalCostUSD; got != 2 {
+		t.Errorf("Stats().TotalCostUSD after clear = %v, want 2", got)
+	}
+}
+
+func TestControllerVerify(t *testing.T) {
+	// newVerifyController builds a controller whose verify commands are
+	// answered by fn.
+	newVerifyController := func(fn func(command string) ([]byte, error)) (*Controller, *brclient.MockClient) {
+		cfg := testConfig()
+		mockClient := brclient.NewMockClient()
+		runner := testutil.NewMockRunner()
+		runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+			out, err := fn(args[len(args)-1])
+			return out, err, true
+		}
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil,
+			WithVerifier(verify.New(runner, config.VerifyConfig{
+				Commands: []string{"go test ./...", "golangci-lint run"},
+			})))
+		return c, mockClient
+	}
+	bead := &workqueue.Bead{ID: "bd-001", Title: "Test"}
+
+	t.Run("passing checks accept the close", func(t *testing.T) {
+		var ran []string
+		c, mockClient := newVerifyController(func(command string) ([]byte, error) {
+			ran = append(ran, command)
+			return []byte("ok\n"), nil
+		})
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+
+		c.handleBeadClosed(w, bead, &SessionResult{}, time.Second)
+
+		if len(ran) != 2 {
+			t.Errorf("expected both commands to run, got %v", ran)
+		}
+		if len(mockClient.UpdateStatusCalls) != 0 {
+			t.Errorf("expected no status updates, got %v", mockClient.UpdateStatusCalls)
+		}
+		if h := c.workQueue.History()[bead.ID]; h == nil || h.Status != workqueue.HistoryCompleted {
+			t.Errorf("expected bead recorded as completed, got %+v", h)
+		}
+	})
+
+	t.Run("failing check reopens bead with output", func(t *testing.T) {
+		c, mockClient := newVerifyController(func(command string) ([]byte, error) {
+			if command == "golangci-lint run" {
+				return []byte("main.go:10:2: ineffectual assignment to err\n"), errors.New("exit status 1")
+			}
+			return []byte("ok\n"), nil
+		})
+		w := c.workers[0]
+		w.setBead(bead.ID, bead.Title)
+
+		c.handleFollowUpSuccess(w, bead, &SessionResult{NumTurns: 3}, &SessionResult{NumTurns: 1}, 0.5, time.Second)
+
+		if len(mockClient.UpdateStatusCalls) != 1 {
+			t.Fatalf("expected 1 UpdateStatus call, got %d", len(mockClient.UpdateStatusCalls))
+		}
+		call := mockClient.UpdateStatusCalls[0]
+		if call.Status != "open" {
+			t.Errorf("expected status open, got %q", call.Status)
+		}
+		if !strings.Contains(call.Notes, `"golangci-lint run" failed`) {
+			t.Errorf("expected failing command in note, got %q", call.Notes)
+		}
+		if !strings.Contains(call.Notes, "ineffectual assignment to err") {
+			t.Errorf("expected command output in note, got %q", call.Notes)
+		}
+		h := c.workQueue.History()[bead.ID]
+		if h == nil || h.Status != workqueue.HistoryFailed {
+			t.Fatalf("expected bead recorded as failed, got %+v", h)
+		}
+		if !strings.Contains(h.LastError, "verification failed") {
+			t.Errorf("expected verification error recorded, got %q", h.LastError)
+		}
+	})
+}
+
+func TestControllerPromptSelection(t *testing.T) {
+	cfg := testConfig()
+	cfg.Prompts = []config.PromptRule{
+		{Name: "docs", Labels: []string{"docs"}, Prompt: "Document {{.BeadID}}"},
+		{Name: "bugs", IssueType: "bug", Prompt: "Fix {{.BeadID}}"},
+	}
+	mockClient := brclient.NewMockClient()
+	mockClient.ReadyResponse = []brclient.Bead{
+		{ID: "bd-001", Title: "Crash on start", Status: "open", Priority: 1, IssueType: "bug"},
+	}
+
+	wq := workqueue.New(cfg, mockClient, nil)
+	router := events.NewRouter(100)
+	defer router.Close()
+	sub := router.Subscribe()
+	c := New(cfg, wq, router, mockClient, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	timeout := time.After(2 * time.Second)
+	var start *events.SessionStartEvent
+	for start == nil {
+		select {
+		case ev := <-sub:
+			if e, ok := ev.(*events.SessionStartEvent); ok {
+				start = e
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for SessionStartEvent")
+		}
+	}
+
+	if start.BeadID != "bd-001" {
+		t.Errorf("SessionStartEvent.BeadID = %q, want bd-001", start.BeadID)
+	}
+	if start.Prompt != "bugs" {
+		t.Errorf("SessionStartEvent.Prompt = %q, want bugs", start.Prompt)
+	}
+
+	c.Stop()
+	cancel()
+	<-done
+}
+
+func TestControllerExpandPromptTemplate(t *testing.T) {
+	cfg := testConfig()
+	cfg.PromptMode = config.PromptModeTemplate
+	mockClient := brclient.NewMockClient()
+	mockClient.ShowResponses["bd-epic"] = &brclient.Bead{ID: "bd-epic", Title: "Checkout flow"}
+
+	wq := workqueue.New(cfg, mockClient, nil)
+	wq.SetHistory(map[string]*workqueue.BeadHistory{
+		"bd-001": {ID: "bd-001", Attempts: 1, LastError: "tests failed"},
+	})
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+
+	bead := &brclient.Bead{ID: "bd-001", Title: "Fix cart"}
+	details := &brclient.Bead{
+		ID:           "bd-001",
+		Title:        "Fix cart",
+		Notes:        "see logs",
+		Parent:       "bd-epic",
+		Dependencies: []brclient.BeadReference{{ID: "bd-000", Status: "closed"}},
+	}
+	vars := config.PromptVars{BeadID: "bd-001", BeadTitle: "Fix cart", BeadParent: "bd-epic"}
+	tmpl := "{{.BeadID}} in {{.Epic.Title}}; notes: {{.Bead.Notes}}; deps: {{len .Dependencies}}; last error: {{.History.LastError}}"
+
+	got, err := c.expandPrompt("default", tmpl, bead, details, vars)
+	if err != nil {
+		t.Fatalf("expandPrompt() error = %v", err)
+	}
+	want := "bd-001 in Checkout flow; notes: see logs; deps: 1; last error: tests failed"
+	if got != want {
+		t.Errorf("expandPrompt() = %q, want %q", got, want)
+	}
+
+	// Render errors surface instead of starting a session with a broken prompt
+	if _, err := c.expandPrompt("default", "{{.Bead.Nope}}", bead, details, vars); err == nil {
+		t.Error("expandPrompt() expected error for unknown field")
+	}
+
+	// Simple mode keeps plain substitution
+	cfg.PromptMode = config.PromptModeSimple
+	got, err = c.expandPrompt("default", "Work on {{.BeadID}}", bead, details, vars)
+	if err != nil || got != "Work on bd-001" {
+		t.Errorf("expandPrompt() simple = %q, %v", got, err)
+	}
+}
+
+func TestControllerPreviousAttempts(t *testing.T) {
+	cfg := testConfig()
+	cfg.Paths.Log = filepath.Join(t.TempDir(), "atari.log")
+
+	start := time.Now().Add(-time.Minute)
+	var lines []string
+	for i, ev := range []events.Event{
+		&events.SessionStartEvent{BaseEvent: events.BaseEvent{EventType: events.EventSessionStart, Time: start}, BeadID: "bd-001"},
+		&events.ClaudeToolUseEvent{
+			BaseEvent: events.BaseEvent{EventType: events.EventClaudeToolUse, Time: start.Add(time.Second)},
+			ToolName:  "Bash",
+			Input:     map[string]any{"command": "make migrate"},
+		},
+		&events.IterationEndEvent{BaseEvent: events.BaseEvent{EventType: events.EventIterationEnd, Time: start.Add(2 * time.Second)}, BeadID: "bd-001"},
+	} {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			t.Fatalf("marshal event %d: %v", i, err)
+		}
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(cfg.Paths.Log, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	mockClient := brclient.NewMockClient()
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+
+	// First attempt: no section
+	wq.SetHistory(map[string]*workqueue.BeadHistory{"bd-001": {ID: "bd-001", Attempts: 1}})
+	if got := c.previousAttempts("bd-001"); got != "" {
+		t.Errorf("expected no section on first attempt, got %q", got)
+	}
+
+	// Retry after a timeout with stderr
+	wq.SetHistory(map[string]*workqueue.BeadHistory{"bd-001": {
+		ID:        "bd-001",
+		Attempts:  2,
+		LastError: "session error: timed out after 5m0s of inactivity: signal: terminated" + stderrMarker + "database locked",
+	}})
+	got := c.previousAttempts("bd-001")
+	for _, want := range []string{
+		"## Previous attempts",
+		"attempted 1 time(s) before",
+		"Last error: session error: timed out after 5m0s of inactivity",
+		"database locked",
+		"1 tool calls (Bash 1)",
+		"- Bash make migrate",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected section to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Last error: session error: timed out after 5m0s of inactivity: signal: terminated\nstderr") {
+		t.Error("expected stderr split from the error line")
+	}
+}
+
+func TestControllerPreviousFailures(t *testing.T) {
+	cfg := testConfig()
+	mockClient := brclient.NewMockClient()
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+
+	// Three attempts: one failed, one paused gracefully, one about to start
+	wq.SetHistory(map[string]*workqueue.BeadHistory{
+		"bd-001": {ID: "bd-001", Status: workqueue.HistoryWorking, Attempts: 3, Failures: 1},
+	})
+	if got := c.previousFailures("bd-001"); got != 1 {
+		t.Errorf("previousFailures() = %d, want 1", got)
+	}
+
+	// A budget stop is not a failure
+	wq.RecordStopped("bd-001", errors.New("bead bd-001 spent $2.00 of its $1.00 budget"))
+	if got := c.previousFailures("bd-001"); got != 1 {
+		t.Errorf("previousFailures() after a budget stop = %d, want 1", got)
+	}
+	if got := c.previousFailures("bd-002"); got != 0 {
+		t.Errorf("previousFailures() for an unseen bead = %d, want 0", got)
+	}
+}
+
+func TestControllerModelSelection(t *testing.T) {
+	cfg := testConfig()
+	cfg.Model = config.ModelConfig{
+		Rules:    []config.ModelRule{{IssueType: "chore", Model: "haiku"}},
+		Escalate: config.ModelEscalation{AfterFailures: 2, Model: "opus"},
+	}
+	cfg.Backoff.MaxFailures = 5
+	mockClient := brclient.NewMockClient()
+	mockClient.ReadyResponse = []brclient.Bead{
+		{ID: "bd-001", Title: "Bump deps", Status: "open", Priority: 3, IssueType: "chore"},
+	}
+
+	wq := workqueue.New(cfg, mockClient, nil)
+	// Two earlier failed attempts, long out of backoff
+	wq.SetHistory(map[string]*workqueue.BeadHistory{
+		"bd-001": {ID: "bd-001", Status: workqueue.HistoryFailed, Attempts: 2, Failures: 2, Model: "haiku"},
+	})
+	router := events.NewRouter(100)
+	defer router.Close()
+	sub := router.Subscribe()
+	c := New(cfg, wq, router, mockClient, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	timeout := time.After(2 * time.Second)
+	var start *events.SessionStartEvent
+	for start == nil {
+		select {
+		case ev := <-sub:
+			if e, ok := ev.(*events.SessionStartEvent); ok {
+				start = e
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for SessionStartEvent")
+		}
+	}
+
+	if start.Model != "opus" {
+		t.Errorf("SessionStartEvent.Model = %q, want opus after escalation", start.Model)
+	}
+	if h := wq.History()["bd-001"]; h == nil || h.Model != "opus" {
+		t.Errorf("expected history to record the model, got %+v", h)
+	}
+
+	c.Stop()
+	cancel()
+	<-done
+}
+
+func TestControllerSchedule(t *testing.T) {
+	cfg := testConfig()
+	mockClient := brclient.NewMockClient()
+	mockClient.ReadyResponse = []brclient.Bead{}
+	var polled atomic.Int32
+	mockClient.DynamicReady = func(ctx context.Context, opts *brclient.ReadyOptions) ([]brclient.Bead, error, bool) {
+		polled.Add(1)
+		return nil, nil, false
+	}
+
+	// The weekend window opens 300ms after the controller starts
+	sched, err := schedule.New(config.ScheduleConfig{
+		Timezone: "UTC",
+		Windows:  []config.ScheduleWindow{{Days: "sat,sun"}},
+	})
+	if err != nil {
+		t.Fatalf("schedule.New() error: %v", err)
+	}
+	opens := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	start := time.Now()
+
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil, WithSchedule(sched))
+	c.now = func() time.Time { return opens.Add(time.Since(start) - 300*time.Millisecond) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	if c.State() != StatePaused {
+		t.Errorf("state outside schedule = %s, want %s", c.State(), StatePaused)
+	}
+	if n := polled.Load(); n != 0 {
+		t.Errorf("polled for work %d times outside schedule", n)
+	}
+	if status := c.GetStats().Schedule; status == nil || status.Open || !status.Next.Equal(opens) {
+		t.Errorf("schedule stats = %+v, want closed until %s", status, opens)
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if c.State() != StateIdle {
+		t.Errorf("state once the window opened = %s, want %s", c.State(), StateIdle)
+	}
+	if polled.Load() == 0 {
+		t.Error("did not poll for work once the window opened")
+	}
+	if status := c.GetStats().Schedule; status == nil || !status.Open || status.Window != "sat,sun" {
+		t.Errorf("schedule stats = %+v, want open in sat,sun", status)
+	}
+
+	c.Stop()
+	<-done
+}
+
+func TestControllerResumeScheduledPause(t *testing.T) {
+	cfg := testConfig()
+	mockClient := brclient.NewMockClient()
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+	open := schedule.Status{Open: true, Window: "sat,sun"}
+
+	resumed := func() bool {
+		select {
+		case <-c.resumeSignal:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// The window reopened while the session was still running: the pause is
+	// kept until the drain reaches Paused
+	c.setScheduledPause(true)
+	c.setState(StateWorking)
+	c.resumeScheduledPause(open)
+	if resumed() {
+		t.Fatal("resumed a drain that had not paused yet")
+	}
+
+	c.setState(StatePaused)
+	c.resumeScheduledPause(open)
+	if !resumed() {
+		t.Fatal("did not resume once the drain paused")
+	}
+
+	// Pauses the schedule did not make are left alone
+	c.resumeScheduledPause(open)
+	if resumed() {
+		t.Error("resumed a pause the schedule did not make")
+	}
+}
+
+func TestControllerUntil(t *testing.T) {
+	// runUntil runs the controller until it stops or the timeout passes, and
+	// returns the DrainStopEvent.
+	runUntil := func(t *testing.T, c *Controller, router *events.Router, during func()) *events.DrainStopEvent {
+		t.Helper()
+		sub := router.Subscribe()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Run(ctx)
+		}()
+		if during != nil {
+			time.Sleep(30 * time.Millisecond)
+			during()
+		}
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+		case <-time.After(time.Second):
+			c.Stop()
+			<-done
+			t.Fatal("drain did not stop")
+		}
+		if c.State() != StateStopped {
+			t.Errorf("state = %s, want %s", c.State(), StateStopped)
+		}
+
+		for {
+			select {
+			case ev := <-sub:
+				if stop, ok := ev.(*events.DrainStopEvent); ok {
+					return stop
+				}
+			default:
+				t.Fatal("no DrainStopEvent emitted")
+				return nil
+			}
+		}
+	}
+
+	t.Run("max beads counts beads completed during the run", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Until.MaxBeads = 2
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		wq.RecordSuccess("bd-old")
+		router := events.NewRouter(100)
+		defer router.Close()
+		c := New(cfg, wq, router, mockClient, nil, nil)
+
+		stop := runUntil(t, c, router, func() {
+			if c.State() == StateStopped {
+				t.Error("stopped before any bead was completed in this run")
+			}
+			wq.RecordSuccess("bd-001")
+			wq.RecordSuccess("bd-002")
+		})
+
+		if stop.Condition != UntilMaxBeads || stop.Reason != "completed 2 of 2 beads" {
+			t.Errorf("stop event = %+v", stop)
+		}
+	})
+
+	t.Run("epic closed", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Until.EpicClosed = "bd-epic"
+		mockClient := brclient.NewMockClient()
+		mockClient.ShowResponses["bd-epic"] = &brclient.Bead{ID: "bd-epic", Status: "closed"}
+		wq := workqueue.New(cfg, mockClient, nil)
+		router := events.NewRouter(100)
+		defer router.Close()
+		c := New(cfg, wq, router, mockClient, nil, nil)
+
+		stop := runUntil(t, c, router, nil)
+
+		if stop.Condition != UntilEpicClosed || !strings.Contains(stop.Reason, "bd-epic") {
+			t.Errorf("stop event = %+v", stop)
+		}
+	})
+
+	t.Run("max cost", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Until.MaxCostUSD = 5
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		router := events.NewRouter(100)
+		defer router.Close()
+		c := New(cfg, wq, router, mockClient, nil, nil)
+
+		stop := runUntil(t, c, router, func() {
+			c.accumulateCost(5.5)
+		})
+
+		if stop.Condition != UntilMaxCost || stop.Reason != "spent $5.50 of $5.00" {
+			t.Errorf("stop event = %+v", stop)
+		}
+	})
+
+	t.Run("max duration", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Until.MaxDuration = 50 * time.Millisecond
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		router := events.NewRouter(100)
+		defer router.Close()
+		c := New(cfg, wq, router, mockClient, nil, nil)
+
+		stop := runUntil(t, c, router, nil)
+
+		if stop.Condition != UntilMaxDuration {
+			t.Errorf("stop event = %+v", stop)
+		}
+	})
+
+	t.Run("worker pool starts no beads beyond the limit", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Workers = 3
+		cfg.Until.MaxBeads = 2
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		c := New(cfg, wq, nil, mockClient, nil, nil)
+
+		if c.atBeadLimit() {
+			t.Error("atBeadLimit() = true with no beads started")
+		}
+		c.workers[0].setBead("bd-001", "One")
+		wq.RecordSuccess("bd-000")
+		if !c.atBeadLimit() {
+			t.Error("atBeadLimit() = false with one bead completed and one running")
+		}
+	})
+}
+
+func TestControllerQueueBead(t *testing.T) {
+	cfg := testConfig()
+	mockClient := brclient.NewMockClient()
+	mockClient.SetShowResponse("bd-open", &brclient.Bead{ID: "bd-open", Title: "Open", Status: "open"})
+	mockClient.SetShowResponse("bd-done", &brclient.Bead{ID: "bd-done", Status: "closed"})
+	wq := workqueue.New(cfg, mockClient, nil)
+	c := New(cfg, wq, nil, mockClient, nil, nil)
+
+	if err := c.QueueBead("bd-done"); err == nil || !strings.Contains(err.Error(), "already closed") {
+		t.Errorf("QueueBead(closed) error = %v", err)
+	}
+	if err := c.QueueBead("bd-missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("QueueBead(missing) error = %v", err)
+	}
+	if err := c.QueueBead("bd-open"); err != nil {
+		t.Fatalf("QueueBead() error: %v", err)
+	}
+	if err := c.QueueBead("bd-open"); err != nil {
+		t.Fatalf("QueueBead() twice error: %v", err)
+	}
+
+	bead, _, err := c.selectNextBead()
+	if err != nil {
+		t.Fatalf("selectNextBead() error: %v", err)
+	}
+	if bead == nil || bead.ID != "bd-open" {
+		t.Fatalf("selectNextBead() = %+v, want queued bead bd-open", bead)
+	}
+	if c.workQueue.Claim("bd-open", "") {
+		t.Error("queued bead was not claimed when selected")
+	}
+	if why := c.workQueue.Explain("bd-open"); why != "queued with atari run" {
+		t.Errorf("Explain() = %q, want queued explanation", why)
+	}
+	if next := c.nextQueuedBead(); next != nil {
+		t.Errorf("bead queued twice was selected again: %+v", next)
+	}
+}
+
+func TestClassifySession(t *testing.T) {
+	killed := exec.Command("sh", "-c", "kill -KILL $$").Run()
+	exited := exec.Command("sh", "-c", "exit 1").Run()
+
+	tests := []struct {
+		name     string
+		timedOut bool
+		stderr   string
+		waitErr  error
+		result   *events.SessionEndEvent
+		want     workqueue.FailureClass
+	}{
+		{name: "timeout", timedOut: true, stderr: "API Error: 429", waitErr: killed, want: workqueue.FailureTimeout},
+		{name: "rate limit in stderr", stderr: "API Error: 429 Too Many Requests", waitErr: exited, want: workqueue.FailureRateLimit},
+		{name: "usage limit in result", waitErr: exited, result: &events.SessionEndEvent{IsError: true, Result: "Claude AI usage limit reached"}, want: workqueue.FailureRateLimit},
+		{name: "auth", stderr: "Invalid API key · Please run /login", waitErr: exited, want: workqueue.FailureAuth},
+		{name: "overloaded", stderr: `API Error: 529 {"type":"overloaded_error"}`, waitErr: exited, want: workqueue.FailureTransient},
+		{name: "connection reset", stderr: "Error: read ECONNRESET", waitErr: exited, want: workqueue.FailureTransient},
+		{name: "expired login", stderr: "OAuth token has expired. Please obtain a new token", waitErr: exited, want: workqueue.FailureAuth},
+		{name: "summary mentioning errors", waitErr: exited, result: &events.SessionEndEvent{Result: "Tests fail: connection refused by the test DB; added rate limit handling"}, want: workqueue.FailureCrash},
+		{name: "loose words in an error result", waitErr: exited, result: &events.SessionEndEvent{IsError: true, Result: "the server was overloaded, fetch failed"}, want: workqueue.FailureCrash},
+		{name: "max turns", waitErr: exited, result: &events.SessionEndEvent{Subtype: "error_max_turns", IsError: true}, want: workqueue.FailureGaveUp},
+		{name: "killed by a signal", waitErr: killed, want: workqueue.FailureTransient},
+		{name: "crash", stderr: "TypeError: cannot read properties of undefined", waitErr: exited, want: workqueue.FailureCrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifySession(tt.timedOut, tt.stderr, tt.waitErr, tt.result); got != tt.want {
+				t.Errorf("classifySession() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Only error results are classified on a clean exit
+	if got := resultFailure(&events.SessionEndEvent{Result: "Fixed the rate limit handling"}); got != "" {
+		t.Errorf("resultFailure() on a successful result = %q, want none", got)
+	}
+	if got := resultFailure(&events.SessionEndEvent{IsError: true, Result: "API Error: 429"}); got != workqueue.FailureRateLimit {
+		t.Errorf("resultFailure() = %q, want %q", got, workqueue.FailureRateLimit)
+	}
+}
+
+func TestControllerFailurePause(t *testing.T) {
+	newController := func() (*Controller, *workqueue.Manager) {
+		cfg := testConfig()
+		cfg.Retry.RateLimit = config.RetryPolicy{Action: config.RetryPause, Wait: 20 * time.Millisecond}
+		mockClient := brclient.NewMockClient()
+		wq := workqueue.New(cfg, mockClient, nil)
+		wq.SetHistory(map[string]*workqueue.BeadHistory{"bd-001": {ID: "bd-001", Status: workqueue.HistoryWorking, Attempts: 1}})
+		return New(cfg, wq, nil, mockClient, nil, nil), wq
+	}
+	rateLimited := workqueue.Classify(workqueue.FailureRateLimit, errors.New("API Error: 429"))
+
+	t.Run("pauses and resumes after the wait", func(t *testing.T) {
+		c, wq := newController()
+		c.setState(StatePaused)
+		c.recordFailure(&workqueue.Bead{ID: "bd-001"}, rateLimited)
+
+		h := wq.History()["bd-001"]
+		if h.FailureClass != workqueue.FailureRateLimit {
+			t.Errorf("expected failure class %q, got %q", workqueue.FailureRateLimit, h.FailureClass)
+		}
+		if h.Attempts != 0 {
+			t.Errorf("expected the rate-limited attempt not to count, got %d attempts", h.Attempts)
+		}
+
+		select {
+		case <-c.pauseSignal:
+		default:
+			t.Fatal("expected a pause signal")
+		}
+		select {
+		case <-c.resumeSignal:
+		case <-time.After(time.Second):
+			t.Fatal("expected the drain to resume after the wait")
+		}
+	})
+
+	t.Run("manual pause cancels the resume", func(t *testing.T) {
+		c, _ := newController()
+		c.setState(StatePaused)
+		c.recordFailure(&workqueue.Bead{ID: "bd-001"}, rateLimited)
+		c.Pause()
+
+		select {
+		case <-c.resumeSignal:
+			t.Fatal("expected no resume after a manual pause")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("other failures do not pause", func(t *testing.T) {
+		c, _ := newController()
+		c.recordFailure(&workqueue.Bead{ID: "bd-001"}, workqueue.Classify(workqueue.FailureCrash, errors.New("exit status 1")))
+
+		select {
+		case <-c.pauseSignal:
+			t.Fatal("expected no pause signal for a crash")
+		default:
+		}
+	})
+}
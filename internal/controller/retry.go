@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/observer"
+	"github.com/npratt/atari/internal/session"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// stderrMarker separates a session error from the stderr captured with it.
+const stderrMarker = "\nstderr: "
+
+// Limits for the previous-attempts prompt section.
+const (
+	maxRetryErrorBytes  = 1000
+	maxRetryStderrBytes = 2000
+)
+
+// sessionError describes a failed session, noting whether the inactivity
+// watchdog stopped it and appending any captured stderr. The error is
+// classified for the work queue's retry policies; result is the session's
+// result event, if it reported one.
+func (c *Controller) sessionError(prefix string, sess *session.Manager, waitErr error, result *events.SessionEndEvent) error {
+	class := classifySession(sess.TimedOut(), sess.Stderr(), waitErr, result)
+	if sess.TimedOut() {
+		waitErr = fmt.Errorf("timed out after %s of inactivity: %w", c.config.Claude.Timeout, waitErr)
+	}
+	if stderr := sess.Stderr(); stderr != "" {
+		return workqueue.Classify(class, fmt.Errorf("%s: %w"+stderrMarker+"%s", prefix, waitErr, stderr))
+	}
+	return workqueue.Classify(class, fmt.Errorf("%s: %w", prefix, waitErr))
+}
+
+// recordFailure records a failed attempt on a bead. If the retry policy for
+// the failure's class is to pause, the drain pauses, since no bead can get
+// past a rate limit or a login problem.
+func (c *Controller) recordFailure(bead *workqueue.Bead, err error) {
+	c.workQueue.RecordFailure(bead.ID, err)
+
+	class := workqueue.ClassOf(err)
+	if policy := c.workQueue.RetryPolicy(class); policy.Action == config.RetryPause {
+		c.pauseForFailure(bead, class, policy.Wait, err)
+	}
+}
+
+// pauseForFailure pauses the drain after a failure of the given class. With
+// a wait, the drain resumes by itself once it has passed; otherwise it stays
+// paused until resumed.
+func (c *Controller) pauseForFailure(bead *workqueue.Bead, class workqueue.FailureClass, wait time.Duration, err error) {
+	msg := fmt.Sprintf("pausing drain after %s failure", class)
+	if wait > 0 {
+		msg += fmt.Sprintf(", resuming in %s", wait)
+	} else {
+		msg += " until resumed"
+	}
+	c.logger.Warn(msg, "bead_id", bead.ID, "error", err)
+	c.emit(&events.ErrorEvent{
+		BaseEvent: events.NewInternalEvent(events.EventError),
+		Message:   msg,
+		Severity:  events.SeverityWarning,
+		BeadID:    bead.ID,
+		Context:   map[string]string{"failure_class": string(class)},
+	})
+
+	select {
+	case c.pauseSignal <- struct{}{}:
+	default:
+		// Signal already pending
+	}
+	c.resumeAfter(wait)
+}
+
+// resumeAfter resumes a drain paused by a failure once wait has passed,
+// replacing any earlier timer. Pausing or resuming by hand cancels it.
+func (c *Controller) resumeAfter(wait time.Duration) {
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
+
+	if c.retryResume != nil {
+		c.retryResume.Stop()
+		c.retryResume = nil
+	}
+	if wait <= 0 {
+		return
+	}
+	c.retryResume = time.AfterFunc(wait, func() {
+		if c.getState() == StatePaused {
+			c.logger.Info("failure pause over, resuming")
+			c.Resume()
+		}
+	})
+}
+
+// cancelRetryResume stops a pending resume after a failure pause.
+func (c *Controller) cancelRetryResume() {
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
+
+	if c.retryResume != nil {
+		c.retryResume.Stop()
+		c.retryResume = nil
+	}
+}
+
+// previousAttempts builds the "Previous attempts" prompt section for a bead
+// being retried after a failure, so the new session can avoid repeating the
+// last one's mistake. Returns "" on a first attempt.
+func (c *Controller) previousAttempts(beadID string) string {
+	h, ok := c.workQueue.History()[beadID]
+	if !ok || h.Attempts <= 1 || h.LastError == "" {
+		return ""
+	}
+
+	lastError, stderr, _ := strings.Cut(h.LastError, stderrMarker)
+
+	var sb strings.Builder
+	sb.WriteString("## Previous attempts\n\n")
+	fmt.Fprintf(&sb, "This bead has been attempted %d time(s) before and the last attempt failed. "+
+		"Review what went wrong and take a different approach where needed.\n\n", h.Attempts-1)
+	fmt.Fprintf(&sb, "Last error: %s\n", events.Truncate(strings.TrimSpace(lastError), maxRetryErrorBytes))
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		if len(stderr) > maxRetryStderrBytes {
+			stderr = "..." + stderr[len(stderr)-maxRetryStderrBytes:]
+		}
+		fmt.Fprintf(&sb, "\nStderr (tail):\n```\n%s\n```\n", stderr)
+	}
+
+	if summary := c.lastFailedAttempt(beadID); summary != nil && summary.ToolCalls > 0 {
+		names := make([]string, 0, len(summary.ToolCounts))
+		for name := range summary.ToolCounts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		counts := make([]string, len(names))
+		for i, name := range names {
+			counts[i] = fmt.Sprintf("%s %d", name, summary.ToolCounts[name])
+		}
+
+		fmt.Fprintf(&sb, "\nThe last attempt made %d tool calls (%s)", summary.ToolCalls, strings.Join(counts, ", "))
+		if summary.ToolErrors > 0 {
+			fmt.Fprintf(&sb, ", %d of which returned errors", summary.ToolErrors)
+		}
+		sb.WriteString(". Its final tool calls were:\n")
+		for _, call := range summary.RecentTools {
+			fmt.Fprintf(&sb, "- %s\n", call)
+		}
+	}
+
+	return sb.String()
+}
+
+// lastFailedAttempt reads the tool-call summary for the bead's last failed
+// attempt from the event log. Tool events carry no bead ID, so this is skipped
+// when several workers write to the log at once.
+func (c *Controller) lastFailedAttempt(beadID string) *observer.AttemptSummary {
+	if c.pooled() || c.config.Paths.Log == "" {
+		return nil
+	}
+
+	summary, err := observer.NewLogReader(c.config.Paths.Log).LastFailedAttempt(beadID)
+	if err != nil {
+		c.logger.Debug("failed to read previous attempt from log", "bead_id", beadID, "error", err)
+		return nil
+	}
+	return summary
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// RunBead works on one bead instead of draining the queue, as atari run does.
+// The bead goes through the same path as a selected bead: prompt expansion,
+// follow-up session and epic auto-close. It returns once that has finished,
+// with the outcome recorded in the bead's history.
+func (c *Controller) RunBead(ctx context.Context, beadID string) (workqueue.HistoryStatus, error) {
+	c.cancelMu.Lock()
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.cancelMu.Unlock()
+	defer c.cancel()
+
+	c.setStartTime(time.Now())
+
+	if c.pooled() {
+		return "", fmt.Errorf("running a single bead needs one worker, have %d", len(c.workers))
+	}
+	if c.branches != nil {
+		if err := c.branches.ResolveTarget(c.ctx); err != nil {
+			return "", fmt.Errorf("branch per bead: %w", err)
+		}
+	}
+
+	bead, err := c.openBead(beadID)
+	if err != nil {
+		return "", err
+	}
+	if !c.workQueue.Claim(bead.ID, "run with atari run") {
+		return "", fmt.Errorf("bead %s is already being worked on", bead.ID)
+	}
+	epicID := c.budgetEpic(bead)
+	if breach := c.budget.Check(epicID); breach != nil {
+		c.workQueue.Unclaim(bead.ID)
+		return "", fmt.Errorf("budget: %s", breach)
+	}
+
+	c.emit(&events.DrainStartEvent{
+		BaseEvent: events.NewInternalEvent(events.EventDrainStart),
+		WorkDir:   ".",
+	})
+
+	c.runWorkingOnBead(c.workers[0], bead, epicID)
+
+	// Wait for epic auto-close before reporting
+	c.wg.Wait()
+
+	var status workqueue.HistoryStatus
+	if h, ok := c.workQueue.History()[bead.ID]; ok {
+		status = h.Status
+	}
+	c.emit(&events.DrainStopEvent{
+		BaseEvent: events.NewInternalEvent(events.EventDrainStop),
+		Reason:    fmt.Sprintf("ran %s: %s", bead.ID, status),
+	})
+	c.setState(StateStopped)
+	return status, nil
+}
+
+// QueueBead asks a running drain to work on a bead next, ahead of the
+// work queue's own selection, as atari run does when a daemon is running.
+func (c *Controller) QueueBead(beadID string) error {
+	if beadID == "" {
+		return errors.New("bead ID required")
+	}
+	if _, err := c.openBead(beadID); err != nil {
+		return err
+	}
+
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	if !slices.Contains(c.queued, beadID) {
+		c.queued = append(c.queued, beadID)
+	}
+	c.logger.Info("bead queued", "bead_id", beadID, "queued", len(c.queued))
+	return nil
+}
+
+// nextQueuedBead claims the oldest bead queued with QueueBead, skipping any
+// that were closed or picked up in the meantime. Returns nil if none are left.
+func (c *Controller) nextQueuedBead() *workqueue.Bead {
+	for {
+		c.queueMu.Lock()
+		if len(c.queued) == 0 {
+			c.queueMu.Unlock()
+			return nil
+		}
+		beadID := c.queued[0]
+		c.queued = c.queued[1:]
+		c.queueMu.Unlock()
+
+		bead, err := c.openBead(beadID)
+		if err != nil {
+			c.logger.Warn("skipping queued bead", "bead_id", beadID, "error", err)
+			c.emit(&events.ErrorEvent{
+				BaseEvent: events.NewInternalEvent(events.EventError),
+				Message:   fmt.Sprintf("skipping queued bead: %v", err),
+				Severity:  events.SeverityWarning,
+				BeadID:    beadID,
+			})
+			continue
+		}
+		if c.workQueue.Claim(bead.ID, "queued with atari run") {
+			return bead
+		}
+	}
+}
+
+// openBead fetches a bead that can be worked on, failing if it does not
+// exist or is already closed.
+func (c *Controller) openBead(beadID string) (*workqueue.Bead, error) {
+	if c.brClient == nil {
+		return nil, errors.New("no bead client available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bead, err := c.brClient.Show(ctx, beadID)
+	if err != nil {
+		return nil, fmt.Errorf("show bead %s: %w", beadID, err)
+	}
+	if bead == nil {
+		return nil, fmt.Errorf("bead %s not found", beadID)
+	}
+	if bead.Status == "closed" || bead.Status == "completed" {
+		return nil, fmt.Errorf("bead %s is already closed", beadID)
+	}
+	return bead, nil
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/npratt/atari/internal/schedule"
+)
+
+// scheduleCheckInterval is the longest the schedule watcher sleeps, so it
+// notices clock changes such as a laptop waking up.
+const scheduleCheckInterval = time.Minute
+
+// ScheduleStatus returns the schedule's current window, or nil when no
+// schedule is configured.
+func (c *Controller) ScheduleStatus() *schedule.Status {
+	if c.schedule == nil {
+		return nil
+	}
+	status := c.schedule.Status(c.now())
+	return &status
+}
+
+// outsideSchedule pauses the drain when no schedule window is open. It is
+// checked before selecting each bead; busy pooled workers stop at their next
+// turn boundary.
+func (c *Controller) outsideSchedule() bool {
+	if c.schedule == nil {
+		return false
+	}
+	status := c.schedule.Status(c.now())
+	if status.Open {
+		return false
+	}
+
+	c.setScheduledPause(true)
+	c.setState(StatePaused)
+	if n := c.pauseWorkers(); n > 0 {
+		c.logger.Info("outside schedule, paused with workers stopping at turn boundary",
+			"active_workers", n, "next_window", status.Next)
+	} else {
+		c.logger.Info("outside schedule, paused", "next_window", status.Next)
+	}
+	return true
+}
+
+// watchSchedule follows the schedule until ctx is done. When a window closes
+// during a session the drain pauses gracefully; while a window is open, a
+// drain paused by the schedule resumes.
+func (c *Controller) watchSchedule(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	open := c.schedule.Status(c.now()).Open
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		now := c.now()
+		status := c.schedule.Status(now)
+		switch {
+		case open && !status.Open:
+			// Idle and pooled drains pause in runIdle before taking more work
+			if c.getState() == StateWorking && !c.pooled() {
+				c.setScheduledPause(true)
+				c.GracefulPause()
+			}
+		case status.Open:
+			c.resumeScheduledPause(status)
+		}
+		open = status.Open
+
+		wait := scheduleCheckInterval
+		if !status.Next.IsZero() {
+			wait = min(wait, max(status.Next.Sub(now), 10*time.Millisecond))
+		}
+		timer.Reset(wait)
+	}
+}
+
+// resumeScheduledPause resumes the drain if the schedule paused it. It runs
+// on every check while a window is open, not only when the window opens: a
+// drain that was still finishing its session then reaches Paused later, and
+// its pause is resumed at the next check.
+func (c *Controller) resumeScheduledPause(status schedule.Status) {
+	if c.getState() != StatePaused || !c.takeScheduledPause() {
+		return
+	}
+	c.logger.Info("schedule window open, resuming", "window", status.Window)
+	c.Resume()
+}
+
+// setScheduledPause records whether the drain is paused by the schedule.
+func (c *Controller) setScheduledPause(paused bool) {
+	c.scheduleMu.Lock()
+	defer c.scheduleMu.Unlock()
+	c.scheduledPause = paused
+}
+
+// takeScheduledPause reports whether the drain was paused by the schedule
+// and clears the flag. Callers check that the drain is paused first, so a
+// pause still waiting for its session keeps the flag.
+func (c *Controller) takeScheduledPause() bool {
+	c.scheduleMu.Lock()
+	defer c.scheduleMu.Unlock()
+	paused := c.scheduledPause
+	c.scheduledPause = false
+	return paused
+}
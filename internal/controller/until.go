@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+)
+
+// Drain-until conditions, as recorded in DrainStopEvent.Condition.
+const (
+	UntilMaxBeads    = "max_beads"
+	UntilEpicClosed  = "epic_closed"
+	UntilMaxCost     = "max_cost"
+	UntilMaxDuration = "max_duration"
+)
+
+// untilMet describes a drain-until condition that has been reached.
+type untilMet struct {
+	condition string
+	reason    string
+}
+
+// checkUntil returns the first configured drain-until condition that has
+// been reached, or nil if the drain should go on.
+func (c *Controller) checkUntil() *untilMet {
+	until := c.config.Until
+
+	if until.MaxBeads > 0 {
+		if n := c.completedThisRun(); n >= until.MaxBeads {
+			return &untilMet{UntilMaxBeads, fmt.Sprintf("completed %d of %d beads", n, until.MaxBeads)}
+		}
+	}
+
+	stats := c.getStatsSnapshot()
+	if until.MaxCostUSD > 0 && stats.TotalCostUSD >= until.MaxCostUSD {
+		return &untilMet{UntilMaxCost, fmt.Sprintf("spent $%.2f of $%.2f", stats.TotalCostUSD, until.MaxCostUSD)}
+	}
+	if until.MaxDuration > 0 {
+		if elapsed := c.now().Sub(stats.StartTime); elapsed >= until.MaxDuration {
+			return &untilMet{UntilMaxDuration, fmt.Sprintf("ran for %s of %s", elapsed.Truncate(time.Second), until.MaxDuration)}
+		}
+	}
+
+	if until.EpicClosed != "" && c.isBeadClosed(until.EpicClosed) {
+		return &untilMet{UntilEpicClosed, fmt.Sprintf("epic %s closed", until.EpicClosed)}
+	}
+	return nil
+}
+
+// stopUntil stops the drain for a drain-until condition. Beads in progress
+// finish in runStopping, which records the condition in DrainStopEvent.
+func (c *Controller) stopUntil(met *untilMet) {
+	c.statsMu.Lock()
+	c.untilMet = met
+	c.statsMu.Unlock()
+
+	c.logger.Info("drain-until condition met, stopping",
+		"condition", met.condition,
+		"reason", met.reason,
+		"active_workers", c.busyWorkers())
+	c.setState(StateStopping)
+}
+
+// atBeadLimit reports whether the beads completed and in progress already
+// reach the max-beads limit, so a worker pool starts no more.
+func (c *Controller) atBeadLimit() bool {
+	limit := c.config.Until.MaxBeads
+	return limit > 0 && c.completedThisRun()+c.busyWorkers() >= limit
+}
+
+// completedThisRun returns the number of beads completed since Run started.
+func (c *Controller) completedThisRun() int {
+	c.statsMu.Lock()
+	base := c.completedAtStart
+	c.statsMu.Unlock()
+	return c.workQueue.Stats().Completed - base
+}
+
+// stopReason returns the reason and drain-until condition for the stop event
+// emitted when the drain stops gracefully.
+func (c *Controller) stopReason() (reason, condition string) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	if c.untilMet == nil {
+		return "graceful stop completed", ""
+	}
+	return c.untilMet.reason, c.untilMet.condition
+}
@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/npratt/atari/internal/session"
+	"github.com/npratt/atari/internal/transcript"
+	"github.com/npratt/atari/internal/viewmodel"
+)
+
+// worker holds the per-session state for one slot of the worker pool.
+// With a single worker the controller runs sessions in the project directory;
+// with more, each worker runs in its own git worktree.
+type worker struct {
+	id      int
+	name    string
+	workDir string // empty means the current working directory
+
+	mu           sync.RWMutex
+	beadID       string
+	beadTitle    string
+	beadStart    time.Time
+	turns        int
+	sessionCost  float64 // estimated cost of the running session, not yet recorded
+	branch       string  // bead branch checked out for the session, if any
+	epicID       string  // epic the bead's spending counts against
+	model        string  // model selected for the bead's sessions
+	sess         *session.Manager
+	transcript   *transcript.Writer // raw output of the bead attempt's sessions, if saved
+	pausePending bool               // graceful pause requested before the session started
+
+	// Created beads tracking (protected by createdMu)
+	createdBeads []string
+	createdMu    sync.Mutex
+}
+
+// newWorker creates an idle worker with the given 1-based ID.
+func newWorker(id int) *worker {
+	return &worker{
+		id:   id,
+		name: fmt.Sprintf("worker-%d", id),
+	}
+}
+
+// busy reports whether the worker is currently assigned a bead.
+func (w *worker) busy() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.beadID != ""
+}
+
+// setBead assigns a bead to the worker and resets per-session progress.
+func (w *worker) setBead(beadID, title string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.beadID = beadID
+	w.beadTitle = title
+	w.beadStart = time.Now()
+	w.turns = 0
+	w.sessionCost = 0
+	w.branch = ""
+	w.epicID = ""
+	w.model = ""
+	w.pausePending = false
+}
+
+// clearBead marks the worker as idle.
+func (w *worker) clearBead() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.beadID = ""
+	w.beadTitle = ""
+	w.beadStart = time.Time{}
+	w.turns = 0
+	w.sessionCost = 0
+	w.branch = ""
+	w.epicID = ""
+	w.model = ""
+	w.sess = nil
+	w.pausePending = false
+}
+
+// bead returns the worker's current bead ID, title, and start time.
+func (w *worker) bead() (id, title string, start time.Time) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.beadID, w.beadTitle, w.beadStart
+}
+
+// setTurns records the number of turns completed in the current session.
+func (w *worker) setTurns(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.turns = n
+}
+
+// turnCount returns the number of turns completed in the current session.
+func (w *worker) turnCount() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.turns
+}
+
+// setSessionCost records the running session's estimated cost.
+func (w *worker) setSessionCost(cost float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sessionCost = cost
+}
+
+// liveCost returns the running session's estimated cost, or 0 between sessions.
+func (w *worker) liveCost() float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.sessionCost
+}
+
+// setEpic records the epic the worker's bead spending counts against.
+func (w *worker) setEpic(epicID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.epicID = epicID
+}
+
+// epic returns the epic the worker's bead spending counts against.
+func (w *worker) epic() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.epicID
+}
+
+// setModel records the model selected for the worker's bead.
+func (w *worker) setModel(model string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.model = model
+}
+
+// currentModel returns the model selected for the worker's bead, or "" for
+// the agent's default.
+func (w *worker) currentModel() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.model
+}
+
+// setBranch records the bead branch the worker's session runs on.
+func (w *worker) setBranch(branch string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.branch = branch
+}
+
+// currentBranch returns the bead branch the worker's session runs on, or ""
+// if none is checked out or it has already been merged or left.
+func (w *worker) currentBranch() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.branch
+}
+
+// setSession records the active session so it can be paused from outside the
+// worker. A graceful pause requested before the session started is applied here.
+func (w *worker) setSession(sess *session.Manager) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sess = sess
+	if sess != nil && w.pausePending {
+		sess.RequestPause()
+		w.pausePending = false
+	}
+}
+
+// setTranscript records the transcript for the worker's bead attempt.
+func (w *worker) setTranscript(t *transcript.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.transcript = t
+}
+
+// currentTranscript returns the transcript for the worker's bead attempt, or
+// nil if session output is not being saved.
+func (w *worker) currentTranscript() *transcript.Writer {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.transcript
+}
+
+// requestPause asks the worker's session to stop at the next turn boundary.
+// Returns false if the worker is idle.
+func (w *worker) requestPause() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.beadID == "" {
+		return false
+	}
+	if w.sess != nil {
+		w.sess.RequestPause()
+	} else {
+		w.pausePending = true
+	}
+	return true
+}
+
+// info returns a snapshot of the worker for display.
+func (w *worker) info() viewmodel.WorkerInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return viewmodel.WorkerInfo{
+		ID:        w.id,
+		BeadID:    w.beadID,
+		BeadTitle: w.beadTitle,
+		StartedAt: w.beadStart,
+		Turns:     w.turns,
+		CostUSD:   w.sessionCost,
+		Model:     w.model,
+	}
+}
+
+// clearCreatedBeads clears the list of created beads for a new session.
+func (w *worker) clearCreatedBeads() {
+	w.createdMu.Lock()
+	w.createdBeads = nil
+	w.createdMu.Unlock()
+}
+
+// addCreatedBead adds a bead ID to the list of created beads.
+func (w *worker) addCreatedBead(beadID string) {
+	w.createdMu.Lock()
+	w.createdBeads = append(w.createdBeads, beadID)
+	w.createdMu.Unlock()
+}
+
+// getCreatedBeads returns a copy of the created beads list.
+func (w *worker) getCreatedBeads() []string {
+	w.createdMu.Lock()
+	defer w.createdMu.Unlock()
+	return append([]string{}, w.createdBeads...)
+}
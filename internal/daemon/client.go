@@ -1,13 +1,17 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"syscall"
 	"time"
+
+	"github.com/npratt/atari/internal/events"
 )
 
 const (
@@ -136,6 +140,68 @@ func (c *Client) Retry(beadID string) error {
 	return err
 }
 
+// Queue asks the daemon to work on a bead next, ahead of its own selection.
+func (c *Client) Queue(beadID string) error {
+	params := QueueParams{BeadID: beadID}
+	_, err := c.call("queue", params)
+	return err
+}
+
+// Subscribe streams events from the daemon, calling handle for each one,
+// until ctx is cancelled or the daemon closes the stream. Cancelling ctx
+// is not an error. Event types this build does not know are skipped.
+func (c *Client) Subscribe(ctx context.Context, params SubscribeParams, handle func(events.Event)) error {
+	conn, err := net.DialTimeout("unix", c.sockPath, c.timeout)
+	if err != nil {
+		return c.wrapConnError(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Unblock the decoder when the caller is done
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return fmt.Errorf("set deadline: %w", err)
+	}
+
+	req := Request{Method: "subscribe", Params: params}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+
+	decoder := json.NewDecoder(conn)
+	var resp Response
+	if err := decoder.Decode(&resp); err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("daemon error: %s", resp.Error)
+	}
+
+	// Events may be far apart; only the handshake is time-limited
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("set deadline: %w", err)
+	}
+
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read event: %w", err)
+		}
+		ev, err := events.ParseEvent(raw)
+		if err != nil {
+			return fmt.Errorf("parse event: %w", err)
+		}
+		if ev != nil {
+			handle(ev)
+		}
+	}
+}
+
 // IsRunning checks if the daemon is running by attempting to connect.
 func (c *Client) IsRunning() bool {
 	conn, err := net.DialTimeout("unix", c.sockPath, time.Second)
@@ -178,6 +178,30 @@ func TestClient_Stop_Force(t *testing.T) {
 	}
 }
 
+func TestClient_Queue(t *testing.T) {
+	sockPath := shortSocketPath(t)
+
+	var receivedID string
+	cleanup := mockServer(t, sockPath, func(req Request) Response {
+		if req.Method != "queue" {
+			return Response{Error: "unexpected method"}
+		}
+		if params, ok := req.Params.(map[string]interface{}); ok {
+			receivedID, _ = params["bead_id"].(string)
+		}
+		return Response{Result: "queued"}
+	})
+	defer cleanup()
+
+	client := NewClient(sockPath)
+	if err := client.Queue("bd-042"); err != nil {
+		t.Errorf("Queue() error: %v", err)
+	}
+	if receivedID != "bd-042" {
+		t.Errorf("server received bead_id %q, want bd-042", receivedID)
+	}
+}
+
 func TestClient_IsRunning_True(t *testing.T) {
 	sockPath := shortSocketPath(t)
 
@@ -4,39 +4,62 @@ package daemon
 import (
 	"log/slog"
 	"net"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/npratt/atari/internal/config"
 	"github.com/npratt/atari/internal/controller"
+	"github.com/npratt/atari/internal/events"
 )
 
 // Daemon manages background execution with external control via Unix socket.
 type Daemon struct {
 	config     *config.Config
 	controller *controller.Controller
+	router     *events.Router
 	listener   net.Listener
 	sockPath   string
 	startTime  time.Time
 	logger     *slog.Logger
 
+	// HTTP API (only when config.HTTP.Listen is set)
+	httpServer *http.Server
+	metrics    *metrics
+	metricsSub <-chan events.Event
+
 	running bool
 	stopCh  chan struct{} // signals Start() to return
 	mu      sync.RWMutex
 }
 
+// Option configures optional Daemon dependencies.
+type Option func(*Daemon)
+
+// WithRouter sets the event router streamed to subscribe clients.
+func WithRouter(r *events.Router) Option {
+	return func(d *Daemon) {
+		d.router = r
+	}
+}
+
 // New creates a new Daemon with the given configuration and controller.
-func New(cfg *config.Config, ctrl *controller.Controller, logger *slog.Logger) *Daemon {
+func New(cfg *config.Config, ctrl *controller.Controller, logger *slog.Logger, opts ...Option) *Daemon {
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Daemon{
+	d := &Daemon{
 		config:     cfg,
 		controller: ctrl,
 		sockPath:   cfg.Paths.Socket,
 		logger:     logger,
+		metrics:    newMetrics(),
 		stopCh:     make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // Running returns whether the daemon is currently running.
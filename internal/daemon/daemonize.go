@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -84,6 +85,15 @@ func IsDaemonized() bool {
 	return os.Getenv(daemonEnvVar) == "1"
 }
 
+// ProcessAlive reports whether a process with the given PID is running.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
 // waitForSocketReady waits for the socket to accept connections.
 func waitForSocketReady(socketPath string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
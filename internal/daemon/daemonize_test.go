@@ -3,6 +3,7 @@ package daemon
 import (
 	"net"
 	"os"
+	"os/exec"
 	"testing"
 	"time"
 )
@@ -34,6 +35,23 @@ func TestIsDaemonized_WrongValue(t *testing.T) {
 	}
 }
 
+func TestProcessAlive(t *testing.T) {
+	if !ProcessAlive(os.Getpid()) {
+		t.Error("expected own process to be alive")
+	}
+	if ProcessAlive(0) {
+		t.Error("expected PID 0 to be reported as not running")
+	}
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run true: %v", err)
+	}
+	if ProcessAlive(cmd.Process.Pid) {
+		t.Error("expected exited process to be reported as not running")
+	}
+}
+
 func TestWaitForSocketReady_Success(t *testing.T) {
 	sockPath := shortSocketPath(t)
 
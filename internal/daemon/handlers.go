@@ -8,6 +8,9 @@ import (
 	"github.com/npratt/atari/internal/controller"
 )
 
+// errNoController is the error returned when the daemon has no controller.
+const errNoController = "no controller available"
+
 // handleRequest dispatches the request to the appropriate handler.
 func (d *Daemon) handleRequest(ctx context.Context, req *Request) Response {
 	switch req.Method {
@@ -21,6 +24,8 @@ func (d *Daemon) handleRequest(ctx context.Context, req *Request) Response {
 		return d.handleStop(req)
 	case "retry":
 		return d.handleRetry(req)
+	case "queue":
+		return d.handleQueue(req)
 	default:
 		return Response{Error: fmt.Sprintf("unknown method: %s", req.Method)}
 	}
@@ -29,7 +34,7 @@ func (d *Daemon) handleRequest(ctx context.Context, req *Request) Response {
 // handleStatus returns the current daemon status.
 func (d *Daemon) handleStatus() Response {
 	if d.controller == nil {
-		return Response{Error: "no controller available"}
+		return Response{Error: errNoController}
 	}
 
 	state := d.controller.State()
@@ -39,6 +44,62 @@ func (d *Daemon) handleStatus() Response {
 	startTime := d.startTime
 	d.mu.RUnlock()
 
+	workers := make([]WorkerStatus, 0, len(stats.Workers))
+	for _, w := range stats.Workers {
+		ws := WorkerStatus{
+			ID:              w.ID,
+			BeadID:          w.BeadID,
+			BeadTitle:       w.BeadTitle,
+			Turns:           w.Turns,
+			CostUSD:         w.CostUSD,
+			Model:           w.Model,
+			StartedAt:       w.StartedAt,
+			SelectedBecause: w.SelectedBecause,
+		}
+		if w.BeadID != "" && !w.StartedAt.IsZero() {
+			ws.Elapsed = time.Since(w.StartedAt).Truncate(time.Second).String()
+		}
+		workers = append(workers, ws)
+	}
+
+	var stall *StallStatus
+	var blocked *BlockedStatus
+	var sched *ScheduleStatus
+	var beadCache *BeadCacheStatus
+	tuiStats := d.controller.GetStats()
+	if tuiStats.StallReason != "" {
+		stall = &StallStatus{
+			BeadID:       tuiStats.StalledBeadID,
+			BeadTitle:    tuiStats.StalledBeadTitle,
+			Reason:       tuiStats.StallReason,
+			Type:         tuiStats.StallType,
+			StalledAt:    tuiStats.StalledAt,
+			CreatedBeads: tuiStats.CreatedBeads,
+		}
+	}
+	if b := tuiStats.TopBlockedBead; b != nil {
+		blocked = &BlockedStatus{
+			BeadID:    b.BeadID,
+			Failures:  b.FailureCount,
+			RetryIn:   b.RetryIn.Truncate(time.Second).String(),
+			LastError: b.LastError,
+		}
+	}
+	if s := tuiStats.Schedule; s != nil {
+		sched = &ScheduleStatus{
+			Open:   s.Open,
+			Window: s.Window,
+			Next:   s.Next,
+		}
+	}
+	if c := stats.BeadCache; c != nil {
+		beadCache = &BeadCacheStatus{
+			Hits:      c.Hits,
+			Misses:    c.Misses,
+			Coalesced: c.Coalesced,
+		}
+	}
+
 	return Response{
 		Result: StatusResponse{
 			Status:      string(state),
@@ -53,7 +114,13 @@ func (d *Daemon) handleStatus() Response {
 				Failed:       stats.QueueStats.Failed,
 				Abandoned:    stats.QueueStats.Abandoned,
 				InBackoff:    stats.QueueStats.InBackoff,
+				TotalCostUSD: stats.TotalCostUSD,
 			},
+			Workers:   workers,
+			Stall:     stall,
+			Blocked:   blocked,
+			Schedule:  sched,
+			BeadCache: beadCache,
 		},
 	}
 }
@@ -61,7 +128,7 @@ func (d *Daemon) handleStatus() Response {
 // handlePause requests the controller to pause at the next turn boundary.
 func (d *Daemon) handlePause() Response {
 	if d.controller == nil {
-		return Response{Error: "no controller available"}
+		return Response{Error: errNoController}
 	}
 
 	d.controller.GracefulPause()
@@ -71,7 +138,7 @@ func (d *Daemon) handlePause() Response {
 // handleResume requests the controller to resume.
 func (d *Daemon) handleResume() Response {
 	if d.controller == nil {
-		return Response{Error: "no controller available"}
+		return Response{Error: errNoController}
 	}
 
 	d.controller.Resume()
@@ -81,7 +148,7 @@ func (d *Daemon) handleResume() Response {
 // handleStop requests the controller to stop and schedules daemon shutdown.
 func (d *Daemon) handleStop(req *Request) Response {
 	if d.controller == nil {
-		return Response{Error: "no controller available"}
+		return Response{Error: errNoController}
 	}
 
 	// Check for force parameter
@@ -131,7 +198,7 @@ func (d *Daemon) handleStop(req *Request) Response {
 // If no bead ID is provided, retries the currently stalled bead.
 func (d *Daemon) handleRetry(req *Request) Response {
 	if d.controller == nil {
-		return Response{Error: "no controller available"}
+		return Response{Error: errNoController}
 	}
 
 	// Extract bead_id parameter if provided
@@ -148,3 +215,23 @@ func (d *Daemon) handleRetry(req *Request) Response {
 
 	return Response{Result: "retrying"}
 }
+
+// handleQueue asks the controller to work on a bead next.
+func (d *Daemon) handleQueue(req *Request) Response {
+	if d.controller == nil {
+		return Response{Error: errNoController}
+	}
+
+	beadID := ""
+	if params, ok := req.Params.(map[string]interface{}); ok {
+		if id, ok := params["bead_id"].(string); ok {
+			beadID = id
+		}
+	}
+
+	if err := d.controller.QueueBead(beadID); err != nil {
+		return Response{Error: err.Error()}
+	}
+
+	return Response{Result: "queued"}
+}
@@ -0,0 +1,246 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+)
+
+// httpReadHeaderTimeout bounds how long a client may take to send headers.
+const httpReadHeaderTimeout = 10 * time.Second
+
+// httpTokenFile is the file, next to the control socket, that holds the
+// generated HTTP API token when http.token is not set.
+const httpTokenFile = "http-token"
+
+// startHTTP starts the HTTP API on the configured address and begins
+// collecting metrics from the event router. Without a configured token it
+// only binds loopback addresses, using a token generated into .atari/.
+func (d *Daemon) startHTTP(ctx context.Context) error {
+	listen := d.config.HTTP.Listen
+	if d.config.HTTP.Token == "" && !isLoopback(listen) {
+		return fmt.Errorf("listen on %s: http.token must be set to serve a non-loopback address", listen)
+	}
+	token, err := d.httpToken()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", listen, err)
+	}
+
+	server := &http.Server{
+		Handler:           d.HTTPHandler(token),
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	d.mu.Lock()
+	d.httpServer = server
+	if d.router != nil {
+		d.metricsSub = d.router.Subscribe()
+		go d.metrics.run(d.metricsSub)
+	}
+	d.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			d.logger.Error("http server error", "error", err)
+		}
+	}()
+
+	d.logger.Info("http api started", "addr", listener.Addr().String())
+	return nil
+}
+
+// stopHTTP closes the HTTP server and its event streams. Callers hold d.mu.
+func (d *Daemon) stopHTTP() {
+	if d.httpServer != nil {
+		if err := d.httpServer.Close(); err != nil {
+			d.logger.Error("error closing http server", "error", err)
+		}
+		d.httpServer = nil
+	}
+	if d.metricsSub != nil {
+		d.router.Unsubscribe(d.metricsSub)
+		d.metricsSub = nil
+	}
+}
+
+// HTTPTokenPath returns the file holding the generated HTTP API token.
+func HTTPTokenPath(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(cfg.Paths.Socket), httpTokenFile)
+}
+
+// httpToken returns the configured token, or the generated one, creating it
+// on first use. The file is readable only by its owner.
+func (d *Daemon) httpToken() (string, error) {
+	if d.config.HTTP.Token != "" {
+		return d.config.HTTP.Token, nil
+	}
+
+	path := HTTPTokenPath(d.config)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("read http token: %w", err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate http token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("write http token: %w", err)
+	}
+	return token, nil
+}
+
+// isLoopback reports whether addr only accepts connections from this host.
+// An empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// requireToken rejects requests that lack "Authorization: Bearer <token>".
+// Browsers cannot add the header to cross-site form posts, so this also
+// keeps web pages from driving the API.
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="atari"`)
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HTTPHandler returns the HTTP API: REST equivalents of the socket methods,
+// a server-sent event stream, and Prometheus metrics. Every endpoint
+// requires the bearer token.
+func (d *Daemon) HTTPHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", func(w http.ResponseWriter, r *http.Request) {
+		writeHTTPResponse(w, d.handleStatus())
+	})
+	mux.HandleFunc("POST /api/pause", func(w http.ResponseWriter, r *http.Request) {
+		writeHTTPResponse(w, d.handlePause())
+	})
+	mux.HandleFunc("POST /api/resume", func(w http.ResponseWriter, r *http.Request) {
+		writeHTTPResponse(w, d.handleResume())
+	})
+	mux.HandleFunc("POST /api/stop", func(w http.ResponseWriter, r *http.Request) {
+		force := r.URL.Query().Get("force") == "true"
+		writeHTTPResponse(w, d.handleStop(&Request{Params: map[string]interface{}{"force": force}}))
+	})
+	mux.HandleFunc("POST /api/retry", func(w http.ResponseWriter, r *http.Request) {
+		beadID := r.URL.Query().Get("bead_id")
+		writeHTTPResponse(w, d.handleRetry(&Request{Params: map[string]interface{}{"bead_id": beadID}}))
+	})
+	mux.HandleFunc("POST /api/queue", func(w http.ResponseWriter, r *http.Request) {
+		beadID := r.URL.Query().Get("bead_id")
+		writeHTTPResponse(w, d.handleQueue(&Request{Params: map[string]interface{}{"bead_id": beadID}}))
+	})
+	mux.HandleFunc("GET /api/events", d.handleEventStream)
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		d.writeMetrics(w)
+	})
+	return requireToken(token, mux)
+}
+
+// writeHTTPResponse writes a socket Response as JSON. Errors map to 503 when
+// the daemon has no controller and 409 otherwise.
+func writeHTTPResponse(w http.ResponseWriter, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	switch resp.Error {
+	case "":
+	case errNoController:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusConflict)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handleEventStream streams events as server-sent events. Query parameters
+// mirror SubscribeParams: type (repeatable or comma-separated), bead, and
+// since (RFC 3339).
+func (d *Daemon) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	params := SubscribeParams{BeadID: query.Get("bead")}
+	for _, t := range query["type"] {
+		for _, part := range strings.Split(t, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				params.Types = append(params.Types, part)
+			}
+		}
+	}
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid since: %v", err), http.StatusBadRequest)
+			return
+		}
+		params.Since = t
+	}
+
+	stream, err := d.openStream(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream.run(r.Context(), nil, func(ev events.Event) bool {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return true
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type(), data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	})
+}
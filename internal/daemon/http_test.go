@@ -0,0 +1,316 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+)
+
+const testHTTPToken = "test-token"
+
+// apiRequest sends an authenticated request to the HTTP API.
+func apiRequest(t *testing.T, method, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testHTTPToken)
+	return http.DefaultClient.Do(req)
+}
+
+func TestHTTPHandler_NoController(t *testing.T) {
+	d := New(config.Default(), nil, nil)
+	server := httptest.NewServer(d.HTTPHandler(testHTTPToken))
+	defer server.Close()
+
+	resp, err := apiRequest(t, http.MethodGet, server.URL+"/api/status")
+	if err != nil {
+		t.Fatalf("GET /api/status: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status code = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	var body Response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != errNoController {
+		t.Errorf("error = %q, want %q", body.Error, errNoController)
+	}
+
+	// Actions are POST only
+	resp2, err := apiRequest(t, http.MethodGet, server.URL+"/api/pause")
+	if err != nil {
+		t.Fatalf("GET /api/pause: %v", err)
+	}
+	_ = resp2.Body.Close()
+	if resp2.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /api/pause status code = %d, want %d", resp2.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHTTPHandler_WithController(t *testing.T) {
+	env := newTestDaemonEnv(t)
+	defer env.cleanup()
+
+	server := httptest.NewServer(env.daemon.HTTPHandler(testHTTPToken))
+	defer server.Close()
+
+	resp, err := apiRequest(t, http.MethodGet, server.URL+"/api/status")
+	if err != nil {
+		t.Fatalf("GET /api/status: %v", err)
+	}
+	var body struct {
+		Result StatusResponse `json:"result"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Result.Status != "idle" {
+		t.Errorf("GET /api/status = %d %+v, want 200 idle", resp.StatusCode, body.Result)
+	}
+
+	resp, err = apiRequest(t, http.MethodPost, server.URL+"/api/pause")
+	if err != nil {
+		t.Fatalf("POST /api/pause: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), `"pausing"`) {
+		t.Errorf("POST /api/pause = %d %s", resp.StatusCode, data)
+	}
+
+	resp, err = apiRequest(t, http.MethodGet, server.URL+"/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	data, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	for _, want := range []string{
+		`atari_state{state="idle"} 1`,
+		`atari_beads{status="completed"} 0`,
+		"atari_backoff_queue_depth 0",
+		"atari_workers 1",
+		"# TYPE atari_session_duration_seconds histogram",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("metrics missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestMetrics_ObserveIterations(t *testing.T) {
+	m := newMetrics()
+	m.observe(&events.IterationEndEvent{
+		BaseEvent:  events.NewInternalEvent(events.EventIterationEnd),
+		Success:    true,
+		NumTurns:   7,
+		DurationMs: 90_000,
+	})
+	m.observe(&events.IterationEndEvent{
+		BaseEvent:  events.NewInternalEvent(events.EventIterationEnd),
+		NumTurns:   300,
+		DurationMs: 10_000,
+	})
+	m.observe(claudeText("ignored", time.Now()))
+
+	d := &Daemon{metrics: m}
+	var out strings.Builder
+	d.writeMetrics(&out)
+	text := out.String()
+
+	for _, want := range []string{
+		`atari_attempts_total{result="success"} 1`,
+		`atari_attempts_total{result="failure"} 1`,
+		`atari_session_duration_seconds_bucket{le="30"} 1`,
+		`atari_session_duration_seconds_bucket{le="60"} 1`,
+		`atari_session_duration_seconds_bucket{le="120"} 2`,
+		"atari_session_duration_seconds_sum 100",
+		`atari_session_turns_bucket{le="10"} 1`,
+		`atari_session_turns_bucket{le="200"} 1`,
+		`atari_session_turns_bucket{le="+Inf"} 2`,
+		"atari_session_turns_count 2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("metrics missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestHTTPHandler_EventStream(t *testing.T) {
+	router := events.NewRouter(100)
+	defer router.Close()
+	d := New(config.Default(), nil, nil, WithRouter(router))
+	server := httptest.NewServer(d.HTTPHandler(testHTTPToken))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/events?type=iteration.start", nil)
+	req.Header.Set("Authorization", "Bearer "+testHTTPToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/events: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	router.Emit(claudeText("filtered out", time.Now()))
+	router.Emit(iterationStart("bd-1", time.Now()))
+
+	reader := bufio.NewReader(resp.Body)
+	eventLine, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	dataLine, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if eventLine != "event: iteration.start\n" {
+		t.Errorf("event line = %q", eventLine)
+	}
+	if !strings.HasPrefix(dataLine, "data: ") || !strings.Contains(dataLine, `"bead_id":"bd-1"`) {
+		t.Errorf("data line = %q", dataLine)
+	}
+}
+
+func TestHTTPHandler_EventStreamBadSince(t *testing.T) {
+	router := events.NewRouter(100)
+	defer router.Close()
+	d := New(config.Default(), nil, nil, WithRouter(router))
+	server := httptest.NewServer(d.HTTPHandler(testHTTPToken))
+	defer server.Close()
+
+	resp, err := apiRequest(t, http.MethodGet, server.URL+"/api/events?since=yesterday")
+	if err != nil {
+		t.Fatalf("GET /api/events: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status code = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestHTTPHandler_RequiresToken(t *testing.T) {
+	d := New(config.Default(), nil, nil)
+	server := httptest.NewServer(d.HTTPHandler(testHTTPToken))
+	defer server.Close()
+
+	for _, auth := range []string{"", "Bearer wrong", testHTTPToken} {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/stop?force=true", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST /api/stop: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status code = %d, want %d", auth, resp.StatusCode, http.StatusUnauthorized)
+		}
+		if resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("Authorization %q: missing WWW-Authenticate header", auth)
+		}
+	}
+}
+
+func TestDaemon_HTTPToken(t *testing.T) {
+	cfg := config.Default()
+	cfg.Paths.Socket = filepath.Join(t.TempDir(), "atari.sock")
+	d := New(cfg, nil, nil)
+
+	token, err := d.httpToken()
+	if err != nil {
+		t.Fatalf("httpToken: %v", err)
+	}
+	if len(token) != 64 {
+		t.Errorf("generated token = %q, want 64 hex characters", token)
+	}
+	info, err := os.Stat(HTTPTokenPath(cfg))
+	if err != nil {
+		t.Fatalf("stat token file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("token file mode = %o, want 600", perm)
+	}
+
+	again, err := d.httpToken()
+	if err != nil || again != token {
+		t.Errorf("httpToken again = %q, %v; want the stored %q", again, err, token)
+	}
+
+	cfg.HTTP.Token = "configured"
+	if got, _ := d.httpToken(); got != "configured" {
+		t.Errorf("httpToken with http.token set = %q, want configured", got)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:9181", true},
+		{"[::1]:9181", true},
+		{"localhost:9181", true},
+		{":9181", false},
+		{"0.0.0.0:9181", false},
+		{"192.168.1.10:9181", false},
+		{"not-an-address", false},
+	}
+	for _, tt := range tests {
+		if got := isLoopback(tt.addr); got != tt.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestDaemon_StartHTTPNonLoopbackNeedsToken(t *testing.T) {
+	cfg := config.Default()
+	cfg.Paths.Socket = shortSocketPath(t)
+	cfg.HTTP.Listen = ":0"
+
+	d := New(cfg, nil, nil)
+	err := d.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "http.token") {
+		t.Fatalf("expected http.token error, got %v", err)
+	}
+	if d.Running() {
+		t.Error("daemon should not be running after a failed start")
+	}
+}
+
+func TestDaemon_StartHTTPInvalidAddress(t *testing.T) {
+	cfg := config.Default()
+	cfg.Paths.Socket = shortSocketPath(t)
+	cfg.HTTP.Listen = "not-an-address"
+	cfg.HTTP.Token = testHTTPToken
+
+	d := New(cfg, nil, nil)
+	err := d.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "start http api") {
+		t.Fatalf("expected http listen error, got %v", err)
+	}
+	if d.Running() {
+		t.Error("daemon should not be running after a failed start")
+	}
+}
@@ -337,7 +337,46 @@ func TestDaemonStatus_Stats(t *testing.T) {
 	t.Logf("Stats: iteration=%d, total_seen=%d, completed=%d",
 		status.Stats.Iteration, status.Stats.TotalSeen, status.Stats.Completed)
 
+	// Default config runs a single idle worker
+	if len(status.Workers) != 1 {
+		t.Fatalf("expected 1 worker, got %d", len(status.Workers))
+	}
+	if status.Workers[0].ID != 1 || status.Workers[0].BeadID != "" {
+		t.Errorf("expected idle worker 1, got %+v", status.Workers[0])
+	}
+	if status.BeadCache != nil {
+		t.Errorf("expected no bead cache stats without a cache, got %+v", status.BeadCache)
+	}
+
 	// Stop daemon
 	cancel()
 	<-errCh
 }
+
+func TestDaemonStatus_BeadCache(t *testing.T) {
+	cfg := config.Default()
+	mockClient := brclient.NewMockClient()
+	mockClient.SetShowResponse("bd-1", &brclient.Bead{ID: "bd-1", Status: "open"})
+	cache := brclient.NewCachingClient(mockClient, time.Minute)
+
+	router := events.NewRouter(1000)
+	defer router.Close()
+	ctrl := controller.New(cfg, workqueue.New(cfg, cache, nil), router, cache, nil, nil)
+	d := New(cfg, ctrl, nil)
+
+	for range 3 {
+		if _, err := cache.Show(context.Background(), "bd-1"); err != nil {
+			t.Fatalf("Show() error: %v", err)
+		}
+	}
+
+	resp := d.handleStatus()
+	status, ok := resp.Result.(StatusResponse)
+	if !ok {
+		t.Fatalf("expected StatusResponse, got %T (error %q)", resp.Result, resp.Error)
+	}
+	want := BeadCacheStatus{Hits: 2, Misses: 1}
+	if status.BeadCache == nil || *status.BeadCache != want {
+		t.Errorf("expected bead cache %+v, got %+v", want, status.BeadCache)
+	}
+}
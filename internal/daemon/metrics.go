@@ -0,0 +1,162 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/npratt/atari/internal/controller"
+	"github.com/npratt/atari/internal/events"
+)
+
+// Histogram bucket upper bounds for bead attempts.
+var (
+	sessionDurationBuckets = []float64{30, 60, 120, 300, 600, 1200, 1800, 3600, 7200}
+	sessionTurnBuckets     = []float64{1, 2, 5, 10, 20, 50, 100, 200}
+)
+
+// controllerStates lists every state reported by the atari_state metric.
+var controllerStates = []controller.State{
+	controller.StateIdle,
+	controller.StateWorking,
+	controller.StatePaused,
+	controller.StateStalled,
+	controller.StateStopping,
+	controller.StateStopped,
+}
+
+// histogram is a cumulative Prometheus-style histogram.
+type histogram struct {
+	bounds []float64
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+// observe records one value.
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// write renders the histogram in the Prometheus text format.
+func (h *histogram) write(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", name, formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// metrics accumulates per-attempt measurements from the event stream.
+// Totals that the controller already tracks are read from it on each scrape.
+type metrics struct {
+	mu        sync.Mutex
+	durations *histogram
+	turns     *histogram
+	succeeded uint64
+	failed    uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		durations: newHistogram(sessionDurationBuckets),
+		turns:     newHistogram(sessionTurnBuckets),
+	}
+}
+
+// observe records the outcome of a finished bead attempt.
+func (m *metrics) observe(ev events.Event) {
+	e, ok := ev.(*events.IterationEndEvent)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations.observe(float64(e.DurationMs) / 1000)
+	m.turns.observe(float64(e.NumTurns))
+	if e.Success {
+		m.succeeded++
+	} else {
+		m.failed++
+	}
+}
+
+// run records events until the channel is closed.
+func (m *metrics) run(ch <-chan events.Event) {
+	for ev := range ch {
+		m.observe(ev)
+	}
+}
+
+// writeMetrics renders all metrics in the Prometheus text exposition format.
+func (d *Daemon) writeMetrics(w io.Writer) {
+	if d.controller != nil {
+		stats := d.controller.Stats()
+		state := d.controller.State()
+
+		fmt.Fprintf(w, "# HELP atari_state Current controller state (1 for the active state).\n# TYPE atari_state gauge\n")
+		for _, s := range controllerStates {
+			value := 0
+			if s == state {
+				value = 1
+			}
+			fmt.Fprintf(w, "atari_state{state=%q} %d\n", s, value)
+		}
+
+		writeMetric(w, "atari_iterations_total", "counter", "Bead attempts started.", float64(stats.Iteration))
+		writeMetric(w, "atari_beads_seen_total", "counter", "Distinct beads picked up by the work queue.", float64(stats.QueueStats.TotalSeen))
+
+		fmt.Fprintf(w, "# HELP atari_beads Beads by their latest outcome.\n# TYPE atari_beads gauge\n")
+		fmt.Fprintf(w, "atari_beads{status=\"completed\"} %d\n", stats.QueueStats.Completed)
+		fmt.Fprintf(w, "atari_beads{status=\"failed\"} %d\n", stats.QueueStats.Failed)
+		fmt.Fprintf(w, "atari_beads{status=\"abandoned\"} %d\n", stats.QueueStats.Abandoned)
+
+		writeMetric(w, "atari_backoff_queue_depth", "gauge", "Failed beads waiting out their backoff.", float64(stats.QueueStats.InBackoff))
+		writeMetric(w, "atari_cost_usd", "gauge", "Spending so far, including estimates for running sessions.", stats.TotalCostUSD)
+
+		busy := 0
+		for _, worker := range stats.Workers {
+			if worker.BeadID != "" {
+				busy++
+			}
+		}
+		writeMetric(w, "atari_workers", "gauge", "Configured workers.", float64(len(stats.Workers)))
+		writeMetric(w, "atari_workers_busy", "gauge", "Workers currently running a bead.", float64(busy))
+		writeMetric(w, "atari_current_turns", "gauge", "Turns completed in the current session.", float64(stats.CurrentTurns))
+	}
+
+	m := d.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fmt.Fprintf(w, "# HELP atari_attempts_total Finished bead attempts by result.\n# TYPE atari_attempts_total counter\n")
+	fmt.Fprintf(w, "atari_attempts_total{result=\"success\"} %d\n", m.succeeded)
+	fmt.Fprintf(w, "atari_attempts_total{result=\"failure\"} %d\n", m.failed)
+	m.durations.write(w, "atari_session_duration_seconds", "Duration of finished bead attempts, including follow-up sessions.")
+	m.turns.write(w, "atari_session_turns", "Turns used by finished bead attempts.")
+}
+
+// writeMetric renders a single unlabeled metric.
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", name, help, name, kind, name, formatFloat(value))
+}
+
+// formatFloat formats a metric value in the shortest form that round-trips.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -46,10 +46,13 @@ func ResolvePaths(paths config.PathsConfig, basePath string) (config.PathsConfig
 	}
 
 	return config.PathsConfig{
-		State:  resolve(paths.State),
-		Log:    resolve(paths.Log),
-		Socket: resolve(paths.Socket),
-		PID:    resolve(paths.PID),
+		State:     resolve(paths.State),
+		Log:       resolve(paths.Log),
+		Socket:    resolve(paths.Socket),
+		PID:       resolve(paths.PID),
+		Worktrees: resolve(paths.Worktrees),
+		History:   resolve(paths.History),
+		Sessions:  resolve(paths.Sessions),
 	}, nil
 }
 
@@ -149,6 +152,16 @@ func RemoveDaemonInfo(path string) error {
 	return nil
 }
 
+// OtherInstancePID returns the PID recorded in the project's daemon.json if
+// that process is still running and is not this one, or 0 otherwise.
+func OtherInstancePID(projectRoot string) int {
+	info, err := ReadDaemonInfo(DaemonInfoPath(projectRoot))
+	if err != nil || info.PID == os.Getpid() || !ProcessAlive(info.PID) {
+		return 0
+	}
+	return info.PID
+}
+
 // DaemonInfoPath returns the path to daemon.json in the .atari directory
 // relative to the project root.
 func DaemonInfoPath(projectRoot string) string {
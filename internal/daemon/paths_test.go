@@ -13,10 +13,11 @@ func TestResolvePaths_RelativePaths(t *testing.T) {
 	tmp := t.TempDir()
 
 	paths := config.PathsConfig{
-		State:  ".atari/state.json",
-		Log:    ".atari/atari.log",
-		Socket: ".atari/atari.sock",
-		PID:    ".atari/atari.pid",
+		State:     ".atari/state.json",
+		Log:       ".atari/atari.log",
+		Socket:    ".atari/atari.sock",
+		PID:       ".atari/atari.pid",
+		Worktrees: ".atari/worktrees",
 	}
 
 	resolved, err := ResolvePaths(paths, tmp)
@@ -25,10 +26,11 @@ func TestResolvePaths_RelativePaths(t *testing.T) {
 	}
 
 	expected := config.PathsConfig{
-		State:  filepath.Join(tmp, ".atari/state.json"),
-		Log:    filepath.Join(tmp, ".atari/atari.log"),
-		Socket: filepath.Join(tmp, ".atari/atari.sock"),
-		PID:    filepath.Join(tmp, ".atari/atari.pid"),
+		State:     filepath.Join(tmp, ".atari/state.json"),
+		Log:       filepath.Join(tmp, ".atari/atari.log"),
+		Socket:    filepath.Join(tmp, ".atari/atari.sock"),
+		PID:       filepath.Join(tmp, ".atari/atari.pid"),
+		Worktrees: filepath.Join(tmp, ".atari/worktrees"),
 	}
 
 	if resolved.State != expected.State {
@@ -43,6 +45,9 @@ func TestResolvePaths_RelativePaths(t *testing.T) {
 	if resolved.PID != expected.PID {
 		t.Errorf("PID: expected %q, got %q", expected.PID, resolved.PID)
 	}
+	if resolved.Worktrees != expected.Worktrees {
+		t.Errorf("Worktrees: expected %q, got %q", expected.Worktrees, resolved.Worktrees)
+	}
 }
 
 func TestResolvePaths_AbsolutePaths(t *testing.T) {
@@ -320,6 +325,35 @@ func TestFindDaemonInfo_NotFound(t *testing.T) {
 	}
 }
 
+func TestOtherInstancePID(t *testing.T) {
+	tmpDir := t.TempDir()
+	infoPath := DaemonInfoPath(tmpDir)
+
+	if pid := OtherInstancePID(tmpDir); pid != 0 {
+		t.Errorf("without daemon.json: got pid %d, want 0", pid)
+	}
+
+	tests := []struct {
+		name string
+		pid  int
+		want int
+	}{
+		{"this process", os.Getpid(), 0},
+		{"live process", os.Getppid(), os.Getppid()},
+		{"no process", -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := WriteDaemonInfo(infoPath, &DaemonInfo{PID: tt.pid}); err != nil {
+				t.Fatalf("WriteDaemonInfo: %v", err)
+			}
+			if got := OtherInstancePID(tmpDir); got != tt.want {
+				t.Errorf("OtherInstancePID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDaemonInfoPath(t *testing.T) {
 	path := DaemonInfoPath("/project")
 	expected := "/project/.atari/daemon.json"
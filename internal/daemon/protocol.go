@@ -1,5 +1,7 @@
 package daemon
 
+import "time"
+
 // Request represents a JSON-RPC request from a client.
 type Request struct {
 	Method string `json:"method"`
@@ -16,22 +18,73 @@ type Response struct {
 
 // StatusResponse contains daemon status information.
 type StatusResponse struct {
-	Status      string      `json:"status"`
-	CurrentBead string      `json:"current_bead,omitempty"`
-	Uptime      string      `json:"uptime"`
-	StartTime   string      `json:"start_time"`
-	Stats       StatusStats `json:"stats"`
+	Status      string           `json:"status"`
+	CurrentBead string           `json:"current_bead,omitempty"` // first busy worker's bead; see Workers for all
+	Uptime      string           `json:"uptime"`
+	StartTime   string           `json:"start_time"`
+	Stats       StatusStats      `json:"stats"`
+	Workers     []WorkerStatus   `json:"workers,omitempty"`
+	Stall       *StallStatus     `json:"stall,omitempty"`
+	Blocked     *BlockedStatus   `json:"blocked,omitempty"` // bead with the shortest remaining backoff
+	Schedule    *ScheduleStatus  `json:"schedule,omitempty"`
+	BeadCache   *BeadCacheStatus `json:"bead_cache,omitempty"` // nil when bead reads are not cached
 }
 
 // StatusStats contains queue statistics for the status response.
 type StatusStats struct {
-	Iteration    int `json:"iteration"`
-	CurrentTurns int `json:"current_turns"`
-	TotalSeen    int `json:"total_seen"`
-	Completed    int `json:"completed"`
-	Failed       int `json:"failed"`
-	Abandoned    int `json:"abandoned"`
-	InBackoff    int `json:"in_backoff"`
+	Iteration    int     `json:"iteration"`
+	CurrentTurns int     `json:"current_turns"` // turns in the first busy worker's session
+	TotalSeen    int     `json:"total_seen"`
+	Completed    int     `json:"completed"`
+	Failed       int     `json:"failed"`
+	Abandoned    int     `json:"abandoned"`
+	InBackoff    int     `json:"in_backoff"`
+	TotalCostUSD float64 `json:"total_cost_usd"` // includes estimates for running sessions
+}
+
+// WorkerStatus contains the progress of a single worker.
+type WorkerStatus struct {
+	ID              int       `json:"id"`
+	BeadID          string    `json:"bead_id,omitempty"`
+	BeadTitle       string    `json:"bead_title,omitempty"`
+	Turns           int       `json:"turns"`
+	Elapsed         string    `json:"elapsed,omitempty"`
+	CostUSD         float64   `json:"cost_usd,omitempty"`         // estimated cost of the running session
+	Model           string    `json:"model,omitempty"`            // model selected for the bead
+	StartedAt       time.Time `json:"started_at,omitzero"`        // when the worker started the bead
+	SelectedBecause string    `json:"selected_because,omitempty"` // why the selection strategy chose the bead
+}
+
+// StallStatus describes why the drain is stalled.
+type StallStatus struct {
+	BeadID       string    `json:"bead_id,omitempty"`
+	BeadTitle    string    `json:"bead_title,omitempty"`
+	Reason       string    `json:"reason"`
+	Type         string    `json:"type"` // "abandoned", "review", or "budget"
+	StalledAt    time.Time `json:"stalled_at"`
+	CreatedBeads []string  `json:"created_beads,omitempty"` // beads created in the session (review stalls)
+}
+
+// BlockedStatus describes a bead waiting out its backoff.
+type BlockedStatus struct {
+	BeadID    string `json:"bead_id"`
+	Failures  int    `json:"failures"`
+	RetryIn   string `json:"retry_in"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// ScheduleStatus describes the drain schedule's current window.
+type ScheduleStatus struct {
+	Open   bool      `json:"open"`
+	Window string    `json:"window,omitempty"` // the open window as configured
+	Next   time.Time `json:"next,omitzero"`    // when the window closes or the next one opens
+}
+
+// BeadCacheStatus counts the bead reads served by the cache.
+type BeadCacheStatus struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`    // reads that ran br
+	Coalesced int64 `json:"coalesced"` // reads that shared an identical one in flight
 }
 
 // StopParams contains parameters for the stop method.
@@ -43,3 +96,15 @@ type StopParams struct {
 type RetryParams struct {
 	BeadID string `json:"bead_id,omitempty"`
 }
+
+// QueueParams contains parameters for the queue method.
+type QueueParams struct {
+	BeadID string `json:"bead_id"`
+}
+
+// SubscribeParams contains parameters for the subscribe method.
+type SubscribeParams struct {
+	Types  []string  `json:"types,omitempty"`   // event types to stream (empty = all)
+	BeadID string    `json:"bead_id,omitempty"` // only events for this bead
+	Since  time.Time `json:"since,omitzero"`    // replay logged events after this time first
+}
@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/npratt/atari/internal/viewmodel"
+)
+
+// RemoteStats serves controller statistics for a TUI attached to a daemon.
+// It caches the latest status response so the TUI never waits on the socket.
+type RemoteStats struct {
+	client *Client
+
+	mu     sync.RWMutex
+	status *StatusResponse
+}
+
+// NewRemoteStats creates a RemoteStats that polls the given client.
+func NewRemoteStats(client *Client) *RemoteStats {
+	return &RemoteStats{client: client}
+}
+
+// Refresh fetches the current status from the daemon. On error the last
+// good status is kept.
+func (r *RemoteStats) Refresh() error {
+	status, err := r.client.Status()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.status = status
+	r.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the status every interval until ctx is cancelled.
+func (r *RemoteStats) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = r.Refresh()
+		}
+	}
+}
+
+// Status returns the latest status, or nil if none has been fetched.
+func (r *RemoteStats) Status() *StatusResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// snapshot returns the latest status, or an empty one if none was fetched.
+func (r *RemoteStats) snapshot() StatusResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.status == nil {
+		return StatusResponse{}
+	}
+	return *r.status
+}
+
+// Iteration returns the daemon's iteration count.
+func (r *RemoteStats) Iteration() int {
+	return r.snapshot().Stats.Iteration
+}
+
+// Completed returns the number of successfully completed beads.
+func (r *RemoteStats) Completed() int {
+	return r.snapshot().Stats.Completed
+}
+
+// Failed returns the number of failed beads.
+func (r *RemoteStats) Failed() int {
+	return r.snapshot().Stats.Failed
+}
+
+// Abandoned returns the number of abandoned beads.
+func (r *RemoteStats) Abandoned() int {
+	return r.snapshot().Stats.Abandoned
+}
+
+// CurrentBead returns the ID of the bead being worked on, or "".
+func (r *RemoteStats) CurrentBead() string {
+	return r.snapshot().CurrentBead
+}
+
+// CurrentTurns returns the turns completed in the current session.
+func (r *RemoteStats) CurrentTurns() int {
+	return r.snapshot().Stats.CurrentTurns
+}
+
+// GetStats converts the latest status to TUI statistics.
+func (r *RemoteStats) GetStats() viewmodel.TUIStats {
+	status := r.snapshot()
+
+	stats := viewmodel.TUIStats{
+		Completed:    status.Stats.Completed,
+		Failed:       status.Stats.Failed,
+		Abandoned:    status.Stats.Abandoned,
+		InBackoff:    status.Stats.InBackoff,
+		CurrentBead:  status.CurrentBead,
+		CurrentTurns: status.Stats.CurrentTurns,
+	}
+
+	for _, w := range status.Workers {
+		stats.Workers = append(stats.Workers, viewmodel.WorkerInfo{
+			ID:              w.ID,
+			BeadID:          w.BeadID,
+			BeadTitle:       w.BeadTitle,
+			StartedAt:       w.StartedAt,
+			Turns:           w.Turns,
+			CostUSD:         w.CostUSD,
+			Model:           w.Model,
+			SelectedBecause: w.SelectedBecause,
+		})
+	}
+
+	if b := status.Blocked; b != nil {
+		retryIn, _ := time.ParseDuration(b.RetryIn)
+		stats.TopBlockedBead = &viewmodel.BlockedBeadInfo{
+			BeadID:       b.BeadID,
+			FailureCount: b.Failures,
+			RetryIn:      retryIn,
+			LastError:    b.LastError,
+		}
+	}
+
+	if s := status.Stall; s != nil {
+		stats.StalledBeadID = s.BeadID
+		stats.StalledBeadTitle = s.BeadTitle
+		stats.StallReason = s.Reason
+		stats.StalledAt = s.StalledAt
+		stats.StallType = s.Type
+		stats.CreatedBeads = s.CreatedBeads
+	}
+
+	if s := status.Schedule; s != nil {
+		stats.Schedule = &viewmodel.ScheduleInfo{
+			Open:   s.Open,
+			Window: s.Window,
+			Next:   s.Next,
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRemoteStats_GetStats(t *testing.T) {
+	sockPath := shortSocketPath(t)
+	started := time.Now().Add(-time.Minute).Truncate(time.Second)
+	stalledAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	opens := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	cleanup := mockServer(t, sockPath, func(req Request) Response {
+		return Response{Result: StatusResponse{
+			Status:      "stalled",
+			CurrentBead: "bd-002",
+			Stats: StatusStats{
+				Iteration:    4,
+				CurrentTurns: 3,
+				Completed:    2,
+				Failed:       1,
+				InBackoff:    1,
+			},
+			Workers: []WorkerStatus{
+				{ID: 1, BeadID: "bd-002", BeadTitle: "Fix it", Turns: 3, Model: "opus", StartedAt: started, SelectedBecause: "unblocks 2 beads"},
+			},
+			Stall: &StallStatus{
+				BeadID:    "bd-001",
+				Reason:    "max failures",
+				Type:      "abandoned",
+				StalledAt: stalledAt,
+			},
+			Blocked:  &BlockedStatus{BeadID: "bd-003", Failures: 2, RetryIn: "1m30s", LastError: "boom"},
+			Schedule: &ScheduleStatus{Open: false, Next: opens},
+		}}
+	})
+	defer cleanup()
+
+	stats := NewRemoteStats(NewClient(sockPath))
+	if got := stats.GetStats(); got.Completed != 0 || got.Workers != nil {
+		t.Fatalf("expected empty stats before the first refresh, got %+v", got)
+	}
+
+	if err := stats.Refresh(); err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+
+	if stats.Iteration() != 4 || stats.CurrentBead() != "bd-002" || stats.CurrentTurns() != 3 {
+		t.Errorf("unexpected counters: iteration=%d bead=%q turns=%d",
+			stats.Iteration(), stats.CurrentBead(), stats.CurrentTurns())
+	}
+
+	got := stats.GetStats()
+	if got.Completed != 2 || got.Failed != 1 || got.InBackoff != 1 {
+		t.Errorf("unexpected queue stats: %+v", got)
+	}
+	if len(got.Workers) != 1 || got.Workers[0].Model != "opus" || got.Workers[0].SelectedBecause != "unblocks 2 beads" || !got.Workers[0].StartedAt.Equal(started) {
+		t.Errorf("unexpected workers: %+v", got.Workers)
+	}
+	if got.StallReason != "max failures" || got.StallType != "abandoned" || !got.StalledAt.Equal(stalledAt) {
+		t.Errorf("unexpected stall info: %+v", got)
+	}
+	if got.TopBlockedBead == nil || got.TopBlockedBead.RetryIn != 90*time.Second || got.TopBlockedBead.FailureCount != 2 {
+		t.Errorf("unexpected blocked bead: %+v", got.TopBlockedBead)
+	}
+	if got.Schedule == nil || got.Schedule.Open || !got.Schedule.Next.Equal(opens) {
+		t.Errorf("unexpected schedule: %+v", got.Schedule)
+	}
+}
+
+func TestRemoteStats_RefreshKeepsLastStatus(t *testing.T) {
+	sockPath := shortSocketPath(t)
+	cleanup := mockServer(t, sockPath, func(req Request) Response {
+		return Response{Result: StatusResponse{Status: "working", Stats: StatusStats{Completed: 5}}}
+	})
+
+	stats := NewRemoteStats(NewClient(sockPath))
+	if err := stats.Refresh(); err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+	cleanup()
+
+	if err := stats.Refresh(); err == nil {
+		t.Error("expected an error once the daemon is gone")
+	}
+	if stats.Completed() != 5 {
+		t.Errorf("Completed() = %d, want the last good value 5", stats.Completed())
+	}
+}
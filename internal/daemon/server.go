@@ -52,6 +52,13 @@ func (d *Daemon) Start(ctx context.Context) error {
 
 	d.logger.Info("daemon started", "socket", d.sockPath)
 
+	if d.config.HTTP.Listen != "" {
+		if err := d.startHTTP(ctx); err != nil {
+			_ = d.Stop()
+			return fmt.Errorf("start http api: %w", err)
+		}
+	}
+
 	// Start accept loop
 	go d.serve(ctx)
 
@@ -74,6 +81,7 @@ func (d *Daemon) Stop() error {
 	}
 
 	d.running = false
+	d.stopHTTP()
 
 	if d.listener != nil {
 		if err := d.listener.Close(); err != nil {
@@ -115,6 +123,7 @@ func (d *Daemon) serve(ctx context.Context) {
 }
 
 // handleConnection reads a request, dispatches it, and writes the response.
+// A subscribe request instead streams events until the client disconnects.
 func (d *Daemon) handleConnection(ctx context.Context, conn net.Conn) {
 	defer func() { _ = conn.Close() }()
 
@@ -135,6 +144,12 @@ func (d *Daemon) handleConnection(ctx context.Context, conn net.Conn) {
 		return
 	}
 
+	// Subscriptions keep the connection open and stream events
+	if req.Method == "subscribe" {
+		d.handleSubscribe(ctx, conn, &req)
+		return
+	}
+
 	resp := d.handleRequest(ctx, &req)
 	resp.ID = req.ID
 	_ = encoder.Encode(resp)
@@ -0,0 +1,211 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/observer"
+)
+
+const (
+	// subscribeBufferSize is the router buffer for each subscriber. Events are
+	// dropped by the router if a subscriber falls this far behind.
+	subscribeBufferSize = 1000
+	// writeTimeout bounds each write to a subscriber so a stuck client cannot
+	// hold a subscription open forever.
+	writeTimeout = 10 * time.Second
+)
+
+// EventFilter applies the type and bead filters of SubscribeParams to an
+// ordered event stream. Events that carry no bead ID, such as agent output,
+// match a bead filter while that bead's iteration is running.
+type EventFilter struct {
+	types  []string
+	beadID string
+	active bool // the filtered bead's iteration is running
+}
+
+// NewEventFilter creates a filter for the given subscription parameters.
+func NewEventFilter(params SubscribeParams) *EventFilter {
+	return &EventFilter{types: params.Types, beadID: params.BeadID}
+}
+
+// Match reports whether the event passes the filter. Events must be passed
+// in order, since bead iterations are tracked across calls.
+func (f *EventFilter) Match(ev events.Event) bool {
+	inBead := f.track(ev)
+	if len(f.types) > 0 && !slices.Contains(f.types, string(ev.Type())) {
+		return false
+	}
+	if f.beadID == "" {
+		return true
+	}
+	if id := events.GetBeadID(ev); id != "" {
+		return id == f.beadID
+	}
+	return inBead
+}
+
+// track updates the iteration state and reports whether the filtered bead's
+// iteration is running, including the events that start and end it.
+func (f *EventFilter) track(ev events.Event) bool {
+	if f.beadID == "" {
+		return false
+	}
+	switch e := ev.(type) {
+	case *events.IterationStartEvent:
+		if e.BeadID == f.beadID {
+			f.active = true
+		}
+	case *events.IterationEndEvent:
+		if e.BeadID == f.beadID && f.active {
+			f.active = false
+			return true
+		}
+	}
+	return f.active
+}
+
+// eventStream is an open subscription: logged events to replay, then live
+// events from the router, both passed through the subscription's filter.
+type eventStream struct {
+	router *events.Router
+	sub    <-chan events.Event
+	replay []events.Event
+	filter *EventFilter
+}
+
+// openStream subscribes to the router and reads the events to replay. The
+// caller must Close the stream.
+func (d *Daemon) openStream(params SubscribeParams) (*eventStream, error) {
+	if d.router == nil {
+		return nil, errors.New("no event router available")
+	}
+
+	// Subscribe before reading the log so no event falls between the two
+	s := &eventStream{
+		router: d.router,
+		sub:    d.router.SubscribeBuffered(subscribeBufferSize),
+		filter: NewEventFilter(params),
+	}
+	if !params.Since.IsZero() {
+		replay, err := observer.NewLogReader(d.config.Paths.Log).ReadAfterTimestamp(params.Since)
+		if err != nil && !errors.Is(err, observer.ErrFileNotFound) {
+			s.Close()
+			return nil, fmt.Errorf("read event log: %w", err)
+		}
+		s.replay = replay
+	}
+	return s, nil
+}
+
+// Close unsubscribes from the router.
+func (s *eventStream) Close() {
+	s.router.Unsubscribe(s.sub)
+}
+
+// run passes matching events to send until ctx or done is closed, the router
+// closes, or send reports false.
+func (s *eventStream) run(ctx context.Context, done <-chan struct{}, send func(events.Event) bool) {
+	var replayedUntil time.Time
+	for _, ev := range s.replay {
+		if s.filter.Match(ev) && !send(ev) {
+			return
+		}
+		replayedUntil = ev.Timestamp()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case ev, ok := <-s.sub:
+			if !ok {
+				return
+			}
+			// Skip live events the log replay already delivered
+			if !ev.Timestamp().After(replayedUntil) {
+				continue
+			}
+			if s.filter.Match(ev) && !send(ev) {
+				return
+			}
+		}
+	}
+}
+
+// handleSubscribe streams events to the client as newline-delimited JSON until
+// the client disconnects, the router closes, or the daemon shuts down. The
+// first line is a Response acknowledging the subscription or reporting an
+// error; every line after it is an event in the event log format.
+func (d *Daemon) handleSubscribe(ctx context.Context, conn net.Conn, req *Request) {
+	encoder := json.NewEncoder(conn)
+
+	params, err := decodeSubscribeParams(req.Params)
+	if err != nil {
+		_ = encoder.Encode(Response{Error: err.Error(), ID: req.ID})
+		return
+	}
+	stream, err := d.openStream(params)
+	if err != nil {
+		_ = encoder.Encode(Response{Error: err.Error(), ID: req.ID})
+		return
+	}
+	defer stream.Close()
+
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		d.logger.Error("clear read deadline error", "error", err)
+		return
+	}
+	if !d.send(conn, encoder, Response{Result: "subscribed", ID: req.ID}) {
+		return
+	}
+
+	// The client sends nothing more; a read returning means it hung up
+	disconnected := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		close(disconnected)
+	}()
+
+	stream.run(ctx, disconnected, func(ev events.Event) bool {
+		return d.send(conn, encoder, ev)
+	})
+}
+
+// send writes one line to a subscriber, reporting false if the write failed.
+func (d *Daemon) send(conn net.Conn, encoder *json.Encoder, v any) bool {
+	if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return false
+	}
+	if err := encoder.Encode(v); err != nil {
+		d.logger.Debug("subscriber write failed", "error", err)
+		return false
+	}
+	return true
+}
+
+// decodeSubscribeParams converts the generic request params to SubscribeParams.
+func decodeSubscribeParams(raw any) (SubscribeParams, error) {
+	var params SubscribeParams
+	if raw == nil {
+		return params, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return params, fmt.Errorf("invalid params: %w", err)
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return params, fmt.Errorf("invalid params: %w", err)
+	}
+	return params, nil
+}
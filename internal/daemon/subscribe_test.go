@@ -0,0 +1,251 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+)
+
+// startSubscribeDaemon starts a daemon with an event router and waits for its socket.
+func startSubscribeDaemon(t *testing.T, cfg *config.Config, router *events.Router) {
+	t.Helper()
+	cfg.Paths.Socket = shortSocketPath(t)
+
+	d := New(cfg, nil, nil, WithRouter(router))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = d.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	waitForSocket(t, cfg.Paths.Socket, 2*time.Second)
+}
+
+func iterationStart(beadID string, at time.Time) *events.IterationStartEvent {
+	ev := &events.IterationStartEvent{
+		BaseEvent: events.NewInternalEvent(events.EventIterationStart),
+		BeadID:    beadID,
+		Title:     "Bead " + beadID,
+	}
+	ev.Time = at
+	return ev
+}
+
+func iterationEnd(beadID string, at time.Time) *events.IterationEndEvent {
+	ev := &events.IterationEndEvent{
+		BaseEvent: events.NewInternalEvent(events.EventIterationEnd),
+		BeadID:    beadID,
+		Success:   true,
+	}
+	ev.Time = at
+	return ev
+}
+
+func claudeText(text string, at time.Time) *events.ClaudeTextEvent {
+	ev := &events.ClaudeTextEvent{
+		BaseEvent: events.NewClaudeEvent(events.EventClaudeText),
+		Text:      text,
+	}
+	ev.Time = at
+	return ev
+}
+
+func TestEventFilter_Types(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		types []string
+		ev    events.Event
+		want  bool
+	}{
+		{"no filter", nil, claudeText("hi", now), true},
+		{"type matches", []string{"claude.text"}, claudeText("hi", now), true},
+		{"type excluded", []string{"iteration.start"}, claudeText("hi", now), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewEventFilter(SubscribeParams{Types: tt.types})
+			if got := filter.Match(tt.ev); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventFilter_Bead(t *testing.T) {
+	now := time.Now()
+	filter := NewEventFilter(SubscribeParams{BeadID: "bd-1"})
+
+	steps := []struct {
+		ev   events.Event
+		want bool
+	}{
+		{claudeText("before", now), false},
+		{iterationStart("bd-2", now), false},
+		{iterationStart("bd-1", now), true},
+		{claudeText("during", now), true},
+		{iterationEnd("bd-2", now), false},
+		{iterationEnd("bd-1", now), true},
+		{claudeText("after", now), false},
+	}
+	for i, step := range steps {
+		if got := filter.Match(step.ev); got != step.want {
+			t.Errorf("step %d (%s): Match() = %v, want %v", i, step.ev.Type(), got, step.want)
+		}
+	}
+}
+
+func TestDaemon_Subscribe_StreamsLiveEvents(t *testing.T) {
+	router := events.NewRouter(100)
+	defer router.Close()
+	cfg := config.Default()
+	startSubscribeDaemon(t, cfg, router)
+
+	conn, err := net.Dial("unix", cfg.Paths.Socket)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	req := Request{Method: "subscribe", Params: SubscribeParams{BeadID: "bd-1"}, ID: 7}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("read ack: %v", err)
+	}
+	var ack Response
+	if err := json.Unmarshal(line, &ack); err != nil {
+		t.Fatalf("decode ack: %v", err)
+	}
+	if ack.Error != "" || ack.Result != "subscribed" || ack.ID != 7 {
+		t.Fatalf("unexpected ack: %+v", ack)
+	}
+
+	now := time.Now()
+	router.Emit(claudeText("unrelated", now))
+	router.Emit(iterationStart("bd-2", now))
+	router.Emit(iterationStart("bd-1", now))
+	router.Emit(claudeText("working on bd-1", now))
+	router.Emit(iterationEnd("bd-1", now))
+
+	var got []string
+	for len(got) < 3 {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read event: %v (got %v)", err, got)
+		}
+		ev, err := events.ParseEvent(line)
+		if err != nil || ev == nil {
+			t.Fatalf("parse event %q: %v", line, err)
+		}
+		got = append(got, string(ev.Type()))
+	}
+
+	want := []string{"iteration.start", "claude.text", "iteration.end"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("streamed events = %v, want %v", got, want)
+	}
+}
+
+func TestDaemon_Subscribe_NoRouter(t *testing.T) {
+	cfg := config.Default()
+	startSubscribeDaemon(t, cfg, nil)
+
+	client := NewClient(cfg.Paths.Socket)
+	err := client.Subscribe(context.Background(), SubscribeParams{}, func(events.Event) {})
+	if err == nil || !strings.Contains(err.Error(), "no event router") {
+		t.Errorf("expected no router error, got %v", err)
+	}
+}
+
+func TestClient_Subscribe_ReplaysSince(t *testing.T) {
+	router := events.NewRouter(100)
+	defer router.Close()
+
+	base := time.Now().Add(-time.Hour)
+	logPath := filepath.Join(t.TempDir(), "events.log")
+	var lines []string
+	for _, ev := range []events.Event{
+		claudeText("too old", base),
+		claudeText("first", base.Add(2*time.Minute)),
+		iterationStart("bd-1", base.Add(3*time.Minute)),
+	} {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		lines = append(lines, string(data))
+	}
+	if err := os.WriteFile(logPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.Paths.Log = logPath
+	startSubscribeDaemon(t, cfg, router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan events.Event, 10)
+	done := make(chan error, 1)
+	go func() {
+		params := SubscribeParams{
+			Types: []string{"claude.text"},
+			Since: base.Add(time.Minute),
+		}
+		done <- NewClient(cfg.Paths.Socket).Subscribe(ctx, params, func(ev events.Event) {
+			received <- ev
+		})
+	}()
+
+	select {
+	case ev := <-received:
+		text, ok := ev.(*events.ClaudeTextEvent)
+		if !ok || text.Text != "first" {
+			t.Fatalf("first event = %#v, want replayed claude.text \"first\"", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for replayed event")
+	}
+
+	// Live events follow the replay
+	router.Emit(claudeText("live", time.Now()))
+	select {
+	case ev := <-received:
+		if text, ok := ev.(*events.ClaudeTextEvent); !ok || text.Text != "live" {
+			t.Fatalf("second event = %#v, want live claude.text", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for live event")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Subscribe() returned error after cancel: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return after cancel")
+	}
+}
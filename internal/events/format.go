@@ -35,6 +35,8 @@ func Format(event Event) string {
 		return formatSessionEnd(e)
 	case *SessionTimeoutEvent:
 		return formatSessionTimeout(e)
+	case *SessionCostEvent:
+		return formatSessionCost(e)
 	case *DrainStartEvent:
 		return formatDrainStart(e)
 	case *DrainStopEvent:
@@ -131,6 +133,10 @@ func formatSessionTimeout(e *SessionTimeoutEvent) string {
 	return fmt.Sprintf("session timeout after %s", e.Duration)
 }
 
+func formatSessionCost(e *SessionCostEvent) string {
+	return fmt.Sprintf("session cost: ~$%.4f (%d in, %d out)", e.EstimatedCostUSD, e.InputTokens, e.OutputTokens)
+}
+
 func formatDrainStart(e *DrainStartEvent) string {
 	return fmt.Sprintf("drain started: %s", SafeString(e.WorkDir))
 }
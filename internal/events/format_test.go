@@ -88,6 +88,16 @@ func TestFormat_AllEventTypes(t *testing.T) {
 			},
 			contains: []string{"session timeout", "5m"},
 		},
+		{
+			name: "SessionCostEvent",
+			event: &SessionCostEvent{
+				BaseEvent:        BaseEvent{EventType: EventSessionCost, Time: now, Src: SourceClaude},
+				InputTokens:      1200,
+				OutputTokens:     300,
+				EstimatedCostUSD: 0.0081,
+			},
+			contains: []string{"session cost:", "~$0.0081", "1200 in", "300 out"},
+		},
 		{
 			name: "DrainStartEvent",
 			event: &DrainStartEvent{
@@ -326,6 +336,7 @@ func TestFormat_RoundTrip(t *testing.T) {
 		&SessionStartEvent{BaseEvent: BaseEvent{EventType: EventSessionStart, Time: now, Src: SourceInternal}, BeadID: "bd-1", Title: "Test"},
 		&SessionEndEvent{BaseEvent: BaseEvent{EventType: EventSessionEnd, Time: now, Src: SourceInternal}, NumTurns: 5},
 		&SessionTimeoutEvent{BaseEvent: BaseEvent{EventType: EventSessionTimeout, Time: now, Src: SourceInternal}, Duration: time.Minute},
+		&SessionCostEvent{BaseEvent: BaseEvent{EventType: EventSessionCost, Time: now, Src: SourceClaude}, EstimatedCostUSD: 0.01},
 		&ClaudeTextEvent{BaseEvent: BaseEvent{EventType: EventClaudeText, Time: now, Src: SourceClaude}, Text: "Hello"},
 		&ClaudeToolUseEvent{BaseEvent: BaseEvent{EventType: EventClaudeToolUse, Time: now, Src: SourceClaude}, ToolName: "Bash"},
 		&ClaudeToolResultEvent{BaseEvent: BaseEvent{EventType: EventClaudeToolResult, Time: now, Src: SourceClaude}},
@@ -39,6 +39,11 @@ func ParseEvent(line []byte) (Event, error) {
 		err = json.Unmarshal(line, &e)
 		ev = &e
 
+	case EventSessionCost:
+		var e SessionCostEvent
+		err = json.Unmarshal(line, &e)
+		ev = &e
+
 	case EventClaudeText:
 		var e ClaudeTextEvent
 		err = json.Unmarshal(line, &e)
@@ -46,6 +46,18 @@ func TestParseEvent_AllTypes(t *testing.T) {
 			wantType:   EventSessionTimeout,
 			wantBeadID: "",
 		},
+		{
+			name: "SessionCostEvent",
+			event: &SessionCostEvent{
+				BaseEvent:        BaseEvent{EventType: EventSessionCost, Time: now, Src: SourceClaude},
+				SessionID:        "sess-1",
+				Model:            "claude-sonnet-4-5",
+				OutputTokens:     300,
+				EstimatedCostUSD: 0.0045,
+			},
+			wantType:   EventSessionCost,
+			wantBeadID: "",
+		},
 		{
 			name: "ClaudeTextEvent",
 			event: &ClaudeTextEvent{
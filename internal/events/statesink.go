@@ -35,7 +35,7 @@ type State struct {
 	StalledBeadTitle string    `json:"stalled_bead_title,omitempty"`
 	StallReason      string    `json:"stall_reason,omitempty"`
 	StalledAt        time.Time `json:"stalled_at,omitempty"`
-	StallType        string    `json:"stall_type,omitempty"`        // "abandoned" or "review"
+	StallType        string    `json:"stall_type,omitempty"`        // "abandoned", "review", or "budget"
 	CreatedBeads     []string  `json:"created_beads,omitempty"`     // bead IDs created during session (for review stalls)
 }
 
@@ -167,6 +167,13 @@ func (s *StateSink) handleEvent(event Event) {
 		}
 		s.dirty = true
 
+	case *SessionStartEvent:
+		// Record the model of the latest attempt
+		if h := s.state.History[e.BeadID]; h != nil {
+			h.Model = e.Model
+			s.dirty = true
+		}
+
 	case *BeadAbandonedEvent:
 		if h := s.state.History[e.BeadID]; h != nil {
 			h.Status = HistoryAbandoned
@@ -120,6 +120,48 @@ func TestStateSinkTracksIterations(t *testing.T) {
 	_ = sink.Stop()
 }
 
+func TestStateSinkTracksSessionModel(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "state.json")
+
+	sink := NewStateSink(path)
+	sink.SetMinDelay(0) // Disable debounce for testing
+	events := make(chan Event, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := sink.Start(ctx, events)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	events <- &IterationStartEvent{
+		BaseEvent: NewInternalEvent(EventIterationStart),
+		BeadID:    "bd-001",
+		Title:     "Test bead",
+		Attempt:   1,
+	}
+	events <- &SessionStartEvent{
+		BaseEvent: NewInternalEvent(EventSessionStart),
+		BeadID:    "bd-001",
+		Title:     "Test bead",
+		Model:     "opus",
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	h, ok := sink.State().History["bd-001"]
+	if !ok {
+		t.Fatal("expected history for bd-001")
+	}
+	if h.Model != "opus" {
+		t.Errorf("History model = %q, want %q", h.Model, "opus")
+	}
+
+	cancel()
+	_ = sink.Stop()
+}
+
 func TestStateSinkTracksDrainStatus(t *testing.T) {
 	tmp := t.TempDir()
 	path := filepath.Join(tmp, "state.json")
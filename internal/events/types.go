@@ -14,6 +14,7 @@ const (
 	EventSessionStart   EventType = "session.start"
 	EventSessionEnd     EventType = "session.end"
 	EventSessionTimeout EventType = "session.timeout"
+	EventSessionCost    EventType = "session.cost"
 
 	// Claude content events
 	EventClaudeText       EventType = "claude.text"
@@ -51,6 +52,9 @@ const (
 	// Error events
 	EventError      EventType = "error"
 	EventParseError EventType = "error.parse"
+
+	// Replay events (emitted by atari replay, never logged)
+	EventReplayRewind EventType = "replay.rewind"
 )
 
 // Source constants identify the origin of events.
@@ -94,16 +98,21 @@ type SessionStartEvent struct {
 	BaseEvent
 	BeadID string `json:"bead_id"`
 	Title  string `json:"title"`
+	Prompt string `json:"prompt,omitempty"` // name of the prompt template used
+	Model  string `json:"model,omitempty"`  // model selected for the session (empty = agent default)
 }
 
 // SessionEndEvent is emitted when a Claude session completes.
 type SessionEndEvent struct {
 	BaseEvent
 	SessionID    string  `json:"session_id"`
+	BeadID       string  `json:"bead_id,omitempty"` // bead the session worked on, set by atari
 	NumTurns     int     `json:"num_turns"`
 	DurationMs   int64   `json:"duration_ms"`
 	TotalCostUSD float64 `json:"total_cost_usd"`
 	Result       string  `json:"result,omitempty"`
+	Subtype      string  `json:"subtype,omitempty"`  // result subtype, e.g. "success" or "error_max_turns"
+	IsError      bool    `json:"is_error,omitempty"` // the agent reported the session as failed
 }
 
 // SessionTimeoutEvent is emitted when a session is killed due to inactivity.
@@ -112,6 +121,21 @@ type SessionTimeoutEvent struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// SessionCostEvent is emitted while a session runs, each time the estimated
+// cost changes. The estimate is derived from the per-message token usage and
+// is superseded by the actual cost in SessionEndEvent.
+type SessionCostEvent struct {
+	BaseEvent
+	SessionID           string  `json:"session_id,omitempty"`
+	BeadID              string  `json:"bead_id,omitempty"` // bead the session works on, set by atari
+	Model               string  `json:"model,omitempty"`
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheCreationTokens int     `json:"cache_creation_tokens"`
+	CacheReadTokens     int     `json:"cache_read_tokens"`
+	EstimatedCostUSD    float64 `json:"estimated_cost_usd"`
+}
+
 // ClaudeTextEvent is emitted for assistant text output.
 type ClaudeTextEvent struct {
 	BaseEvent
@@ -143,7 +167,8 @@ type DrainStartEvent struct {
 // DrainStopEvent is emitted when atari stops.
 type DrainStopEvent struct {
 	BaseEvent
-	Reason string `json:"reason,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Condition string `json:"condition,omitempty"` // drain-until condition that ended the drain, e.g. "max_beads"
 }
 
 // DrainStateChangedEvent is emitted when the controller state changes.
@@ -161,7 +186,7 @@ type StallEvent struct {
 	BeadID       string   `json:"bead_id"`
 	Title        string   `json:"title"`
 	Reason       string   `json:"reason"`
-	StallType    string   `json:"stall_type,omitempty"`    // "abandoned" or "review"
+	StallType    string   `json:"stall_type,omitempty"`    // "abandoned", "review", or "budget"
 	CreatedBeads []string `json:"created_beads,omitempty"` // bead IDs created during session (for review stalls)
 }
 
@@ -181,6 +206,7 @@ type IterationStartEvent struct {
 	Attempt       int    `json:"attempt"`
 	TopLevelID    string `json:"top_level_id,omitempty"`    // Active top-level item ID (when selection_mode=top-level)
 	TopLevelTitle string `json:"top_level_title,omitempty"` // Active top-level item title
+	EpicID        string `json:"epic_id,omitempty"`         // Epic the bead's cost is counted against
 }
 
 // IterationEndEvent is emitted when bead work completes.
@@ -303,6 +329,13 @@ type ParseErrorEvent struct {
 	Error string `json:"error"`
 }
 
+// ReplayRewindEvent is emitted when a replayed log seeks backwards, before
+// the earlier events are sent again. Consumers should discard the state they
+// built from the events already seen.
+type ReplayRewindEvent struct {
+	BaseEvent
+}
+
 // NewEvent creates a BaseEvent with the given type and source.
 func NewEvent(eventType EventType, source string) BaseEvent {
 	return BaseEvent{
@@ -340,13 +373,31 @@ const (
 	HistorySkipped   HistoryStatus = "skipped"
 )
 
+// FailureClass is the kind of failure that ended a bead's latest attempt.
+// Each class has its own retry policy.
+type FailureClass string
+
+// FailureClass constants.
+const (
+	FailureRateLimit FailureClass = "rate_limit" // the agent hit an API rate or usage limit
+	FailureAuth      FailureClass = "auth"       // the agent could not log in or its account needs attention
+	FailureTimeout   FailureClass = "timeout"    // the inactivity watchdog stopped the session
+	FailureTransient FailureClass = "transient"  // the API was overloaded or the connection dropped
+	FailureCrash     FailureClass = "crash"      // the agent exited with an error
+	FailureGaveUp    FailureClass = "gave_up"    // the agent ended without closing the bead
+	FailureOther     FailureClass = "other"      // verification, merge or any other failure
+)
+
 // BeadHistory tracks the processing history of a bead.
 // This type is shared between workqueue and state sink.
 type BeadHistory struct {
 	ID            string        `json:"id"`
 	Status        HistoryStatus `json:"status"`
 	Attempts      int           `json:"attempts"`
+	Failures      int           `json:"failures,omitempty"` // attempts that ended in a failure, not a pause or a stop
 	LastAttempt   time.Time     `json:"last_attempt"`
 	LastError     string        `json:"last_error,omitempty"`
 	LastSessionID string        `json:"last_session_id,omitempty"` // Claude session ID for resume
+	Model         string        `json:"model,omitempty"`           // Model used by the latest attempt
+	FailureClass  FailureClass  `json:"failure_class,omitempty"`   // Kind of failure that ended the latest attempt
 }
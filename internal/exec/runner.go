@@ -4,6 +4,7 @@ package exec
 import (
 	"context"
 	"os/exec"
+	"time"
 )
 
 // CommandRunner abstracts command execution for dependency injection.
@@ -28,8 +29,14 @@ func (r *ExecRunner) Run(ctx context.Context, name string, args ...string) ([]by
 // execCommand is a variable to allow testing.
 var execCommand = execCommandImpl
 
+// waitDelay bounds how long Run waits for output after the context ends, in
+// case the killed command left children holding its stdout open.
+const waitDelay = 2 * time.Second
+
 func execCommandImpl(ctx context.Context, name string, args ...string) execCmd {
-	return realExecCmd{cmd: exec.CommandContext(ctx, name, args...)}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	return realExecCmd{cmd: cmd}
 }
 
 // execCmd abstracts exec.Cmd for testing.
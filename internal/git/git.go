@@ -0,0 +1,44 @@
+// Package git runs the git commands shared by worker worktrees and the
+// branch-per-bead workflow.
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	osexec "os/exec"
+	"strings"
+
+	"github.com/npratt/atari/internal/exec"
+)
+
+// Run runs a git command in dir. Errors include git's stderr so they can be
+// logged and recorded in bead notes.
+func Run(ctx context.Context, runner exec.CommandRunner, dir string, args ...string) ([]byte, error) {
+	out, err := runner.Run(ctx, "git", append([]string{"-C", dir}, args...)...)
+	if err != nil {
+		var exitErr *osexec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return out, err
+	}
+	return out, nil
+}
+
+// BranchExists reports whether a local branch with the given name exists in
+// the repository at dir.
+func BranchExists(ctx context.Context, runner exec.CommandRunner, dir, branch string) bool {
+	_, err := Run(ctx, runner, dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// CurrentBranch returns the branch checked out in dir. It fails on a
+// detached HEAD.
+func CurrentBranch(ctx context.Context, runner exec.CommandRunner, dir string) (string, error) {
+	out, err := Run(ctx, runner, dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
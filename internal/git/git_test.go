@@ -0,0 +1,57 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmdexec "github.com/npratt/atari/internal/exec"
+)
+
+func TestRealGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	runner := cmdexec.NewExecRunner()
+	ctx := context.Background()
+	mustRun := func(args ...string) {
+		t.Helper()
+		if _, err := Run(ctx, runner, repo, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	mustRun("init", "-q", "-b", "main")
+	mustRun("config", "user.name", "test")
+	mustRun("config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mustRun("add", "README")
+	mustRun("commit", "-q", "-m", "initial")
+
+	if branch, err := CurrentBranch(ctx, runner, repo); err != nil || branch != "main" {
+		t.Errorf("CurrentBranch() = %q, %v; want main", branch, err)
+	}
+	if !BranchExists(ctx, runner, repo, "main") {
+		t.Error("BranchExists(main) = false, want true")
+	}
+	if BranchExists(ctx, runner, repo, "atari/bd-001") {
+		t.Error("BranchExists(atari/bd-001) = true, want false")
+	}
+
+	// Errors carry git's stderr
+	_, err := Run(ctx, runner, repo, "checkout", "-q", "no-such-branch")
+	if err == nil || !strings.Contains(err.Error(), "no-such-branch") {
+		t.Errorf("Run() error = %v, want git's stderr", err)
+	}
+
+	mustRun("checkout", "-q", "--detach")
+	if _, err := CurrentBranch(ctx, runner, repo); err == nil {
+		t.Error("CurrentBranch() on a detached HEAD: want an error")
+	}
+}
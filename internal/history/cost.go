@@ -0,0 +1,117 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/npratt/atari/internal/brclient"
+)
+
+// CostModel estimates what a bead will cost from the attempts in the
+// archive, for the cheapest and weighted selection strategies. It rereads
+// the archive whenever it has changed, so estimates follow the drain.
+type CostModel struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	beads   map[string]costMean // per completed attempt, by bead
+	epics   map[string]costMean // per completed bead, by epic
+	overall costMean            // per completed bead
+}
+
+// costMean accumulates a mean cost.
+type costMean struct {
+	total float64
+	n     int
+}
+
+func (c costMean) mean() float64 {
+	return c.total / float64(c.n)
+}
+
+// NewCostModel creates a CostModel for the archive at path.
+func NewCostModel(path string) *CostModel {
+	return &CostModel{path: path}
+}
+
+// EstimateCost implements workqueue.CostEstimator. A bead completed before,
+// then reopened, is expected to cost what its completed attempts did on
+// average. Failed attempts are left out, since one that gave up early and
+// cheaply would make the bead look cheap. Otherwise the estimate is the
+// mean cost of completing a bead in the same epic, then of any bead.
+func (m *CostModel) EstimateCost(bead brclient.Bead) (float64, string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refresh()
+
+	if c, ok := m.beads[bead.ID]; ok {
+		return c.mean(), fmt.Sprintf("mean of its %s", plural(c.n, "completed attempt")), true
+	}
+	if bead.Parent != "" {
+		if c, ok := m.epics[bead.Parent]; ok {
+			return c.mean(), fmt.Sprintf("mean of %s in %s", plural(c.n, "completed bead"), bead.Parent), true
+		}
+	}
+	if m.overall.n > 0 {
+		return m.overall.mean(), fmt.Sprintf("mean of %s", plural(m.overall.n, "completed bead")), true
+	}
+	return 0, "", false
+}
+
+// refresh reloads the archive if it changed since it was last read. A
+// missing or unreadable archive keeps the previous estimates. Callers hold
+// m.mu.
+func (m *CostModel) refresh() {
+	info, err := os.Stat(m.path)
+	if err != nil || (info.ModTime().Equal(m.modTime) && info.Size() == m.size) {
+		return
+	}
+	records, err := Load(m.path)
+	if err != nil {
+		return
+	}
+	m.modTime, m.size = info.ModTime(), info.Size()
+
+	m.beads = make(map[string]costMean)
+	beadTotals := make(map[string]float64)
+	epicOf := make(map[string]string)
+	completed := make(map[string]bool)
+	for _, rec := range records {
+		beadTotals[rec.BeadID] += rec.CostUSD
+		if rec.EpicID != "" {
+			epicOf[rec.BeadID] = rec.EpicID
+		}
+		if rec.Outcome == OutcomeCompleted {
+			completed[rec.BeadID] = true
+			c := m.beads[rec.BeadID]
+			c.total += rec.CostUSD
+			c.n++
+			m.beads[rec.BeadID] = c
+		}
+	}
+
+	m.epics = make(map[string]costMean)
+	m.overall = costMean{}
+	for id := range completed {
+		m.overall.total += beadTotals[id]
+		m.overall.n++
+		if epic := epicOf[id]; epic != "" {
+			c := m.epics[epic]
+			c.total += beadTotals[id]
+			c.n++
+			m.epics[epic] = c
+		}
+	}
+}
+
+// plural formats a count with a noun, adding "s" unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,71 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/npratt/atari/internal/brclient"
+)
+
+func writeRecords(t *testing.T, path string, records ...Record) {
+	t.Helper()
+	var data []byte
+	for _, rec := range records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCostModel_EstimateCost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	model := NewCostModel(path)
+
+	if _, _, ok := model.EstimateCost(brclient.Bead{ID: "bd-new"}); ok {
+		t.Error("expected no estimate without an archive")
+	}
+
+	writeRecords(t, path,
+		Record{BeadID: "bd-1", EpicID: "bd-epic", CostUSD: 1.0, Outcome: OutcomeFailed},
+		Record{BeadID: "bd-1", EpicID: "bd-epic", CostUSD: 2.0, Outcome: OutcomeCompleted},
+		Record{BeadID: "bd-2", EpicID: "bd-epic", CostUSD: 1.0, Outcome: OutcomeCompleted},
+		Record{BeadID: "bd-3", CostUSD: 5.0, Outcome: OutcomeCompleted},
+		Record{BeadID: "bd-4", CostUSD: 0.5, Outcome: OutcomeFailed},
+	)
+
+	tests := []struct {
+		name  string
+		bead  brclient.Bead
+		want  float64
+		basis string
+	}{
+		{"completed attempts", brclient.Bead{ID: "bd-1", Parent: "bd-epic"}, 2.0, "mean of its 1 completed attempt"},
+		{"failed attempts ignored", brclient.Bead{ID: "bd-4"}, 3.0, "mean of 3 completed beads"},
+		{"same epic", brclient.Bead{ID: "bd-new", Parent: "bd-epic"}, 2.0, "mean of 2 completed beads in bd-epic"},
+		{"any bead", brclient.Bead{ID: "bd-new", Parent: "bd-other"}, 3.0, "mean of 3 completed beads"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, basis, ok := model.EstimateCost(tt.bead)
+			if !ok || got != tt.want || basis != tt.basis {
+				t.Errorf("EstimateCost() = %v, %q, %v; want %v, %q", got, basis, ok, tt.want, tt.basis)
+			}
+		})
+	}
+
+	// New attempts are picked up when the archive changes
+	writeRecords(t, path,
+		Record{BeadID: "bd-4", CostUSD: 0.5, Outcome: OutcomeFailed},
+		Record{BeadID: "bd-4", CostUSD: 1.5, Outcome: OutcomeCompleted},
+	)
+	if got, _, _ := model.EstimateCost(brclient.Bead{ID: "bd-4"}); got != 1.5 {
+		t.Errorf("EstimateCost() after archive change = %v, want 1.5", got)
+	}
+}
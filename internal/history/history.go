@@ -0,0 +1,84 @@
+// Package history keeps a permanent record of every bead attempt in an
+// append-only JSON lines archive. Unlike the state file, which keeps only
+// the latest attempt per bead, the archive survives restarts and log
+// rotation and backs the history and report commands.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Attempt outcomes.
+const (
+	OutcomeCompleted = "completed"
+	OutcomeFailed    = "failed"
+	OutcomeAbandoned = "abandoned" // failed and reached max_failures
+)
+
+// maxLineSize bounds a single archive line when reading.
+const maxLineSize = 1024 * 1024
+
+// Record describes one attempt at a bead, from iteration start to end.
+type Record struct {
+	BeadID    string    `json:"bead_id"`
+	Title     string    `json:"title,omitempty"`
+	EpicID    string    `json:"epic_id,omitempty"`
+	Attempt   int       `json:"attempt"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Turns     int       `json:"turns"`
+	CostUSD   float64   `json:"cost_usd"`
+	Model     string    `json:"model,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+}
+
+// Duration returns how long the attempt took.
+func (r Record) Duration() time.Duration {
+	return r.EndedAt.Sub(r.StartedAt)
+}
+
+// Load reads every record from the archive at path, oldest first. A missing
+// archive has no records. Lines that cannot be decoded are skipped.
+func Load(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.BeadID == "" {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return records, fmt.Errorf("read history: %w", err)
+	}
+	return records, nil
+}
+
+// ForBead returns the records for one bead, in archive order.
+func ForBead(records []Record, beadID string) []Record {
+	var out []Record
+	for _, rec := range records {
+		if rec.BeadID == beadID {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+func at(t0 time.Time, d time.Duration, base events.BaseEvent) events.BaseEvent {
+	base.Time = t0.Add(d)
+	return base
+}
+
+func TestSink_RecordsAttempts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+	sink := NewSink(path)
+	ch := make(chan events.Event, 20)
+	if err := sink.Start(context.Background(), ch); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	t0 := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	// Two workers interleave bd-1 and bd-2
+	ch <- &events.IterationStartEvent{BaseEvent: at(t0, 0, events.NewInternalEvent(events.EventIterationStart)), BeadID: "bd-1", Title: "One", Attempt: 1, EpicID: "bd-epic"}
+	ch <- &events.SessionStartEvent{BaseEvent: at(t0, 0, events.NewInternalEvent(events.EventSessionStart)), BeadID: "bd-1", Model: "opus"}
+	ch <- &events.IterationStartEvent{BaseEvent: at(t0, time.Second, events.NewInternalEvent(events.EventIterationStart)), BeadID: "bd-2", Attempt: 3}
+	ch <- &events.BeadAbandonedEvent{BaseEvent: at(t0, time.Minute, events.NewInternalEvent(events.EventBeadAbandoned)), BeadID: "bd-2"}
+	ch <- &events.IterationEndEvent{BaseEvent: at(t0, time.Minute, events.NewInternalEvent(events.EventIterationEnd)), BeadID: "bd-2", Error: "boom"}
+	ch <- &events.SessionStartEvent{BaseEvent: at(t0, 2*time.Minute, events.NewInternalEvent(events.EventSessionStart)), BeadID: "bd-1"} // follow-up
+	ch <- &events.IterationEndEvent{BaseEvent: at(t0, 5*time.Minute, events.NewInternalEvent(events.EventIterationEnd)), BeadID: "bd-1", Success: true, NumTurns: 12, TotalCostUSD: 1.5, SessionID: "sess-1"}
+	// An end without a start is ignored
+	ch <- &events.IterationEndEvent{BaseEvent: events.NewInternalEvent(events.EventIterationEnd), BeadID: "bd-9"}
+	close(ch)
+	if err := sink.Stop(); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+
+	records, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2: %+v", len(records), records)
+	}
+
+	abandoned := records[0]
+	if abandoned.BeadID != "bd-2" || abandoned.Attempt != 3 || abandoned.Outcome != OutcomeAbandoned || abandoned.Error != "boom" {
+		t.Errorf("records[0] = %+v", abandoned)
+	}
+
+	done := records[1]
+	if done.Outcome != OutcomeCompleted || done.Model != "opus" || done.EpicID != "bd-epic" || done.Title != "One" {
+		t.Errorf("records[1] = %+v", done)
+	}
+	if done.Turns != 12 || done.CostUSD != 1.5 || done.SessionID != "sess-1" || done.Duration() != 5*time.Minute {
+		t.Errorf("records[1] = %+v", done)
+	}
+	if got := ForBead(records, "bd-1"); len(got) != 1 || got[0].BeadID != "bd-1" {
+		t.Errorf("ForBead() = %+v", got)
+	}
+}
+
+func TestSink_CancelKeepsDeliveredEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	sink := NewSink(path)
+	ch := make(chan events.Event, 2)
+	ch <- &events.IterationStartEvent{BaseEvent: events.NewInternalEvent(events.EventIterationStart), BeadID: "bd-1", Attempt: 1}
+	ch <- &events.IterationEndEvent{BaseEvent: events.NewInternalEvent(events.EventIterationEnd), BeadID: "bd-1", Error: "interrupted"}
+
+	// The last attempt's end is already delivered when shutdown cancels
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sink.Start(ctx, ch); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if err := sink.Stop(); err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+
+	records, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(records) != 1 || records[0].Error != "interrupted" {
+		t.Errorf("records = %+v, want the interrupted attempt", records)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	records, err := Load(filepath.Join(dir, "missing.jsonl"))
+	if err != nil || records != nil {
+		t.Errorf("Load(missing) = %v, %v; want nil, nil", records, err)
+	}
+
+	path := filepath.Join(dir, "history.jsonl")
+	content := `{"bead_id":"bd-1","attempt":1,"outcome":"failed"}
+not json
+{"attempt":2}
+{"bead_id":"bd-1","attempt":2,"outcome":"completed"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	records, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(records) != 2 || records[1].Attempt != 2 {
+		t.Errorf("Load() = %+v, want the two valid records", records)
+	}
+}
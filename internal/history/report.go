@@ -0,0 +1,132 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxReasonLength truncates failure reasons so similar errors group together.
+const maxReasonLength = 100
+
+// Report summarises the attempts in a time window.
+type Report struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	Attempts  int `json:"attempts"`
+	Completed int `json:"completed"` // attempts that closed their bead
+	Failed    int `json:"failed"`
+	Abandoned int `json:"abandoned"`
+
+	Beads           int           `json:"beads"`                 // distinct beads attempted
+	CompletedBeads  int           `json:"completed_beads"`       // distinct beads completed
+	BeadsPerDay     float64       `json:"beads_per_day"`         // completed beads per day of the window
+	TotalCostUSD    float64       `json:"total_cost_usd"`        // spend across all attempts
+	TotalTurns      int           `json:"total_turns"`           // turns across all attempts
+	MeanTimePerBead time.Duration `json:"mean_time_per_bead_ns"` // time spent on each completed bead, across all its attempts
+
+	Epics          []EpicSummary   `json:"epics,omitempty"`           // by cost, highest first
+	FailureReasons []FailureReason `json:"failure_reasons,omitempty"` // by count, most common first
+}
+
+// EpicSummary is the spend and progress for one epic. Beads outside any
+// epic are grouped under an empty EpicID.
+type EpicSummary struct {
+	EpicID         string  `json:"epic_id"`
+	CostUSD        float64 `json:"cost_usd"`
+	Attempts       int     `json:"attempts"`
+	CompletedBeads int     `json:"completed_beads"`
+}
+
+// FailureReason counts failed attempts that share an error.
+type FailureReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// Summarize reports on records that started in [since, until).
+func Summarize(records []Record, since, until time.Time) Report {
+	r := Report{Since: since, Until: until}
+
+	beads := make(map[string]bool)
+	beadTime := make(map[string]time.Duration)
+	epics := make(map[string]*EpicSummary)
+	reasons := make(map[string]int)
+
+	for _, rec := range records {
+		if rec.StartedAt.Before(since) || !rec.StartedAt.Before(until) {
+			continue
+		}
+
+		r.Attempts++
+		r.TotalCostUSD += rec.CostUSD
+		r.TotalTurns += rec.Turns
+		beads[rec.BeadID] = beads[rec.BeadID] || rec.Outcome == OutcomeCompleted
+		beadTime[rec.BeadID] += rec.Duration()
+
+		epic, ok := epics[rec.EpicID]
+		if !ok {
+			epic = &EpicSummary{EpicID: rec.EpicID}
+			epics[rec.EpicID] = epic
+		}
+		epic.CostUSD += rec.CostUSD
+		epic.Attempts++
+
+		switch rec.Outcome {
+		case OutcomeCompleted:
+			r.Completed++
+			epic.CompletedBeads++
+			continue
+		case OutcomeAbandoned:
+			r.Abandoned++
+		default:
+			r.Failed++
+		}
+		reasons[failureReason(rec.Error)]++
+	}
+
+	var completedTime time.Duration
+	for id, completed := range beads {
+		r.Beads++
+		if completed {
+			r.CompletedBeads++
+			completedTime += beadTime[id]
+		}
+	}
+	if r.CompletedBeads > 0 {
+		r.MeanTimePerBead = completedTime / time.Duration(r.CompletedBeads)
+	}
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		r.BeadsPerDay = float64(r.CompletedBeads) / days
+	}
+
+	for _, epic := range epics {
+		r.Epics = append(r.Epics, *epic)
+	}
+	slices.SortFunc(r.Epics, func(a, b EpicSummary) int {
+		return cmp.Or(cmp.Compare(b.CostUSD, a.CostUSD), strings.Compare(a.EpicID, b.EpicID))
+	})
+
+	for reason, count := range reasons {
+		r.FailureReasons = append(r.FailureReasons, FailureReason{Reason: reason, Count: count})
+	}
+	slices.SortFunc(r.FailureReasons, func(a, b FailureReason) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Reason, b.Reason))
+	})
+
+	return r
+}
+
+// failureReason reduces an error to its first line so repeats group together.
+func failureReason(err string) string {
+	reason, _, _ := strings.Cut(strings.TrimSpace(err), "\n")
+	if reason == "" {
+		return "unknown"
+	}
+	if len(reason) > maxReasonLength {
+		reason = reason[:maxReasonLength-3] + "..."
+	}
+	return reason
+}
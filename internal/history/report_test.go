@@ -0,0 +1,58 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	until := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+	since := until.Add(-7 * 24 * time.Hour)
+	rec := func(bead, epic string, start time.Time, d time.Duration, cost float64, outcome, err string) Record {
+		return Record{BeadID: bead, EpicID: epic, StartedAt: start, EndedAt: start.Add(d), CostUSD: cost, Turns: 10, Outcome: outcome, Error: err}
+	}
+	day := since.Add(24 * time.Hour)
+
+	records := []Record{
+		rec("bd-old", "bd-a", since.Add(-time.Hour), time.Hour, 100, OutcomeCompleted, ""), // before the window
+		rec("bd-1", "bd-a", day, 10*time.Minute, 1, OutcomeFailed, "tests failed\nFAIL pkg"),
+		rec("bd-1", "bd-a", day.Add(time.Hour), 20*time.Minute, 2, OutcomeCompleted, ""),
+		rec("bd-2", "", day, 30*time.Minute, 4, OutcomeCompleted, ""),
+		rec("bd-3", "bd-b", day, time.Minute, 0.5, OutcomeFailed, "tests failed"),
+		rec("bd-3", "bd-b", day, time.Minute, 0.5, OutcomeAbandoned, strings.Repeat("x", 200)),
+	}
+
+	r := Summarize(records, since, until)
+
+	if r.Attempts != 5 || r.Completed != 2 || r.Failed != 2 || r.Abandoned != 1 {
+		t.Errorf("attempt counts = %d/%d/%d/%d", r.Attempts, r.Completed, r.Failed, r.Abandoned)
+	}
+	if r.Beads != 3 || r.CompletedBeads != 2 {
+		t.Errorf("beads = %d, completed = %d; want 3, 2", r.Beads, r.CompletedBeads)
+	}
+	if r.TotalCostUSD != 8 || r.TotalTurns != 50 {
+		t.Errorf("totals = $%v, %d turns", r.TotalCostUSD, r.TotalTurns)
+	}
+	// bd-1 took 30m over two attempts, bd-2 took 30m
+	if r.MeanTimePerBead != 30*time.Minute {
+		t.Errorf("MeanTimePerBead = %v, want 30m", r.MeanTimePerBead)
+	}
+	if r.BeadsPerDay != 2.0/7 {
+		t.Errorf("BeadsPerDay = %v", r.BeadsPerDay)
+	}
+
+	if len(r.Epics) != 3 || r.Epics[0].EpicID != "" || r.Epics[1].EpicID != "bd-a" || r.Epics[1].CostUSD != 3 || r.Epics[1].CompletedBeads != 1 {
+		t.Errorf("Epics = %+v", r.Epics)
+	}
+
+	if len(r.FailureReasons) != 2 {
+		t.Fatalf("FailureReasons = %+v", r.FailureReasons)
+	}
+	if r.FailureReasons[0] != (FailureReason{Reason: "tests failed", Count: 2}) {
+		t.Errorf("FailureReasons[0] = %+v", r.FailureReasons[0])
+	}
+	if got := r.FailureReasons[1].Reason; len(got) != maxReasonLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("long reason not truncated: %q", got)
+	}
+}
@@ -0,0 +1,159 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+// Sink builds a Record for each attempt from the event stream and appends it
+// to the archive when the attempt ends. Attempts are matched to events by
+// bead ID, so records stay correct with parallel workers.
+type Sink struct {
+	path string
+	file *os.File
+	mu   sync.Mutex
+	open map[string]*Record // attempts in progress, by bead ID
+	done chan struct{}
+}
+
+// NewSink creates a Sink that appends to the archive at path.
+func NewSink(path string) *Sink {
+	return &Sink{
+		path: path,
+		open: make(map[string]*Record),
+		done: make(chan struct{}),
+	}
+}
+
+// Start opens the archive and begins recording attempts.
+// It runs until the context is canceled or the events channel is closed,
+// recording any events already delivered before it stops.
+func (s *Sink) Start(ctx context.Context, ch <-chan events.Event) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+
+	s.mu.Lock()
+	s.file = file
+	s.mu.Unlock()
+
+	go s.run(ctx, ch)
+	return nil
+}
+
+func (s *Sink) run(ctx context.Context, ch <-chan events.Event) {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Record attempts whose end was already emitted
+			for {
+				select {
+				case ev, ok := <-ch:
+					if !ok {
+						return
+					}
+					s.handle(ev)
+				default:
+					return
+				}
+			}
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			s.handle(ev)
+		}
+	}
+}
+
+// handle updates the attempt an event belongs to, writing it once it ends.
+func (s *Sink) handle(ev events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e := ev.(type) {
+	case *events.IterationStartEvent:
+		s.open[e.BeadID] = &Record{
+			BeadID:    e.BeadID,
+			Title:     e.Title,
+			EpicID:    e.EpicID,
+			Attempt:   e.Attempt,
+			StartedAt: e.Timestamp(),
+		}
+	case *events.SessionStartEvent:
+		// Follow-up sessions leave the model unset; keep the main session's.
+		if rec, ok := s.open[e.BeadID]; ok && rec.Model == "" {
+			rec.Model = e.Model
+		}
+	case *events.BeadAbandonedEvent:
+		if rec, ok := s.open[e.BeadID]; ok {
+			rec.Outcome = OutcomeAbandoned
+		}
+	case *events.IterationEndEvent:
+		rec, ok := s.open[e.BeadID]
+		if !ok {
+			return
+		}
+		delete(s.open, e.BeadID)
+
+		rec.EndedAt = e.Timestamp()
+		rec.Turns = e.NumTurns
+		rec.CostUSD = e.TotalCostUSD
+		rec.Error = e.Error
+		rec.SessionID = e.SessionID
+		switch {
+		case e.Success:
+			rec.Outcome = OutcomeCompleted
+		case rec.Outcome == "":
+			rec.Outcome = OutcomeFailed
+		}
+		s.write(rec)
+	}
+}
+
+// write appends a record to the archive. Callers hold s.mu.
+func (s *Sink) write(rec *Record) {
+	if s.file == nil {
+		return
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "history sink: failed to encode record: %v\n", err)
+		return
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "history sink: failed to write record: %v\n", err)
+	}
+}
+
+// Stop closes the archive.
+func (s *Sink) Stop() error {
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file != nil {
+		err := s.file.Close()
+		s.file = nil
+		return err
+	}
+	return nil
+}
+
+// Path returns the archive path.
+func (s *Sink) Path() string {
+	return s.path
+}
@@ -6,15 +6,22 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/npratt/atari/internal/beadbranch"
 	"github.com/npratt/atari/internal/brclient"
 	"github.com/npratt/atari/internal/config"
 	"github.com/npratt/atari/internal/controller"
 	"github.com/npratt/atari/internal/events"
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/session"
 	"github.com/npratt/atari/internal/workqueue"
+	"github.com/npratt/atari/internal/worktree"
 )
 
 // testEnv holds the test environment for integration tests.
@@ -1187,3 +1194,302 @@ func TestNoEpicClosureWhenNoneEligible(t *testing.T) {
 
 	t.Log("no epic closure when none eligible - verified")
 }
+
+func TestRunBead(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	// Scenario: atari run on one bead, which the session closes
+	beadID := "bd-run"
+	epicID := "bd-epic-run"
+
+	var mu sync.Mutex
+	sessionStarted := false
+	sub := env.router.Subscribe()
+	go func() {
+		for ev := range sub {
+			if ev.Type() == events.EventSessionStart {
+				mu.Lock()
+				sessionStarted = true
+				mu.Unlock()
+			}
+		}
+	}()
+
+	env.brClient.DynamicShow = func(ctx context.Context, id string) (*brclient.Bead, error, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		bead := singleBead(id, "Run bead")
+		if sessionStarted {
+			bead.Status = "closed"
+		}
+		return &bead, nil, true
+	}
+	env.brClient.CloseEligibleResult = []brclient.EpicCloseResult{
+		{ID: epicID, Title: "Run Epic", DependentCount: 1},
+	}
+
+	wq := workqueue.New(env.cfg, env.brClient, nil)
+	ctrl := controller.New(env.cfg, wq, env.router, env.brClient, nil, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status, err := ctrl.RunBead(ctx, beadID)
+	if err != nil {
+		t.Fatalf("RunBead() error: %v", err)
+	}
+	if status != workqueue.HistoryCompleted {
+		t.Errorf("status = %q, want %q", status, workqueue.HistoryCompleted)
+	}
+	if ctrl.State() != controller.StateStopped {
+		t.Errorf("state = %s, want %s", ctrl.State(), controller.StateStopped)
+	}
+	if len(env.brClient.ReadyCalls) != 0 {
+		t.Errorf("RunBead consulted the work queue: %d ready calls", len(env.brClient.ReadyCalls))
+	}
+
+	env.collectEvents(200 * time.Millisecond)
+
+	if evt := env.findEvent(events.EventIterationStart); evt == nil {
+		t.Error("expected IterationStartEvent")
+	} else if iterEvt := evt.(*events.IterationStartEvent); iterEvt.BeadID != beadID {
+		t.Errorf("expected bead id %s, got %s", beadID, iterEvt.BeadID)
+	}
+	if env.findEvent(events.EventEpicClosed) == nil {
+		t.Error("expected EpicClosedEvent before RunBead returned")
+	}
+	if evt := env.findEvent(events.EventDrainStop); evt == nil {
+		t.Error("expected DrainStopEvent")
+	} else if stop := evt.(*events.DrainStopEvent); !strings.Contains(stop.Reason, beadID) {
+		t.Errorf("drain stop reason = %q, want it to name %s", stop.Reason, beadID)
+	}
+
+	// A closed bead cannot be run again
+	if _, err := ctrl.RunBead(ctx, beadID); err == nil {
+		t.Error("expected error running a closed bead")
+	}
+}
+
+// createCwdRecordingMockClaude creates a script that appends its working
+// directory to logPath and holds the session open briefly so concurrent
+// sessions overlap.
+func createCwdRecordingMockClaude(path, logPath string) error {
+	script := `#!/bin/bash
+cat > /dev/null 2>&1
+pwd >> "` + logPath + `"
+echo '{"type":"system","subtype":"init","session_id":"pool-001","cwd":"/workspace","tools":[]}'
+sleep 0.3
+echo '{"type":"result","subtype":"success","total_cost_usd":0.01,"duration_ms":300,"num_turns":1,"session_id":"pool-001"}'
+exit 0
+`
+	return os.WriteFile(path, []byte(script), 0755)
+}
+
+func TestDrainWithWorkerPool(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	// Create a git repository for worker worktrees
+	repoDir := filepath.Join(env.tempDir, "repo")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatalf("failed to create repo dir: %v", err)
+	}
+	gitEnv := append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+		cmd.Env = gitEnv
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	cwdLog := filepath.Join(env.tempDir, "cwd.log")
+	if err := createCwdRecordingMockClaude(env.mockPath, cwdLog); err != nil {
+		t.Fatalf("failed to create mock claude: %v", err)
+	}
+
+	env.cfg.Workers = 2
+	env.cfg.Git.BranchPerBead = true
+	beads := multipleBeads(2)
+	var mu sync.Mutex
+	closed := make(map[string]bool)
+	env.brClient.DynamicReady = func(ctx context.Context, opts *brclient.ReadyOptions) ([]brclient.Bead, error, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		var ready []brclient.Bead
+		for _, b := range beads {
+			if !closed[b.ID] {
+				ready = append(ready, b)
+			}
+		}
+		return ready, nil, true
+	}
+	env.brClient.DynamicShow = func(ctx context.Context, id string) (*brclient.Bead, error, bool) {
+		// Sessions "close" their bead once they have started
+		mu.Lock()
+		defer mu.Unlock()
+		closed[id] = true
+		return &brclient.Bead{ID: id, Status: "closed"}, nil, true
+	}
+
+	wq := workqueue.New(env.cfg, env.brClient, nil)
+	runner := cmdexec.NewExecRunner()
+	wt := worktree.New(runner, repoDir, ".atari/worktrees")
+	ctrl := controller.New(env.cfg, wq, env.router, env.brClient, nil, nil,
+		controller.WithWorktrees(wt),
+		controller.WithBranches(beadbranch.New(runner, repoDir, "")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ctrl.Run(ctx)
+	}()
+
+	// Both workers should be busy at the same time
+	deadline := time.Now().Add(3 * time.Second)
+	sawBoth := false
+	for time.Now().Before(deadline) && !sawBoth {
+		busy := 0
+		for _, w := range ctrl.Stats().Workers {
+			if w.BeadID != "" {
+				busy++
+			}
+		}
+		sawBoth = busy == 2
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !sawBoth {
+		t.Error("expected both workers to run beads concurrently")
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	ctrl.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for controller to stop")
+	}
+
+	// Each session ran in its own worktree
+	data, err := os.ReadFile(cwdLog)
+	if err != nil {
+		t.Fatalf("failed to read cwd log: %v", err)
+	}
+	dirs := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		dirs[line] = true
+	}
+	if len(dirs) != 2 {
+		t.Errorf("expected sessions in 2 distinct directories, got %v", dirs)
+	}
+	for dir := range dirs {
+		if !strings.Contains(dir, filepath.Join(".atari", "worktrees", "worker-")) {
+			t.Errorf("expected session to run in a worker worktree, got %s", dir)
+		}
+	}
+
+	env.collectEvents(100 * time.Millisecond)
+	if n := env.countEvents(events.EventIterationEnd); n != 2 {
+		t.Errorf("expected 2 iteration end events, got %d", n)
+	}
+}
+
+// createJSONLAgent creates a script that speaks the jsonl agent backend:
+// plain text lines and atari events, one per line.
+func createJSONLAgent(path string) error {
+	script := `#!/bin/bash
+# Read the prompt from stdin until EOF
+cat > /dev/null 2>&1
+
+echo 'starting local agent'
+echo '{"type":"claude.tool_use","tool_id":"t1","tool_name":"Bash","input":{"command":"make test"}}'
+echo '{"type":"claude.tool_result","tool_id":"t1","content":"ok"}'
+echo '{"type":"turn.complete","tool_count":1}'
+echo '{"type":"session.cost","estimated_cost_usd":0.02}'
+echo '{"type":"session.end","session_id":"local-001","num_turns":1,"total_cost_usd":0.03}'
+exit 0
+`
+	return os.WriteFile(path, []byte(script), 0755)
+}
+
+func TestDrainWithJSONLBackend(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	agentPath := filepath.Join(env.tempDir, "local-agent")
+	if err := createJSONLAgent(agentPath); err != nil {
+		t.Fatalf("failed to create agent script: %v", err)
+	}
+	env.cfg.Agent = config.AgentConfig{Backend: session.BackendJSONL, Command: []string{agentPath}}
+	backend, err := session.NewBackend(env.cfg)
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+
+	callCount := 0
+	env.brClient.DynamicReady = func(ctx context.Context, opts *brclient.ReadyOptions) ([]brclient.Bead, error, bool) {
+		callCount++
+		if callCount > 1 {
+			return nil, nil, true
+		}
+		return []brclient.Bead{singleBead("bd-001", "Test bead 1")}, nil, true
+	}
+	// The agent closes the bead during its session
+	env.brClient.DynamicShow = func(ctx context.Context, id string) (*brclient.Bead, error, bool) {
+		return &brclient.Bead{ID: id, Status: "closed"}, nil, true
+	}
+
+	wq := workqueue.New(env.cfg, env.brClient, nil)
+	ctrl := controller.New(env.cfg, wq, env.router, env.brClient, nil, nil,
+		controller.WithBackend(backend))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ctrl.Run(ctx)
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	ctrl.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for controller to stop")
+	}
+
+	env.collectEvents(100 * time.Millisecond)
+
+	if evt := env.findEvent(events.EventClaudeText); evt == nil || evt.(*events.ClaudeTextEvent).Text != "starting local agent" {
+		t.Errorf("expected plain output as a text event, got %v", evt)
+	}
+	if evt := env.findEvent(events.EventClaudeToolUse); evt == nil {
+		t.Error("expected ClaudeToolUseEvent")
+	}
+	if evt := env.findEvent(events.EventIterationEnd); evt == nil {
+		t.Error("expected IterationEndEvent")
+	} else if iterEvt := evt.(*events.IterationEndEvent); iterEvt.TotalCostUSD != 0.03 || iterEvt.SessionID != "local-001" {
+		t.Errorf("expected cost and session from session.end, got %+v", iterEvt)
+	}
+}
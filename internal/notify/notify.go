@@ -0,0 +1,356 @@
+// Package notify sends webhook and desktop notifications for drain
+// milestones, so a stalled drain does not sit unnoticed while nobody is
+// watching the TUI.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/exec"
+)
+
+// Notification kinds. Each matches the event type that triggers it, except
+// KindBudget, which is sent instead of KindStall for budget stalls.
+const (
+	KindStall      = string(events.EventDrainStall)
+	KindAbandoned  = string(events.EventBeadAbandoned)
+	KindEpicClosed = string(events.EventEpicClosed)
+	KindDrainStop  = string(events.EventDrainStop)
+	KindBudget     = "budget.exhausted"
+)
+
+// Kinds lists every notification kind.
+var Kinds = []string{KindStall, KindAbandoned, KindEpicClosed, KindBudget, KindDrainStop}
+
+// deliveryTimeout bounds each webhook request and notify-send call.
+const deliveryTimeout = 10 * time.Second
+
+// queueSize is how many notifications may wait for delivery. Milestones are
+// rare, so a full queue means the destinations are failing and more are dropped.
+const queueSize = 64
+
+// Notification is the data sent for one milestone. It is the default webhook
+// payload and the data passed to webhook templates.
+type Notification struct {
+	Kind      string    `json:"event"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	BeadID    string    `json:"bead_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// FromEvent builds the notification for an event. It returns false for
+// events that are not drain milestones.
+func FromEvent(ev events.Event) (*Notification, bool) {
+	n := &Notification{Timestamp: ev.Timestamp()}
+	switch e := ev.(type) {
+	case *events.StallEvent:
+		n.BeadID = e.BeadID
+		if e.StallType == "budget" {
+			n.Kind = KindBudget
+			n.Title = "atari: budget exhausted"
+			n.Message = e.Reason
+			break
+		}
+		n.Kind = KindStall
+		n.Title = "atari: drain stalled"
+		n.Message = fmt.Sprintf("%s %s: %s", e.BeadID, e.Title, e.Reason)
+	case *events.BeadAbandonedEvent:
+		n.Kind = KindAbandoned
+		n.BeadID = e.BeadID
+		n.Title = "atari: bead abandoned"
+		n.Message = fmt.Sprintf("%s abandoned after %d attempts: %s", e.BeadID, e.Attempts, e.LastError)
+	case *events.EpicClosedEvent:
+		n.Kind = KindEpicClosed
+		n.BeadID = e.EpicID
+		n.Title = "atari: epic closed"
+		n.Message = fmt.Sprintf("%s %s closed with %d children done", e.EpicID, e.Title, e.TotalChildren)
+	case *events.DrainStopEvent:
+		n.Kind = KindDrainStop
+		n.Title = "atari: drain stopped"
+		n.Message = "drain stopped"
+		if e.Reason != "" {
+			n.Message += ": " + e.Reason
+		}
+	default:
+		return nil, false
+	}
+	return n, true
+}
+
+// webhook is a configured webhook with its parsed body template.
+type webhook struct {
+	config.WebhookConfig
+	tmpl *template.Template // nil for the default JSON payload
+}
+
+// Sink is an events.Sink that delivers drain milestones to webhooks and the
+// desktop.
+type Sink struct {
+	cfg      config.NotifyConfig
+	webhooks []webhook
+	runner   exec.CommandRunner
+	client   *http.Client
+	logger   *slog.Logger
+	now      func() time.Time
+	started  bool
+	queue    chan *Notification
+	done     chan struct{}
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+// New creates a Sink for the configuration. Desktop notifications run
+// notify-send through runner. It returns an error for an unknown
+// notification kind or an invalid webhook template.
+func New(cfg config.NotifyConfig, runner exec.CommandRunner, logger *slog.Logger) (*Sink, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if err := checkKinds(cfg.Events); err != nil {
+		return nil, fmt.Errorf("notify.events: %w", err)
+	}
+
+	s := &Sink{
+		cfg:      cfg,
+		runner:   runner,
+		client:   &http.Client{Timeout: deliveryTimeout},
+		logger:   logger,
+		now:      time.Now,
+		lastSent: make(map[string]time.Time),
+		queue:    make(chan *Notification, queueSize),
+		done:     make(chan struct{}),
+	}
+	for i, hook := range cfg.Webhooks {
+		if hook.URL == "" {
+			return nil, fmt.Errorf("notify.webhooks[%d]: url is required", i)
+		}
+		if err := checkKinds(hook.Events); err != nil {
+			return nil, fmt.Errorf("notify.webhooks[%d].events: %w", i, err)
+		}
+		w := webhook{WebhookConfig: hook}
+		if hook.Template != "" {
+			tmpl, err := template.New(fmt.Sprintf("webhook-%d", i)).Funcs(templateFuncs).Parse(hook.Template)
+			if err != nil {
+				return nil, fmt.Errorf("notify.webhooks[%d].template: %w", i, err)
+			}
+			w.tmpl = tmpl
+		}
+		s.webhooks = append(s.webhooks, w)
+	}
+	return s, nil
+}
+
+// templateFuncs are available in webhook templates. json encodes a value,
+// so {"text": {{json .Message}}} yields a valid JSON string.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// checkKinds returns an error for any name that is not a notification kind.
+func checkKinds(kinds []string) error {
+	for _, k := range kinds {
+		if !slices.Contains(Kinds, k) {
+			return fmt.Errorf("unknown notification %q (valid: %v)", k, Kinds)
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether any destination is configured.
+func (s *Sink) Enabled() bool {
+	return s.cfg.Desktop || len(s.webhooks) > 0
+}
+
+// Start begins delivering notifications for events. It runs until the
+// context is canceled or the events channel is closed. Deliveries run on
+// their own goroutine, so a slow webhook does not hold up reading ch and
+// make the router drop events for this sink.
+func (s *Sink) Start(ctx context.Context, ch <-chan events.Event) error {
+	s.started = true
+	// Deliveries are bounded by deliveryTimeout rather than ctx, so that
+	// milestones queued at shutdown, such as the final drain stop, still go out.
+	go s.deliverQueued(context.WithoutCancel(ctx))
+	go s.run(ctx, ch)
+	return nil
+}
+
+func (s *Sink) run(ctx context.Context, ch <-chan events.Event) {
+	defer close(s.queue)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case ev, ok := <-ch:
+					if !ok {
+						return
+					}
+					s.enqueue(ev)
+				default:
+					return
+				}
+			}
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			s.enqueue(ev)
+		}
+	}
+}
+
+// enqueue queues the notification for ev, if any, dropping it when the
+// queue is full.
+func (s *Sink) enqueue(ev events.Event) {
+	n, ok := s.notification(ev)
+	if !ok {
+		return
+	}
+	select {
+	case s.queue <- n:
+	default:
+		s.logger.Warn("notification queue full, dropping notification", "event", n.Kind, "bead", n.BeadID)
+	}
+}
+
+// deliverQueued delivers queued notifications until the queue is closed.
+func (s *Sink) deliverQueued(ctx context.Context) {
+	defer close(s.done)
+	for n := range s.queue {
+		s.deliver(ctx, n)
+	}
+}
+
+// Stop waits for pending deliveries to finish. It returns immediately if the
+// sink was never started.
+func (s *Sink) Stop() error {
+	if !s.started {
+		return nil
+	}
+	<-s.done
+	return nil
+}
+
+// Handle sends the notification for ev, if it is a milestone that passes the
+// configured filters and rate limit. Delivery errors are logged.
+func (s *Sink) Handle(ctx context.Context, ev events.Event) {
+	if n, ok := s.notification(ev); ok {
+		s.deliver(ctx, n)
+	}
+}
+
+// notification returns the notification for ev if it is a milestone that
+// passes the configured filters and rate limit.
+func (s *Sink) notification(ev events.Event) (*Notification, bool) {
+	n, ok := FromEvent(ev)
+	if !ok || !wants(s.cfg.Events, n.Kind) || !s.allow(n) {
+		return nil, false
+	}
+	return n, true
+}
+
+// deliver sends a notification to every destination that wants it.
+func (s *Sink) deliver(ctx context.Context, n *Notification) {
+	if s.cfg.Desktop {
+		if err := s.sendDesktop(ctx, n); err != nil {
+			s.logger.Warn("desktop notification failed", "event", n.Kind, "error", err)
+		}
+	}
+	for i := range s.webhooks {
+		hook := &s.webhooks[i]
+		if !wants(hook.Events, n.Kind) {
+			continue
+		}
+		if err := s.sendWebhook(ctx, hook, n); err != nil {
+			s.logger.Warn("webhook notification failed", "event", n.Kind, "url", hook.URL, "error", err)
+		}
+	}
+}
+
+// wants reports whether a kind passes a filter. An empty filter passes all.
+func wants(filter []string, kind string) bool {
+	return len(filter) == 0 || slices.Contains(filter, kind)
+}
+
+// allow applies the rate limit and records the send. The limit is per kind
+// and bead, so repeats for one bead are limited while two different epics
+// closing back to back both get through.
+func (s *Sink) allow(n *Notification) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := n.Kind + "/" + n.BeadID
+	now := s.now()
+	if last, ok := s.lastSent[key]; ok && s.cfg.MinInterval > 0 && now.Sub(last) < s.cfg.MinInterval {
+		s.logger.Debug("notification rate limited", "event", n.Kind, "bead", n.BeadID)
+		return false
+	}
+	s.lastSent[key] = now
+	return true
+}
+
+// sendDesktop shows the notification with notify-send.
+func (s *Sink) sendDesktop(ctx context.Context, n *Notification) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	_, err := s.runner.Run(ctx, "notify-send", "--app-name=atari", n.Title, n.Message)
+	return err
+}
+
+// sendWebhook POSTs the notification to a webhook.
+func (s *Sink) sendWebhook(ctx context.Context, hook *webhook, n *Notification) error {
+	var body []byte
+	if hook.tmpl != nil {
+		var buf bytes.Buffer
+		if err := hook.tmpl.Execute(&buf, n); err != nil {
+			return fmt.Errorf("render template: %w", err)
+		}
+		body = buf.Bytes()
+	} else {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+		body = data
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/testutil"
+)
+
+// recorder is a webhook endpoint that records request bodies.
+type recorder struct {
+	mu      sync.Mutex
+	bodies  []string
+	headers []http.Header
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+	r.mu.Unlock()
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.bodies...)
+}
+
+func stall(beadID, stallType string) *events.StallEvent {
+	return &events.StallEvent{
+		BaseEvent: events.NewInternalEvent(events.EventDrainStall),
+		BeadID:    beadID,
+		Title:     "Fix login",
+		Reason:    "max failures reached",
+		StallType: stallType,
+	}
+}
+
+func TestFromEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   events.Event
+		kind    string
+		message string
+	}{
+		{"stall", stall("bd-1", "abandoned"), KindStall, "bd-1 Fix login: max failures reached"},
+		{"budget stall", stall("bd-1", "budget"), KindBudget, "max failures reached"},
+		{"abandoned", &events.BeadAbandonedEvent{
+			BaseEvent: events.NewInternalEvent(events.EventBeadAbandoned),
+			BeadID:    "bd-2",
+			Attempts:  5,
+			LastError: "tests fail",
+		}, KindAbandoned, "bd-2 abandoned after 5 attempts: tests fail"},
+		{"epic closed", &events.EpicClosedEvent{
+			BaseEvent:     events.NewInternalEvent(events.EventEpicClosed),
+			EpicID:        "bd-epic",
+			Title:         "Auth",
+			TotalChildren: 3,
+		}, KindEpicClosed, "bd-epic Auth closed with 3 children done"},
+		{"drain stop", &events.DrainStopEvent{
+			BaseEvent: events.NewInternalEvent(events.EventDrainStop),
+			Reason:    "no work",
+		}, KindDrainStop, "drain stopped: no work"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, ok := FromEvent(tt.event)
+			if !ok {
+				t.Fatal("expected a notification")
+			}
+			if n.Kind != tt.kind || n.Message != tt.message {
+				t.Errorf("got %s %q, want %s %q", n.Kind, n.Message, tt.kind, tt.message)
+			}
+		})
+	}
+
+	if _, ok := FromEvent(&events.IterationStartEvent{BaseEvent: events.NewInternalEvent(events.EventIterationStart)}); ok {
+		t.Error("iteration.start should not notify")
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.NotifyConfig
+		want string
+	}{
+		{"unknown event", config.NotifyConfig{Events: []string{"drain.start"}}, "notify.events"},
+		{"missing url", config.NotifyConfig{Webhooks: []config.WebhookConfig{{}}}, "url is required"},
+		{"bad template", config.NotifyConfig{Webhooks: []config.WebhookConfig{{URL: "http://x", Template: "{{"}}}, "template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg, nil, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("New() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSink_Webhooks(t *testing.T) {
+	plain := &recorder{}
+	slack := &recorder{}
+	plainServer := httptest.NewServer(plain)
+	defer plainServer.Close()
+	slackServer := httptest.NewServer(slack)
+	defer slackServer.Close()
+
+	sink, err := New(config.NotifyConfig{
+		Webhooks: []config.WebhookConfig{
+			{URL: plainServer.URL, Headers: map[string]string{"authorization": "Bearer token"}},
+			{URL: slackServer.URL, Template: `{"text": {{json .Message}}}`, Events: []string{KindStall}},
+		},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	ctx := context.Background()
+	sink.Handle(ctx, stall("bd-1", "review"))
+	sink.Handle(ctx, &events.DrainStopEvent{BaseEvent: events.NewInternalEvent(events.EventDrainStop)})
+
+	got := plain.received()
+	if len(got) != 2 {
+		t.Fatalf("plain webhook got %d requests, want 2", len(got))
+	}
+	var n Notification
+	if err := json.Unmarshal([]byte(got[0]), &n); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if n.Kind != KindStall || n.BeadID != "bd-1" || n.Title != "atari: drain stalled" {
+		t.Errorf("payload = %+v", n)
+	}
+	if auth := plain.headers[0].Get("Authorization"); auth != "Bearer token" {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	got = slack.received()
+	if len(got) != 1 || got[0] != `{"text": "bd-1 Fix login: max failures reached"}` {
+		t.Errorf("slack webhook got %q, want only the stall", got)
+	}
+}
+
+func TestSink_DesktopFilterAndRateLimit(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		return nil, nil, name == "notify-send"
+	}
+	sink, err := New(config.NotifyConfig{
+		Desktop:     true,
+		Events:      []string{KindStall},
+		MinInterval: time.Minute,
+	}, runner, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	now := time.Now()
+	sink.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	sink.Handle(ctx, stall("bd-1", "abandoned"))
+	sink.Handle(ctx, stall("bd-1", "abandoned")) // rate limited
+	sink.Handle(ctx, stall("bd-2", "abandoned")) // another bead, sent
+	sink.Handle(ctx, stall("bd-3", "budget"))    // filtered out
+
+	now = now.Add(2 * time.Minute)
+	sink.Handle(ctx, stall("bd-1", "abandoned"))
+
+	calls := runner.GetCalls()
+	if len(calls) != 3 {
+		t.Fatalf("got %d notify-send calls, want 3: %+v", len(calls), calls)
+	}
+	for i, bead := range []string{"bd-1", "bd-2", "bd-1"} {
+		if calls[i].Name != "notify-send" || !strings.Contains(strings.Join(calls[i].Args, " "), bead) {
+			t.Errorf("call %d = %+v, want %s", i, calls[i], bead)
+		}
+	}
+}
+
+func TestSink_SlowWebhookDoesNotBlockEvents(t *testing.T) {
+	release := make(chan struct{})
+	hook := &recorder{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		hook.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	sink, err := New(config.NotifyConfig{Webhooks: []config.WebhookConfig{{URL: server.URL}}}, nil, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	ch := make(chan events.Event)
+	if err := sink.Start(context.Background(), ch); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	for _, bead := range []string{"bd-1", "bd-2", "bd-3"} {
+		select {
+		case ch <- stall(bead, "abandoned"):
+		case <-time.After(time.Second):
+			t.Fatalf("sink stopped reading events while a webhook was pending")
+		}
+	}
+	close(release)
+	close(ch)
+	_ = sink.Stop()
+
+	if got := hook.received(); len(got) != 3 {
+		t.Errorf("got %d requests, want 3", len(got))
+	}
+}
+
+func TestSink_DeliversQueuedEventsOnShutdown(t *testing.T) {
+	hook := &recorder{}
+	server := httptest.NewServer(hook)
+	defer server.Close()
+
+	sink, err := New(config.NotifyConfig{Webhooks: []config.WebhookConfig{{URL: server.URL}}}, nil, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	ch := make(chan events.Event, 1)
+	ch <- &events.DrainStopEvent{BaseEvent: events.NewInternalEvent(events.EventDrainStop)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sink.Start(ctx, ch); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	_ = sink.Stop()
+
+	if got := hook.received(); len(got) != 1 {
+		t.Errorf("got %d requests, want the queued drain stop", len(got))
+	}
+}
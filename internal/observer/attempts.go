@@ -0,0 +1,89 @@
+package observer
+
+import (
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+// maxAttemptToolCalls limits how many tool calls an AttemptSummary lists.
+const maxAttemptToolCalls = 15
+
+// AttemptSummary condenses the events logged for one failed session on a bead.
+type AttemptSummary struct {
+	BeadID      string
+	Start       time.Time
+	End         time.Time
+	Error       string         // error reported when the attempt ended
+	ToolCounts  map[string]int // tool calls by tool name
+	ToolCalls   int            // total tool calls
+	ToolErrors  int            // tool results flagged as errors
+	RecentTools []string       // the last tool calls, e.g. "Bash go test ./..."
+}
+
+// LastFailedAttempt summarizes the most recent failed attempt on a bead.
+// The attempt is located with ReadByBeadID. Tool calls carry no bead ID, so
+// they are taken from the log between the attempt's session start and its
+// iteration end, which is only accurate when one session runs at a time.
+// Returns nil if the log holds no failed attempt for the bead.
+func (r *LogReader) LastFailedAttempt(beadID string) (*AttemptSummary, error) {
+	beadEvents, err := r.ReadByBeadID(beadID)
+	if err != nil {
+		return nil, err
+	}
+
+	var start time.Time
+	var summary *AttemptSummary
+	for _, ev := range beadEvents {
+		switch e := ev.(type) {
+		case *events.SessionStartEvent:
+			start = e.Timestamp()
+		case *events.IterationEndEvent:
+			if e.Success || start.IsZero() {
+				continue
+			}
+			summary = &AttemptSummary{
+				BeadID: beadID,
+				Start:  start,
+				End:    e.Timestamp(),
+				Error:  e.Error,
+			}
+		}
+	}
+	if summary == nil {
+		return nil, nil
+	}
+
+	window, err := r.ReadAfterTimestamp(summary.Start)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.ToolCounts = make(map[string]int)
+	var calls []string
+	for _, ev := range window {
+		if ev.Timestamp().After(summary.End) {
+			break
+		}
+		switch e := ev.(type) {
+		case *events.ClaudeToolUseEvent:
+			summary.ToolCalls++
+			summary.ToolCounts[e.ToolName]++
+			call := e.ToolName
+			if detail := events.ExtractToolDetail(e.ToolName, e.Input); detail != "" {
+				call += " " + detail
+			}
+			calls = append(calls, call)
+		case *events.ClaudeToolResultEvent:
+			if e.IsError {
+				summary.ToolErrors++
+			}
+		}
+	}
+	if len(calls) > maxAttemptToolCalls {
+		calls = calls[len(calls)-maxAttemptToolCalls:]
+	}
+	summary.RecentTools = calls
+
+	return summary, nil
+}
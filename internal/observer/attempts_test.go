@@ -0,0 +1,105 @@
+package observer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+func TestLastFailedAttempt(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(eventType events.EventType, src string, offset int) events.BaseEvent {
+		return events.BaseEvent{EventType: eventType, Time: base.Add(time.Duration(offset) * time.Second), Src: src}
+	}
+	toolUse := func(offset int, name string, input map[string]any) events.Event {
+		return &events.ClaudeToolUseEvent{BaseEvent: at(events.EventClaudeToolUse, events.SourceClaude, offset), ToolName: name, Input: input}
+	}
+
+	evs := []events.Event{
+		// First attempt: failed
+		&events.SessionStartEvent{BaseEvent: at(events.EventSessionStart, events.SourceInternal, 0), BeadID: "bd-1"},
+		toolUse(1, "Read", map[string]any{"file_path": "/repo/old.go"}),
+		&events.IterationEndEvent{BaseEvent: at(events.EventIterationEnd, events.SourceInternal, 2), BeadID: "bd-1", Error: "first failure"},
+		// Another bead in between
+		&events.SessionStartEvent{BaseEvent: at(events.EventSessionStart, events.SourceInternal, 3), BeadID: "bd-2"},
+		toolUse(4, "Write", map[string]any{"file_path": "/repo/other.go"}),
+		&events.IterationEndEvent{BaseEvent: at(events.EventIterationEnd, events.SourceInternal, 5), BeadID: "bd-2", Success: true},
+		// Second attempt: failed
+		&events.SessionStartEvent{BaseEvent: at(events.EventSessionStart, events.SourceInternal, 10), BeadID: "bd-1"},
+		toolUse(11, "Bash", map[string]any{"command": "go test ./..."}),
+		&events.ClaudeToolResultEvent{BaseEvent: at(events.EventClaudeToolResult, events.SourceClaude, 12), IsError: true},
+		toolUse(13, "Edit", map[string]any{"file_path": "/repo/main.go"}),
+		toolUse(14, "Bash", map[string]any{"command": "go test ./..."}),
+		&events.IterationEndEvent{BaseEvent: at(events.EventIterationEnd, events.SourceInternal, 15), BeadID: "bd-1", Error: "second failure"},
+		toolUse(16, "Read", map[string]any{"file_path": "/repo/after.go"}),
+	}
+
+	r := NewLogReader(createTempFileWithEvents(t, evs))
+	summary, err := r.LastFailedAttempt("bd-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary == nil {
+		t.Fatal("expected a summary")
+	}
+
+	if summary.Error != "second failure" {
+		t.Errorf("Error = %q, want second failure", summary.Error)
+	}
+	if summary.ToolCalls != 3 || summary.ToolCounts["Bash"] != 2 || summary.ToolCounts["Edit"] != 1 {
+		t.Errorf("unexpected tool counts: %d %v", summary.ToolCalls, summary.ToolCounts)
+	}
+	if summary.ToolErrors != 1 {
+		t.Errorf("ToolErrors = %d, want 1", summary.ToolErrors)
+	}
+	want := []string{"Bash go test ./...", "Edit main.go", "Bash go test ./..."}
+	if fmt.Sprint(summary.RecentTools) != fmt.Sprint(want) {
+		t.Errorf("RecentTools = %q, want %q", summary.RecentTools, want)
+	}
+}
+
+func TestLastFailedAttempt_LimitsToolCalls(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	evs := []events.Event{
+		&events.SessionStartEvent{BaseEvent: events.BaseEvent{EventType: events.EventSessionStart, Time: base}, BeadID: "bd-1"},
+	}
+	for i := 1; i <= maxAttemptToolCalls+5; i++ {
+		evs = append(evs, &events.ClaudeToolUseEvent{
+			BaseEvent: events.BaseEvent{EventType: events.EventClaudeToolUse, Time: base.Add(time.Duration(i) * time.Second)},
+			ToolName:  "Bash",
+			Input:     map[string]any{"command": fmt.Sprintf("step %d", i)},
+		})
+	}
+	evs = append(evs, &events.IterationEndEvent{
+		BaseEvent: events.BaseEvent{EventType: events.EventIterationEnd, Time: base.Add(time.Hour)},
+		BeadID:    "bd-1",
+	})
+
+	summary, err := NewLogReader(createTempFileWithEvents(t, evs)).LastFailedAttempt("bd-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.ToolCalls != maxAttemptToolCalls+5 {
+		t.Errorf("ToolCalls = %d, want %d", summary.ToolCalls, maxAttemptToolCalls+5)
+	}
+	if len(summary.RecentTools) != maxAttemptToolCalls || summary.RecentTools[0] != "Bash step 6" {
+		t.Errorf("expected the last %d calls, got %q", maxAttemptToolCalls, summary.RecentTools)
+	}
+}
+
+func TestLastFailedAttempt_NoFailure(t *testing.T) {
+	evs := []events.Event{
+		&events.SessionStartEvent{BaseEvent: events.NewInternalEvent(events.EventSessionStart), BeadID: "bd-1"},
+		&events.IterationEndEvent{BaseEvent: events.NewInternalEvent(events.EventIterationEnd), BeadID: "bd-1", Success: true},
+	}
+
+	summary, err := NewLogReader(createTempFileWithEvents(t, evs)).LastFailedAttempt("bd-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary != nil {
+		t.Errorf("expected nil summary, got %+v", summary)
+	}
+}
@@ -99,6 +99,11 @@ func (r *LogReader) ReadAfterTimestamp(t time.Time) ([]events.Event, error) {
 	return filtered, nil
 }
 
+// ReadAll returns every event in the log file, in order.
+func (r *LogReader) ReadAll() ([]events.Event, error) {
+	return r.readAllEvents()
+}
+
 // readAllEvents reads and parses all events from the log file.
 func (r *LogReader) readAllEvents() ([]events.Event, error) {
 	file, err := os.Open(r.path)
@@ -256,6 +256,30 @@ func TestReadAfterTimestamp_NoMatches(t *testing.T) {
 	}
 }
 
+func TestReadAll(t *testing.T) {
+	var evs []events.Event
+	for _, text := range []string{"first", "second", "third"} {
+		evs = append(evs, &events.ClaudeTextEvent{
+			BaseEvent: events.NewClaudeEvent(events.EventClaudeText),
+			Text:      text,
+		})
+	}
+	tmpFile := createTempFileWithEvents(t, evs)
+
+	r := NewLogReader(tmpFile)
+	result, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(result))
+	}
+	if text := result[2].(*events.ClaudeTextEvent).Text; text != "third" {
+		t.Errorf("expected events in log order, last was %q", text)
+	}
+}
+
 func TestParseEvent_AllTypes(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -0,0 +1,238 @@
+// Package plan previews the order a drain would work through beads. It runs
+// the work queue's own selection against a snapshot of br, pretending each
+// chosen bead closes, without starting any sessions.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// StopLimit is the stop reason when a plan reaches Options.Limit. Otherwise
+// it is the work queue's selection reason or a drain-until condition.
+const StopLimit = "plan limit reached"
+
+// Plan is the previewed drain order.
+type Plan struct {
+	SelectionMode    string    `json:"selection_mode"`
+	Steps            []Step    `json:"steps"`
+	Skipped          []Skipped `json:"skipped,omitempty"`
+	Remaining        int       `json:"remaining,omitempty"` // eligible beads the plan stopped before
+	EstimatedCostUSD float64   `json:"estimated_cost_usd"`
+	Unestimated      int       `json:"unestimated,omitempty"` // steps with no cost history
+	StopReason       string    `json:"stop_reason"`
+}
+
+// Step is one bead the drain would work on.
+type Step struct {
+	Step             int      `json:"step"`
+	BeadID           string   `json:"bead_id"`
+	Title            string   `json:"title"`
+	Priority         int      `json:"priority"`
+	Epic             string   `json:"epic,omitempty"`
+	EpicTitle        string   `json:"epic_title,omitempty"`
+	SelectedBecause  string   `json:"selected_because"`
+	EstimatedCostUSD float64  `json:"estimated_cost_usd,omitempty"`
+	CostBasis        string   `json:"cost_basis,omitempty"`
+	Unblocks         []string `json:"unblocks,omitempty"` // beads that become ready once it closes
+}
+
+// Skipped is an open bead the drain would not work on, and why.
+type Skipped struct {
+	BeadID string `json:"bead_id"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// Options configures Build.
+type Options struct {
+	// Limit stops the plan after this many steps (0 = no limit).
+	Limit int
+
+	// History is the bead history from the state file, so beads in backoff
+	// or already completed are treated as the drain would.
+	History map[string]*workqueue.BeadHistory
+
+	// ActiveTopLevel is the top-level item a top-level drain is working
+	// through, from the state file.
+	ActiveTopLevel string
+
+	// Costs estimates each step's cost. Nil leaves steps unestimated.
+	Costs workqueue.CostEstimator
+}
+
+// Build previews the drain order for cfg. It snapshots br through client,
+// then repeatedly selects the next bead as the drain would, closes it in the
+// snapshot and re-evaluates which beads are ready, until nothing is left or
+// a drain-until condition or the step limit is reached.
+func Build(ctx context.Context, cfg *config.Config, client brclient.WorkQueueClient, opts Options) (*Plan, error) {
+	snap, err := TakeSnapshot(ctx, client, &brclient.ReadyOptions{
+		Label:          cfg.WorkQueue.Label,
+		UnassignedOnly: cfg.WorkQueue.UnassignedOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wq := workqueue.New(cfg, snap, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if len(opts.History) > 0 {
+		wq.SetHistory(opts.History)
+	}
+	if opts.Costs != nil {
+		wq.SetCostEstimator(opts.Costs)
+	}
+	topLevel := cfg.WorkQueue.Epic == "" && cfg.WorkQueue.SelectionMode == workqueue.ModeTopLevel
+	if topLevel && opts.ActiveTopLevel != "" {
+		wq.SetActiveTopLevel(opts.ActiveTopLevel)
+	}
+
+	p := &Plan{SelectionMode: cfg.WorkQueue.SelectionMode}
+	if !topLevel {
+		p.SelectionMode = wq.Strategy().Name()
+	}
+
+	for {
+		if opts.Limit > 0 && len(p.Steps) >= opts.Limit {
+			p.StopReason = StopLimit
+			break
+		}
+
+		var bead *workqueue.Bead
+		var reason workqueue.SelectionReason
+		if topLevel {
+			bead, reason, err = wq.NextTopLevel(ctx)
+		} else {
+			bead, reason, err = wq.Next(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("select bead: %w", err)
+		}
+		if bead == nil {
+			p.StopReason = reason.String()
+			break
+		}
+
+		step := Step{
+			Step:            len(p.Steps) + 1,
+			BeadID:          bead.ID,
+			Title:           bead.Title,
+			Priority:        bead.Priority,
+			SelectedBecause: wq.Explain(bead.ID),
+		}
+		if epic := epicOf(snap, bead.ID); epic != nil {
+			step.Epic, step.EpicTitle = epic.ID, epic.Title
+		}
+		if opts.Costs != nil {
+			if usd, basis, ok := opts.Costs.EstimateCost(*bead); ok {
+				step.EstimatedCostUSD, step.CostBasis = usd, basis
+				p.EstimatedCostUSD += usd
+			} else {
+				p.Unestimated++
+			}
+		}
+
+		wq.RecordSuccess(bead.ID)
+		wq.Release(bead.ID)
+		step.Unblocks = snap.Close(bead.ID)
+		p.Steps = append(p.Steps, step)
+
+		if met := untilMet(cfg.Until, snap, p); met != "" {
+			p.StopReason = met
+			break
+		}
+	}
+
+	planned := make(map[string]bool, len(p.Steps))
+	for _, step := range p.Steps {
+		planned[step.BeadID] = true
+	}
+	for _, s := range skippedBeads(cfg, snap, wq) {
+		switch {
+		case planned[s.BeadID]:
+		case s.Reason == "":
+			p.Remaining++
+		default:
+			p.Skipped = append(p.Skipped, s)
+		}
+	}
+	return p, nil
+}
+
+// skippedBeads explains why each bead left open by the plan is not ready or
+// not eligible. Eligible ready beads get an empty reason.
+func skippedBeads(cfg *config.Config, snap *Snapshot, wq *workqueue.Manager) []Skipped {
+	beads, _ := snap.List(context.Background(), nil)
+	var skipped []Skipped
+	for _, b := range beads {
+		if isClosed(b.Status) || b.IssueType == "epic" {
+			continue
+		}
+		s := Skipped{BeadID: b.ID, Title: b.Title}
+		switch {
+		case cfg.WorkQueue.Epic != "" && !underEpic(snap, b.ID, cfg.WorkQueue.Epic):
+			s.Reason = fmt.Sprintf("outside epic %s", cfg.WorkQueue.Epic)
+		case snap.IsReady(b.ID):
+			s.Reason = wq.SkipReason(b)
+		case len(snap.Blockers(b.ID)) > 0:
+			s.Reason = "blocked by " + strings.Join(snap.Blockers(b.ID), ", ")
+		case b.Status != "open":
+			s.Reason = fmt.Sprintf("status is %s", b.Status)
+		default:
+			s.Reason = "not returned by br ready"
+		}
+		skipped = append(skipped, s)
+	}
+	return skipped
+}
+
+// untilMet returns why the drain would stop after the latest step because of
+// a drain-until condition, or "" if it would go on. Cost is the estimated
+// cost; the duration limit cannot be previewed.
+func untilMet(until config.UntilConfig, snap *Snapshot, p *Plan) string {
+	if until.MaxBeads > 0 && len(p.Steps) >= until.MaxBeads {
+		return fmt.Sprintf("completed %d of %d beads", len(p.Steps), until.MaxBeads)
+	}
+	if until.MaxCostUSD > 0 && p.EstimatedCostUSD >= until.MaxCostUSD {
+		return fmt.Sprintf("estimated $%.2f of $%.2f", p.EstimatedCostUSD, until.MaxCostUSD)
+	}
+	if until.EpicClosed != "" {
+		if epic := snap.Bead(until.EpicClosed); epic != nil && isClosed(epic.Status) {
+			return fmt.Sprintf("epic %s closed", until.EpicClosed)
+		}
+	}
+	return ""
+}
+
+// epicOf returns the outermost epic a bead belongs to, or nil for a
+// standalone bead.
+func epicOf(snap *Snapshot, id string) *brclient.Bead {
+	var epic *brclient.Bead
+	seen := make(map[string]bool)
+	for b := snap.Bead(id); b != nil && b.Parent != "" && !seen[b.ID]; {
+		seen[b.ID] = true
+		b = snap.Bead(b.Parent)
+		if b != nil && b.IssueType == "epic" {
+			epic = b
+		}
+	}
+	return epic
+}
+
+// underEpic reports whether a bead is the epic or one of its descendants.
+func underEpic(snap *Snapshot, id, epicID string) bool {
+	seen := make(map[string]bool)
+	for b := snap.Bead(id); b != nil && !seen[b.ID]; b = snap.Bead(b.Parent) {
+		if b.ID == epicID {
+			return true
+		}
+		seen[b.ID] = true
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package plan
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/brclient"
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/workqueue"
+)
+
+// fixedCosts is a CostEstimator backed by a map.
+type fixedCosts map[string]float64
+
+func (f fixedCosts) EstimateCost(bead workqueue.Bead) (float64, string, bool) {
+	usd, ok := f[bead.ID]
+	return usd, "fixed", ok
+}
+
+// newTestClient returns a mock br holding an epic with three beads, one
+// blocked by another, and a standalone bead, plus low-priority beads that
+// are excluded, blocked or in progress elsewhere.
+func newTestClient() *brclient.MockClient {
+	base := time.Now().Add(-time.Hour)
+	blocks := func(id string) []brclient.BeadReference {
+		return []brclient.BeadReference{{ID: id, DependencyType: "blocks"}}
+	}
+	beads := []brclient.Bead{
+		{ID: "bd-epic", Title: "Epic", Status: "open", Priority: 1, IssueType: "epic", CreatedAt: base},
+		{ID: "bd-a", Title: "A", Status: "open", Priority: 1, IssueType: "task", Parent: "bd-epic", CreatedAt: base},
+		{ID: "bd-b", Title: "B", Status: "open", Priority: 2, IssueType: "task", Parent: "bd-epic", CreatedAt: base.Add(2 * time.Minute), Dependencies: blocks("bd-a")},
+		{ID: "bd-c", Title: "C", Status: "open", Priority: 3, IssueType: "task", Parent: "bd-epic", CreatedAt: base},
+		{ID: "bd-s", Title: "Standalone", Status: "open", Priority: 2, IssueType: "task", CreatedAt: base.Add(time.Minute)},
+		{ID: "bd-x", Title: "Manual", Status: "open", Priority: 4, IssueType: "task", Labels: []string{"manual"}, CreatedAt: base},
+		{ID: "bd-f", Title: "Flaky", Status: "open", Priority: 4, IssueType: "task", CreatedAt: base},
+		{ID: "bd-w", Title: "Waiting", Status: "open", Priority: 4, IssueType: "task", CreatedAt: base, Dependencies: blocks("bd-ext")},
+		{ID: "bd-ext", Title: "Elsewhere", Status: "in_progress", Priority: 4, IssueType: "task", CreatedAt: base},
+		{ID: "bd-done", Title: "Done", Status: "closed", Priority: 0, IssueType: "task", CreatedAt: base},
+	}
+
+	client := brclient.NewMockClient()
+	for _, b := range beads {
+		full := b
+		client.SetShowResponse(b.ID, &full)
+		listed := b
+		listed.Parent, listed.Dependencies = "", nil
+		client.ListResponse = append(client.ListResponse, listed)
+		if b.Status == "open" && (len(b.Dependencies) == 0 || b.ID == "bd-epic") {
+			client.ReadyResponse = append(client.ReadyResponse, listed)
+		}
+	}
+	return client
+}
+
+func newTestConfig(mode string) *config.Config {
+	cfg := config.Default()
+	cfg.WorkQueue.SelectionMode = mode
+	cfg.WorkQueue.ExcludeLabels = []string{"manual"}
+	return cfg
+}
+
+// testHistory puts bd-f in backoff after two failed attempts.
+func testHistory() map[string]*workqueue.BeadHistory {
+	return map[string]*workqueue.BeadHistory{
+		"bd-f": {ID: "bd-f", Status: workqueue.HistoryFailed, Attempts: 2, LastAttempt: time.Now()},
+	}
+}
+
+func stepIDs(p *Plan) []string {
+	ids := make([]string, len(p.Steps))
+	for i, s := range p.Steps {
+		ids[i] = s.BeadID
+	}
+	return ids
+}
+
+func TestBuild_Global(t *testing.T) {
+	p, err := Build(context.Background(), newTestConfig(workqueue.ModeGlobal), newTestClient(), Options{History: testHistory()})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if want := []string{"bd-a", "bd-s", "bd-b", "bd-c"}; !slices.Equal(stepIDs(p), want) {
+		t.Fatalf("steps = %v, want %v", stepIDs(p), want)
+	}
+	if p.SelectionMode != workqueue.ModePriority {
+		t.Errorf("SelectionMode = %q, want %q", p.SelectionMode, workqueue.ModePriority)
+	}
+	first := p.Steps[0]
+	if first.Epic != "bd-epic" || first.EpicTitle != "Epic" {
+		t.Errorf("step 1 epic = %q %q, want bd-epic Epic", first.Epic, first.EpicTitle)
+	}
+	if !slices.Equal(first.Unblocks, []string{"bd-b"}) {
+		t.Errorf("step 1 unblocks = %v, want [bd-b]", first.Unblocks)
+	}
+	if first.SelectedBecause == "" {
+		t.Error("step 1 has no selection reason")
+	}
+	if p.Steps[1].Epic != "" {
+		t.Errorf("standalone step epic = %q, want none", p.Steps[1].Epic)
+	}
+	if p.StopReason != workqueue.ReasonBackoff.String() {
+		t.Errorf("StopReason = %q, want %q", p.StopReason, workqueue.ReasonBackoff.String())
+	}
+
+	reasons := make(map[string]string)
+	for _, s := range p.Skipped {
+		reasons[s.BeadID] = s.Reason
+	}
+	wantReasons := map[string]string{
+		"bd-x":   "excluded label",
+		"bd-f":   "in backoff until",
+		"bd-w":   "blocked by bd-ext",
+		"bd-ext": "status is in_progress",
+	}
+	if len(reasons) != len(wantReasons) {
+		t.Errorf("skipped = %v, want %d beads", reasons, len(wantReasons))
+	}
+	for id, want := range wantReasons {
+		if !strings.Contains(reasons[id], want) {
+			t.Errorf("skip reason for %s = %q, want it to contain %q", id, reasons[id], want)
+		}
+	}
+}
+
+func TestBuild_TopLevel(t *testing.T) {
+	p, err := Build(context.Background(), newTestConfig(workqueue.ModeTopLevel), newTestClient(), Options{History: testHistory()})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	// The epic is worked through before the standalone bead
+	if want := []string{"bd-a", "bd-b", "bd-c", "bd-s"}; !slices.Equal(stepIDs(p), want) {
+		t.Errorf("steps = %v, want %v", stepIDs(p), want)
+	}
+	if p.SelectionMode != workqueue.ModeTopLevel {
+		t.Errorf("SelectionMode = %q, want %q", p.SelectionMode, workqueue.ModeTopLevel)
+	}
+}
+
+func TestBuild_Stops(t *testing.T) {
+	tests := []struct {
+		name       string
+		configure  func(*config.Config)
+		opts       Options
+		wantSteps  []string
+		wantReason string
+	}{
+		{
+			name:       "limit",
+			opts:       Options{Limit: 2},
+			wantSteps:  []string{"bd-a", "bd-s"},
+			wantReason: StopLimit,
+		},
+		{
+			name:       "max beads",
+			configure:  func(cfg *config.Config) { cfg.Until.MaxBeads = 1 },
+			wantSteps:  []string{"bd-a"},
+			wantReason: "completed 1 of 1 beads",
+		},
+		{
+			name:       "epic closed",
+			configure:  func(cfg *config.Config) { cfg.Until.EpicClosed = "bd-epic" },
+			wantSteps:  []string{"bd-a", "bd-s", "bd-b", "bd-c"},
+			wantReason: "epic bd-epic closed",
+		},
+		{
+			name:       "max cost",
+			configure:  func(cfg *config.Config) { cfg.Until.MaxCostUSD = 3 },
+			opts:       Options{Costs: fixedCosts{"bd-a": 1, "bd-s": 2}},
+			wantSteps:  []string{"bd-a", "bd-s"},
+			wantReason: "estimated $3.00 of $3.00",
+		},
+		{
+			name:       "epic filter",
+			configure:  func(cfg *config.Config) { cfg.WorkQueue.Epic = "bd-epic" },
+			wantSteps:  []string{"bd-a", "bd-b", "bd-c"},
+			wantReason: workqueue.ReasonNoReady.String(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(workqueue.ModeGlobal)
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			p, err := Build(context.Background(), cfg, newTestClient(), tt.opts)
+			if err != nil {
+				t.Fatalf("Build() error: %v", err)
+			}
+			if !slices.Equal(stepIDs(p), tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", stepIDs(p), tt.wantSteps)
+			}
+			if p.StopReason != tt.wantReason {
+				t.Errorf("StopReason = %q, want %q", p.StopReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestBuild_Costs(t *testing.T) {
+	costs := fixedCosts{"bd-a": 1.5, "bd-b": 0.5}
+	p, err := Build(context.Background(), newTestConfig(workqueue.ModeGlobal), newTestClient(), Options{Limit: 3, Costs: costs})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if p.EstimatedCostUSD != 2 {
+		t.Errorf("EstimatedCostUSD = %v, want 2", p.EstimatedCostUSD)
+	}
+	if p.Unestimated != 1 {
+		t.Errorf("Unestimated = %d, want 1", p.Unestimated)
+	}
+	if p.Steps[0].CostBasis != "fixed" {
+		t.Errorf("step 1 cost basis = %q, want fixed", p.Steps[0].CostBasis)
+	}
+	// bd-c and bd-f are eligible but past the limit
+	if p.Remaining != 2 {
+		t.Errorf("Remaining = %d, want 2", p.Remaining)
+	}
+}
+
+func TestSnapshot_Close(t *testing.T) {
+	snap, err := TakeSnapshot(context.Background(), newTestClient(), nil)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error: %v", err)
+	}
+	if snap.IsReady("bd-b") {
+		t.Fatal("bd-b ready before its blocker closed")
+	}
+
+	if got := snap.Close("bd-a"); !slices.Equal(got, []string{"bd-b"}) {
+		t.Errorf("Close(bd-a) unblocked %v, want [bd-b]", got)
+	}
+	if !snap.IsReady("bd-b") {
+		t.Error("bd-b not ready after its blocker closed")
+	}
+
+	snap.Close("bd-b")
+	if snap.Bead("bd-epic").Status == "closed" {
+		t.Error("epic closed with bd-c still open")
+	}
+	snap.Close("bd-c")
+	if snap.Bead("bd-epic").Status != "closed" {
+		t.Error("epic not closed once all its beads were")
+	}
+
+	ready, _ := snap.Ready(context.Background(), nil)
+	for _, b := range ready {
+		if b.ID == "bd-w" {
+			t.Error("bd-w ready while blocked by an in-progress bead")
+		}
+	}
+}
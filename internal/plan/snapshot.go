@@ -0,0 +1,188 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/npratt/atari/internal/brclient"
+)
+
+// Snapshot is an in-memory copy of the beads in br. It implements
+// brclient.WorkQueueClient so a work queue can select from it, and closing
+// a bead in it unblocks dependents without touching br.
+type Snapshot struct {
+	beads map[string]*brclient.Bead
+	order []string        // bead IDs in br list order
+	ready map[string]bool // beads br ready would return
+	opts  brclient.ReadyOptions
+}
+
+// TakeSnapshot reads every open bead from br, with its parent and
+// dependencies, and which of them br reports ready under opts.
+func TakeSnapshot(ctx context.Context, client brclient.WorkQueueClient, opts *brclient.ReadyOptions) (*Snapshot, error) {
+	listed, err := client.List(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list beads: %w", err)
+	}
+	ready, err := client.Ready(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("list ready beads: %w", err)
+	}
+
+	s := &Snapshot{
+		beads: make(map[string]*brclient.Bead, len(listed)),
+		ready: make(map[string]bool, len(ready)),
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+
+	// br list --json has neither parent nor dependencies, br show does
+	for _, b := range listed {
+		bead := b
+		if !isClosed(b.Status) {
+			full, err := client.Show(ctx, b.ID)
+			if err != nil {
+				return nil, fmt.Errorf("show %s: %w", b.ID, err)
+			}
+			if full != nil {
+				bead = *full
+			}
+		}
+		s.beads[bead.ID] = &bead
+		s.order = append(s.order, bead.ID)
+	}
+	for _, b := range ready {
+		if _, ok := s.beads[b.ID]; ok {
+			s.ready[b.ID] = true
+		}
+	}
+	return s, nil
+}
+
+// Ready implements brclient.WorkQueueClient. The ready options are the ones
+// the snapshot was taken with.
+func (s *Snapshot) Ready(_ context.Context, _ *brclient.ReadyOptions) ([]brclient.Bead, error) {
+	var beads []brclient.Bead
+	for _, id := range s.order {
+		if s.ready[id] {
+			beads = append(beads, *s.beads[id])
+		}
+	}
+	return beads, nil
+}
+
+// List implements brclient.WorkQueueClient.
+func (s *Snapshot) List(_ context.Context, opts *brclient.ListOptions) ([]brclient.Bead, error) {
+	var beads []brclient.Bead
+	for _, id := range s.order {
+		b := s.beads[id]
+		if opts != nil && opts.Status != "" && b.Status != opts.Status {
+			continue
+		}
+		beads = append(beads, *b)
+	}
+	return beads, nil
+}
+
+// Show implements brclient.WorkQueueClient.
+func (s *Snapshot) Show(_ context.Context, id string) (*brclient.Bead, error) {
+	b, ok := s.beads[id]
+	if !ok {
+		return nil, fmt.Errorf("bead not found: %s", id)
+	}
+	bead := *b
+	return &bead, nil
+}
+
+// Bead returns a bead in the snapshot, or nil if there is none with that ID.
+func (s *Snapshot) Bead(id string) *brclient.Bead {
+	return s.beads[id]
+}
+
+// IsReady reports whether br ready would return the bead.
+func (s *Snapshot) IsReady(id string) bool {
+	return s.ready[id]
+}
+
+// Close marks a bead closed, then closes any epic whose beads are now all
+// closed, as the drain's epic auto-close would. It returns the beads that
+// became ready as a result, in list order.
+func (s *Snapshot) Close(id string) []string {
+	closed := []string{id}
+	for len(closed) > 0 {
+		b := s.beads[closed[0]]
+		closed = closed[1:]
+		if b == nil || isClosed(b.Status) {
+			continue
+		}
+		b.Status = "closed"
+		delete(s.ready, b.ID)
+		if parent := s.beads[b.Parent]; parent != nil && parent.IssueType == "epic" && s.childrenClosed(parent.ID) {
+			closed = append(closed, parent.ID)
+		}
+	}
+
+	var unblocked []string
+	for _, id := range s.order {
+		b := s.beads[id]
+		if s.ready[id] || b.Status != "open" || !s.matchesOptions(b) {
+			continue
+		}
+		if blockers := s.Blockers(id); len(blockers) == 0 && s.wasBlocked(b) {
+			s.ready[id] = true
+			unblocked = append(unblocked, id)
+		}
+	}
+	return unblocked
+}
+
+// Blockers returns the open beads that block a bead.
+func (s *Snapshot) Blockers(id string) []string {
+	var blockers []string
+	for _, dep := range s.beads[id].Dependencies {
+		if dep.DependencyType != "blocks" {
+			continue
+		}
+		if blocker := s.beads[dep.ID]; blocker != nil && !isClosed(blocker.Status) {
+			blockers = append(blockers, dep.ID)
+		}
+	}
+	return blockers
+}
+
+// wasBlocked reports whether a bead has any blocking dependency, so beads
+// br held back for some other reason are not made ready when a blocker
+// closes.
+func (s *Snapshot) wasBlocked(b *brclient.Bead) bool {
+	return slices.ContainsFunc(b.Dependencies, func(dep brclient.BeadReference) bool {
+		return dep.DependencyType == "blocks"
+	})
+}
+
+// childrenClosed reports whether every bead under an epic is closed.
+func (s *Snapshot) childrenClosed(epicID string) bool {
+	for _, b := range s.beads {
+		if b.Parent == epicID && !isClosed(b.Status) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesOptions applies the ready options' filters to a bead.
+func (s *Snapshot) matchesOptions(b *brclient.Bead) bool {
+	if s.opts.Label != "" && !slices.Contains(b.Labels, s.opts.Label) {
+		return false
+	}
+	if s.opts.UnassignedOnly && b.Assignee != "" {
+		return false
+	}
+	return true
+}
+
+// isClosed reports whether a bead status means the bead is done.
+func isClosed(status string) bool {
+	return status == "closed" || status == "completed"
+}
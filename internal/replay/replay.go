@@ -0,0 +1,247 @@
+// Package replay re-emits a recorded event log with its original timing, so
+// the TUI can be driven without a controller or bead tracker attached.
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+// DefaultMaxGap is the longest real-time pause between two replayed events.
+// Idle stretches in the log are shortened to this.
+const DefaultMaxGap = 5 * time.Second
+
+// Player emits recorded events to a router, spaced by their timestamps and
+// scaled by a speed factor. It can be paused and can seek in either
+// direction while running.
+type Player struct {
+	events []events.Event
+	router *events.Router
+	speed  float64
+	maxGap time.Duration
+	wall   func() time.Time
+
+	mu       sync.Mutex
+	pos      int       // index of the next event to emit
+	base     time.Time // log time at baseWall
+	baseWall time.Time // wall time when base was set
+	paused   bool
+	wake     chan struct{}
+}
+
+// Option configures a Player.
+type Option func(*Player)
+
+// WithSpeed sets the playback speed as a multiple of real time.
+// Values of zero or less are ignored.
+func WithSpeed(speed float64) Option {
+	return func(p *Player) {
+		if speed > 0 {
+			p.speed = speed
+		}
+	}
+}
+
+// WithMaxGap sets the longest real-time pause between two events
+// (0 = keep every gap).
+func WithMaxGap(d time.Duration) Option {
+	return func(p *Player) {
+		p.maxGap = d
+	}
+}
+
+// WithStartPaused starts playback paused at the first event.
+func WithStartPaused() Option {
+	return func(p *Player) {
+		p.paused = true
+	}
+}
+
+// New creates a Player for events, which must be in log order.
+func New(evs []events.Event, router *events.Router, opts ...Option) *Player {
+	p := &Player{
+		events: evs,
+		router: router,
+		speed:  1,
+		maxGap: DefaultMaxGap,
+		wall:   time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(evs) > 0 {
+		p.base = evs[0].Timestamp()
+	}
+	p.baseWall = p.wall()
+	return p
+}
+
+// Run emits events until ctx is cancelled. Once the log is exhausted
+// playback pauses, and Run keeps serving seeks until ctx is done.
+func (p *Player) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		wait := p.step()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		var tick <-chan time.Time
+		if wait > 0 {
+			timer.Reset(wait)
+			tick = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.wake:
+		case <-tick:
+		}
+	}
+}
+
+// step emits every event that is due and returns how long to wait for the
+// next one. It returns 0 when playback is paused or finished, meaning wait
+// for a control call.
+func (p *Player) step() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		return 0
+	}
+	now := p.nowLocked()
+	for p.pos < len(p.events) && !p.events[p.pos].Timestamp().After(now) {
+		p.router.Emit(p.events[p.pos])
+		p.pos++
+	}
+	if p.pos >= len(p.events) {
+		p.setBaseLocked(now)
+		p.paused = true
+		return 0
+	}
+
+	next := p.events[p.pos].Timestamp()
+	wait := time.Duration(float64(next.Sub(now)) / p.speed)
+	if p.maxGap > 0 && wait > p.maxGap {
+		// Skip the idle stretch so the next event is maxGap away
+		p.setBaseLocked(next.Add(-time.Duration(float64(p.maxGap) * p.speed)))
+		wait = p.maxGap
+	}
+	return max(wait, time.Millisecond)
+}
+
+// TogglePause pauses or resumes playback. Resuming a finished replay
+// starts it again from the beginning.
+func (p *Player) TogglePause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		if p.pos >= len(p.events) && len(p.events) > 0 {
+			p.rewindLocked(p.events[0].Timestamp())
+		}
+		p.baseWall = p.wall()
+		p.paused = false
+	} else {
+		p.setBaseLocked(p.nowLocked())
+		p.paused = true
+	}
+	p.signal()
+}
+
+// Seek moves the playback position by d in log time. Seeking forwards emits
+// the skipped events at once; seeking backwards emits a ReplayRewindEvent
+// and then every event up to the new position.
+func (p *Player) Seek(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.events) == 0 {
+		return
+	}
+	first := p.events[0].Timestamp()
+	last := p.events[len(p.events)-1].Timestamp()
+	target := p.nowLocked().Add(d)
+	if target.Before(first) {
+		target = first
+	}
+	if target.After(last) {
+		target = last
+	}
+
+	if d < 0 {
+		p.rewindLocked(target)
+	}
+	for p.pos < len(p.events) && !p.events[p.pos].Timestamp().After(target) {
+		p.router.Emit(p.events[p.pos])
+		p.pos++
+	}
+	p.setBaseLocked(target)
+	p.signal()
+}
+
+// Paused reports whether playback is paused or finished.
+func (p *Player) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Speed returns the playback speed.
+func (p *Player) Speed() float64 {
+	return p.speed
+}
+
+// Now returns the current position in log time.
+func (p *Player) Now() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.nowLocked()
+}
+
+// Progress returns the number of events emitted and the total in the log.
+func (p *Player) Progress() (played, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pos, len(p.events)
+}
+
+func (p *Player) nowLocked() time.Time {
+	if p.paused {
+		return p.base
+	}
+	elapsed := p.wall().Sub(p.baseWall)
+	return p.base.Add(time.Duration(float64(elapsed) * p.speed))
+}
+
+func (p *Player) setBaseLocked(t time.Time) {
+	p.base = t
+	p.baseWall = p.wall()
+}
+
+// rewindLocked tells consumers to reset and restarts from the first event;
+// the caller then emits events up to target.
+func (p *Player) rewindLocked(target time.Time) {
+	p.router.Emit(&events.ReplayRewindEvent{BaseEvent: events.NewEvent(events.EventReplayRewind, events.SourceInternal)})
+	p.pos = 0
+	p.setBaseLocked(target)
+}
+
+// signal wakes Run to reschedule after a control call.
+func (p *Player) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,175 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+var t0 = time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+func textAt(offset time.Duration, text string) events.Event {
+	return &events.ClaudeTextEvent{
+		BaseEvent: events.BaseEvent{EventType: events.EventClaudeText, Time: t0.Add(offset), Src: events.SourceClaude},
+		Text:      text,
+	}
+}
+
+func recorded() []events.Event {
+	return []events.Event{
+		textAt(0, "a"),
+		textAt(time.Minute, "b"),
+		textAt(2*time.Minute, "c"),
+	}
+}
+
+// collect reads the events currently buffered on ch.
+func collect(ch <-chan events.Event) []string {
+	var got []string
+	for {
+		select {
+		case ev := <-ch:
+			if text, ok := ev.(*events.ClaudeTextEvent); ok {
+				got = append(got, text.Text)
+			} else {
+				got = append(got, string(ev.Type()))
+			}
+		default:
+			return got
+		}
+	}
+}
+
+func TestPlayer_Run(t *testing.T) {
+	router := events.NewRouter(10)
+	defer router.Close()
+	ch := router.Subscribe()
+
+	// A minute of log time is 10ms at this speed
+	p := New(recorded(), router, WithSpeed(6000))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = p.Run(ctx)
+		close(done)
+	}()
+
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < 3 {
+		select {
+		case ev := <-ch:
+			got = append(got, ev.(*events.ClaudeTextEvent).Text)
+		case <-timeout:
+			t.Fatalf("timed out with %v", got)
+		}
+	}
+	if got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("events = %v, want in log order", got)
+	}
+
+	// Once the log is exhausted playback pauses at the last event
+	deadline := time.Now().Add(time.Second)
+	for !p.Paused() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !p.Paused() {
+		t.Error("expected playback to pause at the end")
+	}
+	if played, total := p.Progress(); played != 3 || total != 3 {
+		t.Errorf("Progress() = %d/%d, want 3/3", played, total)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestPlayer_MaxGapShortensIdleStretches(t *testing.T) {
+	router := events.NewRouter(10)
+	defer router.Close()
+	ch := router.Subscribe()
+
+	p := New([]events.Event{textAt(0, "a"), textAt(time.Hour, "b")}, router, WithMaxGap(10*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = p.Run(ctx) }()
+
+	timeout := time.After(5 * time.Second)
+	for range 2 {
+		select {
+		case <-ch:
+		case <-timeout:
+			t.Fatal("an hour-long gap was not shortened")
+		}
+	}
+	if !p.Now().Before(t0.Add(time.Hour + time.Minute)) {
+		t.Errorf("Now() = %v, want near the last event", p.Now())
+	}
+}
+
+func TestPlayer_Seek(t *testing.T) {
+	router := events.NewRouter(10)
+	defer router.Close()
+	ch := router.Subscribe()
+
+	p := New(recorded(), router, WithStartPaused())
+
+	p.Seek(90 * time.Second)
+	if got := collect(ch); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("forward seek emitted %v, want [a b]", got)
+	}
+	if !p.Now().Equal(t0.Add(90 * time.Second)) {
+		t.Errorf("Now() = %v, want 90s in", p.Now())
+	}
+
+	p.Seek(-time.Minute)
+	got := collect(ch)
+	if len(got) != 2 || got[0] != string(events.EventReplayRewind) || got[1] != "a" {
+		t.Errorf("backward seek emitted %v, want [replay.rewind a]", got)
+	}
+	if played, _ := p.Progress(); played != 1 {
+		t.Errorf("played = %d after rewind, want 1", played)
+	}
+
+	// Seeks are clamped to the log
+	p.Seek(time.Hour)
+	if !p.Now().Equal(t0.Add(2 * time.Minute)) {
+		t.Errorf("Now() = %v, want the last event", p.Now())
+	}
+	p.Seek(-2 * time.Hour)
+	if !p.Now().Equal(t0) {
+		t.Errorf("Now() = %v, want the first event", p.Now())
+	}
+	if !p.Paused() {
+		t.Error("seeking should not resume playback")
+	}
+}
+
+func TestPlayer_PauseFreezesClock(t *testing.T) {
+	router := events.NewRouter(10)
+	defer router.Close()
+
+	wall := time.Now()
+	p := New(recorded(), router, WithSpeed(2))
+	p.wall = func() time.Time { return wall }
+	p.baseWall = wall
+
+	wall = wall.Add(10 * time.Second)
+	if got := p.Now(); !got.Equal(t0.Add(20 * time.Second)) {
+		t.Errorf("Now() = %v, want 20s in at 2x", got)
+	}
+
+	p.TogglePause()
+	wall = wall.Add(time.Minute)
+	if got := p.Now(); !got.Equal(t0.Add(20 * time.Second)) {
+		t.Errorf("Now() = %v while paused, want it frozen at 20s", got)
+	}
+
+	p.TogglePause()
+	wall = wall.Add(5 * time.Second)
+	if got := p.Now(); !got.Equal(t0.Add(30 * time.Second)) {
+		t.Errorf("Now() = %v after resuming, want 30s", got)
+	}
+}
@@ -0,0 +1,289 @@
+// Package schedule decides when the drain may start work, from the recurring
+// windows in config.ScheduleConfig.
+package schedule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+// minutesPerDay is the end of a window that runs to midnight.
+const minutesPerDay = 24 * 60
+
+// horizon is how far ahead Status looks for window boundaries. Windows repeat
+// weekly, so a week and a day covers every case.
+const horizon = 8
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Schedule is a set of weekly windows in a time zone.
+type Schedule struct {
+	windows []window
+	loc     *time.Location
+}
+
+// window is one configured window. start and end are minutes after
+// midnight; end may pass midnight.
+type window struct {
+	name  string
+	days  [7]bool
+	start int
+	end   int
+}
+
+// Status describes the schedule at a point in time.
+type Status struct {
+	Open   bool      // a window is open
+	Window string    // the open window as configured, e.g. "mon-fri 19:00-07:00"
+	Next   time.Time // when the open window closes, or the next one opens (zero = never)
+}
+
+// Describe summarises the status relative to now, e.g. "in window sat,sun,
+// closes in 5h10m" or "outside schedule, opens in 42m".
+func (s Status) Describe(now time.Time) string {
+	var desc string
+	if s.Open {
+		desc = "in window " + s.Window
+	} else {
+		desc = "outside schedule"
+	}
+	if s.Next.IsZero() {
+		return desc
+	}
+	verb := "opens"
+	if s.Open {
+		verb = "closes"
+	}
+	return fmt.Sprintf("%s, %s in %s", desc, verb, formatUntil(s.Next.Sub(now)))
+}
+
+// formatUntil formats d to the minute, rounding up so a window never
+// appears to open "in 0m".
+func formatUntil(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
+
+// interval is one occurrence of a window.
+type interval struct {
+	start, end time.Time
+	name       string
+}
+
+// New parses the configured windows. It returns nil when no windows are
+// configured, meaning the drain may always work.
+func New(cfg config.ScheduleConfig) (*Schedule, error) {
+	if len(cfg.Windows) == 0 {
+		return nil, nil
+	}
+
+	loc := time.Local
+	if cfg.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("schedule.timezone: %w", err)
+		}
+	}
+
+	s := &Schedule{loc: loc}
+	for i, wc := range cfg.Windows {
+		w, err := parseWindow(wc)
+		if err != nil {
+			return nil, fmt.Errorf("schedule.windows[%d]: %w", i, err)
+		}
+		s.windows = append(s.windows, w)
+	}
+	return s, nil
+}
+
+// Status reports whether a window is open at t and when that changes.
+// Overlapping and back-to-back windows count as one.
+func (s *Schedule) Status(t time.Time) Status {
+	t = t.In(s.loc)
+	intervals := s.intervals(t)
+
+	var status Status
+	for _, iv := range intervals {
+		if !t.Before(iv.start) && t.Before(iv.end) {
+			status.Open = true
+			status.Window = iv.name
+			break
+		}
+	}
+
+	// Merge into continuous blocks to find the next change
+	var blocks []interval
+	for _, iv := range intervals {
+		if n := len(blocks); n > 0 && !iv.start.After(blocks[n-1].end) {
+			if iv.end.After(blocks[n-1].end) {
+				blocks[n-1].end = iv.end
+			}
+			continue
+		}
+		blocks = append(blocks, iv)
+	}
+
+	last := intervals[len(intervals)-1].end
+	for _, b := range blocks {
+		if status.Open && !t.Before(b.start) && t.Before(b.end) {
+			if b.end.Before(last) {
+				status.Next = b.end
+			}
+			return status
+		}
+		if !status.Open && b.start.After(t) {
+			status.Next = b.start
+			return status
+		}
+	}
+	return status
+}
+
+// intervals returns every occurrence of every window from the day before t
+// to the horizon, sorted by start.
+func (s *Schedule) intervals(t time.Time) []interval {
+	year, month, day := t.Date()
+	var out []interval
+	for d := -1; d <= horizon; d++ {
+		midnight := time.Date(year, month, day+d, 0, 0, 0, 0, s.loc)
+		for _, w := range s.windows {
+			if !w.days[midnight.Weekday()] {
+				continue
+			}
+			out = append(out, interval{
+				start: time.Date(year, month, day+d, 0, w.start, 0, 0, s.loc),
+				end:   time.Date(year, month, day+d, 0, w.end, 0, 0, s.loc),
+				name:  w.name,
+			})
+		}
+	}
+	slices.SortFunc(out, func(a, b interval) int { return a.start.Compare(b.start) })
+	return out
+}
+
+func parseWindow(wc config.ScheduleWindow) (window, error) {
+	w := window{end: minutesPerDay}
+
+	days, err := parseDays(wc.Days)
+	if err != nil {
+		return w, err
+	}
+	w.days = days
+
+	if wc.Start != "" {
+		if w.start, err = parseClock(wc.Start); err != nil || w.start == minutesPerDay {
+			return w, fmt.Errorf("invalid start %q: use HH:MM", wc.Start)
+		}
+	}
+	if wc.End != "" {
+		if w.end, err = parseClock(wc.End); err != nil {
+			return w, fmt.Errorf("invalid end %q: use HH:MM", wc.End)
+		}
+	}
+	if w.end <= w.start {
+		w.end += minutesPerDay
+	}
+
+	w.name = strings.TrimSpace(wc.Days)
+	if w.name == "" || w.name == "*" {
+		w.name = "daily"
+	}
+	if wc.Start != "" || wc.End != "" {
+		w.name += fmt.Sprintf(" %02d:%02d-%02d:%02d", w.start/60, w.start%60, (w.end/60)%24, w.end%60)
+	}
+	return w, nil
+}
+
+// parseDays parses a cron-style day-of-week field: a comma-separated list of
+// days or ranges, by name (mon) or number (0-7, where 0 and 7 are Sunday).
+// Empty or "*" means every day.
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "*" {
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	}
+
+	for item := range strings.SplitSeq(s, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(item), "-")
+		first, err := parseDay(from)
+		if err != nil {
+			return days, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseDay(to); err != nil {
+				return days, err
+			}
+		}
+		// Ranges may wrap around the week, e.g. fri-mon. Sunday is only
+		// folded to 0 afterwards, so 0-7 covers every day.
+		if last < first {
+			last += 7
+		}
+		for d := first; d <= last; d++ {
+			days[d%7] = true
+		}
+	}
+	return days, nil
+}
+
+// parseDay parses a day name or number. 7 is kept as Sunday at the end of
+// the week rather than folded to 0, so that ranges ending in 7 work.
+func parseDay(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if d, ok := dayNames[s]; ok {
+		return int(d), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 7 {
+		return 0, fmt.Errorf("invalid day %q: use sun-sat or 0-7", s)
+	}
+	return n, nil
+}
+
+// parseClock parses HH:MM into minutes after midnight. 24:00 is allowed.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("missing ':'")
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil {
+		return 0, err
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("out of range")
+	}
+	return h*60 + m, nil
+}
@@ -0,0 +1,179 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+// weeknightsAndWeekends is the usual quiet-hours schedule: weeknights
+// 19:00-07:00 and all weekend. Windows belong to the day they open on, so
+// Sunday night needs its own window.
+func weeknightsAndWeekends(t *testing.T) *Schedule {
+	t.Helper()
+	s, err := New(config.ScheduleConfig{
+		Timezone: "UTC",
+		Windows: []config.ScheduleWindow{
+			{Days: "sun-thu", Start: "19:00", End: "07:00"},
+			{Days: "fri", Start: "19:00"},
+			{Days: "sat,sun"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return s
+}
+
+// 2026-01-05 is a Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 1, 5+day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestStatus(t *testing.T) {
+	s := weeknightsAndWeekends(t)
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		open   bool
+		window string
+		next   time.Time
+	}{
+		{"monday afternoon", at(0, 14, 0), false, "", at(0, 19, 0)},
+		{"monday evening", at(0, 19, 0), true, "sun-thu 19:00-07:00", at(1, 7, 0)},
+		{"tuesday early morning", at(1, 6, 59), true, "sun-thu 19:00-07:00", at(1, 7, 0)},
+		{"tuesday morning", at(1, 7, 0), false, "", at(1, 19, 0)},
+		{"friday afternoon", at(4, 12, 0), false, "", at(4, 19, 0)},
+		// Friday night runs through the weekend and on to Monday morning
+		{"friday night", at(4, 23, 0), true, "fri 19:00-00:00", at(7, 7, 0)},
+		{"saturday", at(5, 12, 0), true, "sat,sun", at(7, 7, 0)},
+		{"monday early morning", at(7, 3, 0), true, "sun-thu 19:00-07:00", at(7, 7, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Status(tt.t)
+			if got.Open != tt.open || got.Window != tt.window || !got.Next.Equal(tt.next) {
+				t.Errorf("Status(%v) = %+v, want open=%v window=%q next=%v", tt.t, got, tt.open, tt.window, tt.next)
+			}
+		})
+	}
+}
+
+func TestStatus_AlwaysOpen(t *testing.T) {
+	s, err := New(config.ScheduleConfig{Windows: []config.ScheduleWindow{{Days: "*"}}})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	got := s.Status(time.Now())
+	if !got.Open || !got.Next.IsZero() {
+		t.Errorf("Status() = %+v, want open with no closing time", got)
+	}
+}
+
+func TestStatus_Timezone(t *testing.T) {
+	s, err := New(config.ScheduleConfig{
+		Timezone: "America/New_York",
+		Windows:  []config.ScheduleWindow{{Start: "09:00", End: "17:00"}},
+	})
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// 15:00 UTC is 10:00 in New York in January
+	if got := s.Status(at(0, 15, 0)); !got.Open || got.Window != "daily 09:00-17:00" {
+		t.Errorf("Status() = %+v, want open", got)
+	}
+	if got := s.Status(at(0, 23, 0)); got.Open {
+		t.Errorf("Status() = %+v, want closed", got)
+	}
+}
+
+func TestNew_NoWindows(t *testing.T) {
+	s, err := New(config.ScheduleConfig{})
+	if s != nil || err != nil {
+		t.Errorf("New() = %v, %v, want nil, nil", s, err)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.ScheduleConfig
+		want string
+	}{
+		{"bad day", config.ScheduleConfig{Windows: []config.ScheduleWindow{{Days: "funday"}}}, "invalid day"},
+		{"bad day number", config.ScheduleConfig{Windows: []config.ScheduleWindow{{Days: "8"}}}, "invalid day"},
+		{"bad start", config.ScheduleConfig{Windows: []config.ScheduleWindow{{Start: "7pm"}}}, "invalid start"},
+		{"bad end", config.ScheduleConfig{Windows: []config.ScheduleWindow{{End: "25:00"}}}, "invalid end"},
+		{"bad timezone", config.ScheduleConfig{Timezone: "Mars/Olympus", Windows: []config.ScheduleWindow{{}}}, "schedule.timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("New() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []time.Weekday
+	}{
+		{"mon-fri", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"sat,sun", []time.Weekday{time.Sunday, time.Saturday}},
+		{"1-5", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{"fri-mon", []time.Weekday{time.Sunday, time.Monday, time.Friday, time.Saturday}},
+		{"7", []time.Weekday{time.Sunday}},
+		{"0-7", []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}},
+		{"5-7", []time.Weekday{time.Sunday, time.Friday, time.Saturday}},
+		{"7-1", []time.Weekday{time.Sunday, time.Monday}},
+		{"Monday, Wed", []time.Weekday{time.Monday, time.Wednesday}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			days, err := parseDays(tt.in)
+			if err != nil {
+				t.Fatalf("parseDays(%q) error: %v", tt.in, err)
+			}
+			var got []time.Weekday
+			for d, ok := range days {
+				if ok {
+					got = append(got, time.Weekday(d))
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseDays(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("parseDays(%q) = %v, want %v", tt.in, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestStatus_Describe(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status Status
+		want   string
+	}{
+		{Status{Open: true, Window: "sat,sun", Next: now.Add(5*time.Hour + 10*time.Minute)}, "in window sat,sun, closes in 5h10m"},
+		{Status{Next: now.Add(42*time.Minute + 30*time.Second)}, "outside schedule, opens in 43m"},
+		{Status{Next: now.Add(2 * time.Hour)}, "outside schedule, opens in 2h"},
+		{Status{Next: now.Add(time.Second)}, "outside schedule, opens in 1m"},
+		{Status{Open: true, Window: "daily"}, "in window daily"},
+	}
+	for _, tt := range tests {
+		if got := tt.status.Describe(now); got != tt.want {
+			t.Errorf("Describe() = %q, want %q", got, tt.want)
+		}
+	}
+}
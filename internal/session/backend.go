@@ -0,0 +1,109 @@
+package session
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+)
+
+// Backend names for AgentConfig.Backend.
+const (
+	BackendClaude = "claude"
+	BackendJSONL  = "jsonl"
+)
+
+// AgentBackend adapts a coding agent CLI to atari: how a session is launched
+// and resumed, and how its output stream is decoded into events and cost.
+// The prompt is always written to the process's stdin.
+type AgentBackend interface {
+	// Name identifies the backend in logs and errors.
+	Name() string
+
+	// Command returns the program and arguments for a session.
+	Command(cfg *config.Config, opts LaunchOptions) (name string, args []string)
+
+	// SupportsResume reports whether sessions can be resumed by ID.
+	SupportsResume() bool
+
+	// NewDecoder returns a decoder for one session's output stream. The
+	// manager, if non-nil, is told about activity to reset its watchdog.
+	NewDecoder(r io.Reader, router *events.Router, manager *Manager) StreamDecoder
+}
+
+// LaunchOptions are the per-session settings passed to AgentBackend.Command.
+type LaunchOptions struct {
+	ResumeID string // session to resume; only set if the backend supports resume
+	Model    string // model to use (empty = the agent's default)
+}
+
+// StreamDecoder reads a session's output and emits events to the router.
+type StreamDecoder interface {
+	// Parse reads the stream until EOF or error.
+	Parse() error
+
+	// Result returns the session end event, or nil if none was seen.
+	Result() *events.SessionEndEvent
+
+	// EstimatedCost returns the running cost estimate in USD.
+	EstimatedCost() float64
+
+	// TurnCount returns the number of completed turns.
+	TurnCount() int
+
+	// SetOnTurnComplete sets a callback invoked at each turn boundary.
+	SetOnTurnComplete(fn func())
+
+	// SetOnCostUpdate sets a callback invoked when the cost estimate changes.
+	SetOnCostUpdate(fn func(float64))
+}
+
+// NewBackend returns the backend selected by cfg.Agent.Backend.
+func NewBackend(cfg *config.Config) (AgentBackend, error) {
+	switch cfg.Agent.Backend {
+	case "", BackendClaude:
+		return ClaudeBackend{}, nil
+	case BackendJSONL:
+		if len(cfg.Agent.Command) == 0 {
+			return nil, fmt.Errorf("agent backend %q requires agent.command", BackendJSONL)
+		}
+		return NewJSONLBackend(cfg.Agent), nil
+	default:
+		return nil, fmt.Errorf("unknown agent backend %q (want %q or %q)", cfg.Agent.Backend, BackendClaude, BackendJSONL)
+	}
+}
+
+// ClaudeBackend runs the Claude Code CLI with stream-json output.
+type ClaudeBackend struct{}
+
+// Name implements AgentBackend.
+func (ClaudeBackend) Name() string {
+	return BackendClaude
+}
+
+// Command implements AgentBackend.
+func (ClaudeBackend) Command(cfg *config.Config, opts LaunchOptions) (string, []string) {
+	args := []string{"-p", "--verbose", "--output-format", "stream-json"}
+	if cfg.Claude.MaxTurns > 0 {
+		args = append(args, "--max-turns", fmt.Sprintf("%d", cfg.Claude.MaxTurns))
+	}
+	if opts.ResumeID != "" {
+		args = append(args, "--resume", opts.ResumeID)
+	}
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+	args = append(args, cfg.Claude.ExtraArgs...)
+	return "claude", args
+}
+
+// SupportsResume implements AgentBackend.
+func (ClaudeBackend) SupportsResume() bool {
+	return true
+}
+
+// NewDecoder implements AgentBackend.
+func (ClaudeBackend) NewDecoder(r io.Reader, router *events.Router, manager *Manager) StreamDecoder {
+	return NewParser(r, router, manager)
+}
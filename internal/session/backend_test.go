@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+func TestNewBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		agent   config.AgentConfig
+		want    string
+		wantErr string
+	}{
+		{"default", config.AgentConfig{}, BackendClaude, ""},
+		{"claude", config.AgentConfig{Backend: "claude"}, BackendClaude, ""},
+		{"jsonl", config.AgentConfig{Backend: "jsonl", Command: []string{"agent"}}, BackendJSONL, ""},
+		{"jsonl without command", config.AgentConfig{Backend: "jsonl"}, "", "requires agent.command"},
+		{"unknown", config.AgentConfig{Backend: "codex"}, "", "unknown agent backend"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Agent = tt.agent
+			b, err := NewBackend(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NewBackend() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewBackend() error = %v", err)
+			}
+			if b.Name() != tt.want {
+				t.Errorf("Name() = %q, want %q", b.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestClaudeBackend_Command(t *testing.T) {
+	cfg := config.Default()
+	cfg.Claude.MaxTurns = 10
+	cfg.Claude.ExtraArgs = []string{"--model", "opus"}
+
+	name, args := ClaudeBackend{}.Command(cfg, LaunchOptions{ResumeID: "sess-1", Model: "sonnet"})
+	if name != "claude" {
+		t.Errorf("name = %q, want claude", name)
+	}
+	want := "[-p --verbose --output-format stream-json --max-turns 10 --resume sess-1 --model sonnet --model opus]"
+	if got := fmt.Sprint(args); got != want {
+		t.Errorf("args = %s, want %s", got, want)
+	}
+	if !(ClaudeBackend{}).SupportsResume() {
+		t.Error("expected claude backend to support resume")
+	}
+}
+
+func TestJSONLBackend_Command(t *testing.T) {
+	b := NewJSONLBackend(config.AgentConfig{Command: []string{"my-agent", "--json"}})
+	name, args := b.Command(config.Default(), LaunchOptions{Model: "sonnet"})
+	if name != "my-agent" || fmt.Sprint(args) != "[--json]" {
+		t.Errorf("Command() = %s %v", name, args)
+	}
+	if b.SupportsResume() {
+		t.Error("expected no resume without resume_flag")
+	}
+
+	b = NewJSONLBackend(config.AgentConfig{Command: []string{"my-agent"}, ResumeFlag: "--continue", ModelFlag: "--model"})
+	if !b.SupportsResume() {
+		t.Error("expected resume with resume_flag")
+	}
+	if _, args := b.Command(config.Default(), LaunchOptions{ResumeID: "abc", Model: "big"}); fmt.Sprint(args) != "[--continue abc --model big]" {
+		t.Errorf("resume args = %v", args)
+	}
+}
+
+func TestManager_BackendSkipsUnsupportedResume(t *testing.T) {
+	m := New(config.Default(), nil)
+	if m.Backend().Name() != BackendClaude {
+		t.Errorf("default backend = %q, want claude", m.Backend().Name())
+	}
+
+	m.SetBackend(NewJSONLBackend(config.AgentConfig{Command: []string{"cat"}}))
+	m.SetResumeID("sess-1")
+	if err := m.Start(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	_ = m.Wait()
+	if got := fmt.Sprint(m.cmd.Args); got != "[cat]" {
+		t.Errorf("args = %s, want [cat]", got)
+	}
+}
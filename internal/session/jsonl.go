@@ -0,0 +1,227 @@
+package session
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+)
+
+// JSONLBackend runs an arbitrary command that writes atari events as JSON
+// lines, so other coding agents or local scripts can be driven by the same
+// controller, TUI and event log.
+type JSONLBackend struct {
+	command    []string
+	resumeFlag string
+	modelFlag  string
+}
+
+// NewJSONLBackend creates a JSONLBackend from the agent settings.
+func NewJSONLBackend(cfg config.AgentConfig) JSONLBackend {
+	return JSONLBackend{
+		command:    cfg.Command,
+		resumeFlag: cfg.ResumeFlag,
+		modelFlag:  cfg.ModelFlag,
+	}
+}
+
+// Name implements AgentBackend.
+func (b JSONLBackend) Name() string {
+	return BackendJSONL
+}
+
+// Command implements AgentBackend.
+func (b JSONLBackend) Command(_ *config.Config, opts LaunchOptions) (string, []string) {
+	args := append([]string{}, b.command[1:]...)
+	if opts.ResumeID != "" && b.resumeFlag != "" {
+		args = append(args, b.resumeFlag, opts.ResumeID)
+	}
+	if opts.Model != "" && b.modelFlag != "" {
+		args = append(args, b.modelFlag, opts.Model)
+	}
+	return b.command[0], args
+}
+
+// SupportsResume implements AgentBackend. Resume needs a configured flag.
+func (b JSONLBackend) SupportsResume() bool {
+	return b.resumeFlag != ""
+}
+
+// NewDecoder implements AgentBackend.
+func (b JSONLBackend) NewDecoder(r io.Reader, router *events.Router, manager *Manager) StreamDecoder {
+	return NewJSONLParser(r, router, manager)
+}
+
+// JSONLParser decodes a stream of atari events, one JSON object per line in
+// the event log format (e.g. {"type":"claude.text","text":"..."}). Only the
+// event types an agent produces are accepted; lines that are not JSON are
+// emitted as text. Missing timestamps and sources are filled in.
+type JSONLParser struct {
+	scanner        *bufio.Scanner
+	router         *events.Router
+	manager        *Manager
+	result         atomic.Value // stores *events.SessionEndEvent
+	estimatedCost  atomic.Value // stores float64
+	turnNumber     int
+	onTurnComplete func()
+	onCostUpdate   func(float64)
+}
+
+// NewJSONLParser creates a JSONLParser for the given reader.
+func NewJSONLParser(r io.Reader, router *events.Router, manager *Manager) *JSONLParser {
+	scanner := bufio.NewScanner(r)
+	buf := make([]byte, 0, ScannerBufferSize)
+	scanner.Buffer(buf, ScannerBufferSize)
+
+	return &JSONLParser{
+		scanner: scanner,
+		router:  router,
+		manager: manager,
+	}
+}
+
+// Parse reads the stream and emits events until EOF or error.
+func (p *JSONLParser) Parse() error {
+	for p.scanner.Scan() {
+		line := p.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		if p.manager != nil {
+			p.manager.UpdateActivity()
+		}
+
+		if line[0] != '{' {
+			p.emit(&events.ClaudeTextEvent{
+				BaseEvent: events.NewClaudeEvent(events.EventClaudeText),
+				Text:      string(line),
+			})
+			continue
+		}
+
+		ev, err := events.ParseEvent(line)
+		if err == nil {
+			err = p.handle(ev)
+		}
+		if err != nil {
+			p.emit(&events.ParseErrorEvent{
+				BaseEvent: events.NewClaudeEvent(events.EventParseError),
+				Line:      string(line),
+				Error:     err.Error(),
+			})
+		}
+	}
+
+	if err := p.scanner.Err(); err != nil {
+		return fmt.Errorf("scanner error: %w", err)
+	}
+	return nil
+}
+
+// handle tracks turns, cost and the result, emits the event, then runs the
+// callbacks so that they observe the event already routed.
+func (p *JSONLParser) handle(ev events.Event) error {
+	switch e := ev.(type) {
+	case *events.ClaudeTextEvent:
+		fillBase(&e.BaseEvent)
+	case *events.ClaudeToolUseEvent:
+		fillBase(&e.BaseEvent)
+	case *events.ClaudeToolResultEvent:
+		fillBase(&e.BaseEvent)
+	case *events.ErrorEvent:
+		fillBase(&e.BaseEvent)
+	case *events.SessionCostEvent:
+		fillBase(&e.BaseEvent)
+		e.BeadID = p.beadID()
+		p.estimatedCost.Store(e.EstimatedCostUSD)
+	case *events.SessionEndEvent:
+		fillBase(&e.BaseEvent)
+		e.BeadID = p.beadID()
+		p.result.Store(e)
+	case *events.TurnCompleteEvent:
+		fillBase(&e.BaseEvent)
+		p.turnNumber++
+		if e.TurnNumber == 0 {
+			e.TurnNumber = p.turnNumber
+		}
+	case nil:
+		return fmt.Errorf("unknown event type")
+	default:
+		return fmt.Errorf("event type %q cannot be sent by an agent", ev.Type())
+	}
+
+	p.emit(ev)
+
+	switch e := ev.(type) {
+	case *events.SessionCostEvent:
+		if p.onCostUpdate != nil {
+			p.onCostUpdate(e.EstimatedCostUSD)
+		}
+	case *events.TurnCompleteEvent:
+		if p.onTurnComplete != nil {
+			p.onTurnComplete()
+		}
+	}
+	return nil
+}
+
+// beadID returns the bead the session works on, if known.
+func (p *JSONLParser) beadID() string {
+	if p.manager == nil {
+		return ""
+	}
+	return p.manager.BeadID()
+}
+
+// emit sends an event to the router, if any.
+func (p *JSONLParser) emit(ev events.Event) {
+	if p.router != nil {
+		p.router.Emit(ev)
+	}
+}
+
+// fillBase sets the timestamp and source for events that omit them.
+func fillBase(b *events.BaseEvent) {
+	if b.Time.IsZero() {
+		b.Time = time.Now()
+	}
+	if b.Src == "" {
+		b.Src = events.SourceClaude
+	}
+}
+
+// Result returns the session end event, or nil if none was seen.
+func (p *JSONLParser) Result() *events.SessionEndEvent {
+	if v := p.result.Load(); v != nil {
+		return v.(*events.SessionEndEvent)
+	}
+	return nil
+}
+
+// EstimatedCost returns the cost from the latest session.cost event.
+func (p *JSONLParser) EstimatedCost() float64 {
+	if v := p.estimatedCost.Load(); v != nil {
+		return v.(float64)
+	}
+	return 0
+}
+
+// TurnCount returns the number of turn.complete events seen.
+func (p *JSONLParser) TurnCount() int {
+	return p.turnNumber
+}
+
+// SetOnTurnComplete sets a callback invoked after each turn.complete event.
+func (p *JSONLParser) SetOnTurnComplete(fn func()) {
+	p.onTurnComplete = fn
+}
+
+// SetOnCostUpdate sets a callback invoked after each session.cost event.
+func (p *JSONLParser) SetOnCostUpdate(fn func(float64)) {
+	p.onCostUpdate = fn
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/events"
+)
+
+func TestJSONLParser(t *testing.T) {
+	input := strings.Join([]string{
+		"plain output",
+		`{"type":"claude.tool_use","tool_id":"t1","tool_name":"Bash","input":{"command":"ls"}}`,
+		`{"type":"claude.tool_result","tool_id":"t1","content":"ok"}`,
+		`{"type":"turn.complete","tool_count":1}`,
+		`{"type":"session.cost","estimated_cost_usd":0.25}`,
+		`{"type":"iteration.end","bead_id":"bd-1"}`,
+		`{"type":"not.a.type"}`,
+		`{"type":"session.end","session_id":"s1","num_turns":1,"total_cost_usd":0.3}`,
+	}, "\n")
+
+	router := events.NewRouter(100)
+	sub := router.Subscribe()
+	parser := NewJSONLParser(strings.NewReader(input), router, nil)
+
+	var costs []float64
+	turns := 0
+	parser.SetOnCostUpdate(func(c float64) { costs = append(costs, c) })
+	parser.SetOnTurnComplete(func() { turns++ })
+
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	router.Close()
+	collected := collectEvents(sub, 100*time.Millisecond)
+
+	var types []events.EventType
+	for _, ev := range collected {
+		types = append(types, ev.Type())
+	}
+	want := []events.EventType{
+		events.EventClaudeText,
+		events.EventClaudeToolUse,
+		events.EventClaudeToolResult,
+		events.EventTurnComplete,
+		events.EventSessionCost,
+		events.EventParseError, // iteration.end is controller-owned
+		events.EventParseError, // unknown type
+		events.EventSessionEnd,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, types[i], want[i])
+		}
+	}
+
+	// Missing timestamp and source are filled in
+	toolUse := collected[1].(*events.ClaudeToolUseEvent)
+	if toolUse.Timestamp().IsZero() || toolUse.Source() != events.SourceClaude {
+		t.Errorf("expected defaults filled in, got %+v", toolUse.BaseEvent)
+	}
+	if turn := collected[3].(*events.TurnCompleteEvent); turn.TurnNumber != 1 {
+		t.Errorf("TurnNumber = %d, want 1", turn.TurnNumber)
+	}
+
+	if turns != 1 || parser.TurnCount() != 1 {
+		t.Errorf("turns = %d, TurnCount() = %d, want 1", turns, parser.TurnCount())
+	}
+	if len(costs) != 1 || parser.EstimatedCost() != 0.25 {
+		t.Errorf("costs = %v, EstimatedCost() = %v", costs, parser.EstimatedCost())
+	}
+	if result := parser.Result(); result == nil || result.SessionID != "s1" || result.TotalCostUSD != 0.3 {
+		t.Errorf("Result() = %+v", result)
+	}
+}
@@ -78,19 +78,28 @@ type Manager struct {
 	stderr         *LimitedWriter
 	lastActive     atomic.Value // time.Time
 	pauseRequested atomic.Bool  // graceful pause requested
+	timedOut       atomic.Bool  // stopped by the inactivity watchdog
 	done           chan struct{}
 	mu             sync.Mutex
 	started        bool
-	resumeID       string // Claude session ID for --resume flag (optional)
+	resumeID       string       // Claude session ID for --resume flag (optional)
+	workDir        string       // Working directory for the claude process (optional)
+	beadID         string       // bead the session works on, stamped on its cost and end events (optional)
+	backend        AgentBackend // agent CLI to run (default: ClaudeBackend)
+	model          string       // model to run the session with (optional)
+	transcriptOut  io.Writer    // receives a copy of stdout (optional)
+	transcriptErr  io.Writer    // receives a copy of stderr (optional)
+	output         io.Reader    // stdout, teed to transcriptOut when set
 }
 
 // New creates a Manager with the given config and event router.
 func New(cfg *config.Config, router *events.Router) *Manager {
 	m := &Manager{
-		config: cfg,
-		events: router,
-		stderr: NewLimitedWriter(DefaultStderrCap),
-		done:   make(chan struct{}),
+		config:  cfg,
+		events:  router,
+		stderr:  NewLimitedWriter(DefaultStderrCap),
+		done:    make(chan struct{}),
+		backend: ClaudeBackend{},
 	}
 	m.lastActive.Store(time.Now())
 	return m
@@ -103,10 +112,56 @@ func (m *Manager) SetResumeID(sessionID string) {
 	m.resumeID = sessionID
 }
 
-// Start spawns the claude process with stream-json output format.
-// The prompt is provided via stdin. Stdin remains open for prompt injection.
-// If SetResumeID was called with a valid session ID, --resume is passed
-// to restore context from a previous session.
+// SetWorkDir sets the directory the claude process runs in.
+// Pass empty string to use the current working directory.
+func (m *Manager) SetWorkDir(dir string) {
+	m.workDir = dir
+}
+
+// SetBeadID sets the bead the session works on. Decoders stamp it on the
+// session's cost and end events so they can be told apart when several
+// sessions run at once.
+func (m *Manager) SetBeadID(id string) {
+	m.beadID = id
+}
+
+// BeadID returns the bead set by SetBeadID.
+func (m *Manager) BeadID() string {
+	return m.beadID
+}
+
+// SetModel sets the model for the session.
+// Pass empty string to use the agent's default model.
+func (m *Manager) SetModel(model string) {
+	m.model = model
+}
+
+// SetTranscript sets writers that receive a copy of the process's stdout
+// and stderr. Writes to them must not fail, or parsing the session stops.
+// Pass nil writers to disable.
+func (m *Manager) SetTranscript(stdout, stderr io.Writer) {
+	m.transcriptOut = stdout
+	m.transcriptErr = stderr
+}
+
+// SetBackend sets the agent backend that builds the command line.
+// Pass nil to use the Claude CLI.
+func (m *Manager) SetBackend(b AgentBackend) {
+	if b == nil {
+		b = ClaudeBackend{}
+	}
+	m.backend = b
+}
+
+// Backend returns the agent backend for this session.
+func (m *Manager) Backend() AgentBackend {
+	return m.backend
+}
+
+// Start spawns the agent process, by default claude with stream-json output.
+// The prompt is provided via stdin, which is closed once it is written.
+// If SetResumeID was called with a session ID and the backend supports
+// resume, it is passed on to restore context from a previous session.
 func (m *Manager) Start(ctx context.Context, prompt string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -115,16 +170,14 @@ func (m *Manager) Start(ctx context.Context, prompt string) error {
 		return fmt.Errorf("session already started")
 	}
 
-	args := []string{"-p", "--verbose", "--output-format", "stream-json"}
-	if m.config.Claude.MaxTurns > 0 {
-		args = append(args, "--max-turns", fmt.Sprintf("%d", m.config.Claude.MaxTurns))
+	opts := LaunchOptions{Model: m.model}
+	if m.backend.SupportsResume() {
+		opts.ResumeID = m.resumeID
 	}
-	if m.resumeID != "" {
-		args = append(args, "--resume", m.resumeID)
-	}
-	args = append(args, m.config.Claude.ExtraArgs...)
+	name, args := m.backend.Command(m.config, opts)
 
-	m.cmd = exec.CommandContext(ctx, "claude", args...)
+	m.cmd = exec.CommandContext(ctx, name, args...)
+	m.cmd.Dir = m.workDir
 
 	// Use pipe for stdin to allow prompt injection
 	var err error
@@ -137,10 +190,17 @@ func (m *Manager) Start(ctx context.Context, prompt string) error {
 	if err != nil {
 		return fmt.Errorf("stdout pipe: %w", err)
 	}
+	m.output = m.stdout
+	if m.transcriptOut != nil {
+		m.output = io.TeeReader(m.stdout, m.transcriptOut)
+	}
 	m.cmd.Stderr = m.stderr
+	if m.transcriptErr != nil {
+		m.cmd.Stderr = io.MultiWriter(m.stderr, m.transcriptErr)
+	}
 
 	if err := m.cmd.Start(); err != nil {
-		return fmt.Errorf("start claude: %w", err)
+		return fmt.Errorf("start %s: %w", m.backend.Name(), err)
 	}
 
 	// Write initial prompt to stdin and close to signal EOF.
@@ -177,6 +237,7 @@ func (m *Manager) watchdog(ctx context.Context) {
 					Duration:  time.Since(last),
 				})
 			}
+			m.timedOut.Store(true)
 			m.Stop()
 			return true
 		}
@@ -211,7 +272,12 @@ func (m *Manager) UpdateActivity() {
 // Stdout returns the stdout reader for the claude process.
 // Callers should read from this to process stream-json events.
 func (m *Manager) Stdout() io.Reader {
-	return m.stdout
+	return m.output
+}
+
+// TimedOut reports whether the session was stopped by the inactivity watchdog.
+func (m *Manager) TimedOut() bool {
+	return m.timedOut.Load()
 }
 
 // Stderr returns the captured stderr content.
@@ -221,6 +287,7 @@ func (m *Manager) Stderr() string {
 
 // Wait blocks until the claude process exits and returns its error.
 // It closes the done channel to signal the watchdog to stop.
+// Finish reading Stdout first: Wait closes it once the process exits.
 func (m *Manager) Wait() error {
 	defer func() {
 		select {
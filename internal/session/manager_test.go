@@ -277,6 +277,25 @@ func TestManager_WatchdogEmitsTimeout(t *testing.T) {
 	}
 }
 
+// Test that a watchdog timeout is recorded
+func TestManager_TimedOut(t *testing.T) {
+	cfg := config.Default()
+	cfg.Claude.Timeout = 50 * time.Millisecond
+
+	m := New(cfg, nil)
+	if m.TimedOut() {
+		t.Error("expected TimedOut() false before the watchdog fires")
+	}
+
+	// Returns once the timeout is detected
+	m.lastActive.Store(time.Now().Add(-time.Hour))
+	m.watchdog(context.Background())
+
+	if !m.TimedOut() {
+		t.Error("expected TimedOut() true after the watchdog fires")
+	}
+}
+
 // Test that watchdog respects context cancellation
 func TestManager_WatchdogRespectsContext(t *testing.T) {
 	cfg := config.Default()
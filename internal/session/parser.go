@@ -23,12 +23,13 @@ type StreamEvent struct {
 	// For assistant events
 	Message *StreamMessage `json:"message,omitempty"`
 
-	// For result events
+	// For result events (also present on assistant and system events)
 	SessionID    string  `json:"session_id,omitempty"`
 	NumTurns     int     `json:"num_turns,omitempty"`
 	DurationMs   int64   `json:"duration_ms,omitempty"`
 	TotalCostUSD float64 `json:"total_cost_usd,omitempty"`
 	Result       string  `json:"result,omitempty"`
+	IsError      bool    `json:"is_error,omitempty"`
 
 	// For system init events
 	Model string   `json:"model,omitempty"`
@@ -37,7 +38,10 @@ type StreamEvent struct {
 
 // StreamMessage is the message field in assistant/user events.
 type StreamMessage struct {
+	ID      string            `json:"id,omitempty"`
+	Model   string            `json:"model,omitempty"`
 	Content []json.RawMessage `json:"content"`
+	Usage   *Usage            `json:"usage,omitempty"`
 }
 
 // ContentBlock represents a content item within a message.
@@ -72,6 +76,14 @@ type Parser struct {
 	turnNumber      int          // current turn number (1-indexed)
 	turnToolCount   int          // tools used in current turn
 	turnStartTimeMs int64        // when current turn started (unix ms)
+
+	// Live cost estimation from per-message usage
+	model         string           // model reported by system init
+	sessionID     string           // session ID reported by Claude
+	messageUsage  map[string]Usage // last usage seen per message ID
+	usage         Usage            // usage summed across messages
+	estimatedCost atomic.Value     // stores float64
+	onCostUpdate  func(float64)    // callback when the estimate changes
 }
 
 // NewParser creates a Parser for the given reader.
@@ -82,9 +94,10 @@ func NewParser(r io.Reader, router *events.Router, manager *Manager) *Parser {
 	scanner.Buffer(buf, ScannerBufferSize)
 
 	return &Parser{
-		scanner: scanner,
-		router:  router,
-		manager: manager,
+		scanner:      scanner,
+		router:       router,
+		manager:      manager,
+		messageUsage: make(map[string]Usage),
 	}
 }
 
@@ -154,9 +167,10 @@ func (p *Parser) convertAndEmit(e *StreamEvent) {
 func (p *Parser) handleSystemEvent(e *StreamEvent) {
 	switch e.Subtype {
 	case "init":
-		// System init events contain model and tools info
-		// Currently we don't have a dedicated event type for this
-		// Could emit a SessionStartEvent but that's for bead context
+		// System init events contain model and tools info.
+		// The model prices the live cost estimate.
+		p.model = e.Model
+		p.sessionID = e.SessionID
 	case "compact_boundary":
 		// Context compaction event - could be useful for logging
 	default:
@@ -170,6 +184,10 @@ func (p *Parser) handleAssistantEvent(e *StreamEvent) {
 		return
 	}
 
+	if e.Message.Usage != nil {
+		p.updateCost(e, e.Message)
+	}
+
 	for _, rawContent := range e.Message.Content {
 		var block ContentBlock
 		if err := json.Unmarshal(rawContent, &block); err != nil {
@@ -206,6 +224,54 @@ func (p *Parser) handleAssistantEvent(e *StreamEvent) {
 	}
 }
 
+// updateCost folds a message's token usage into the running estimate and
+// emits a SessionCostEvent. Claude repeats a message's usage on every content
+// block it streams, so usage is tracked per message ID and replaced rather
+// than added.
+func (p *Parser) updateCost(e *StreamEvent, msg *StreamMessage) {
+	if e.SessionID != "" {
+		p.sessionID = e.SessionID
+	}
+	if msg.Model != "" {
+		p.model = msg.Model
+	}
+
+	if msg.ID != "" {
+		prev, seen := p.messageUsage[msg.ID]
+		if seen && prev == *msg.Usage {
+			return
+		}
+		p.messageUsage[msg.ID] = *msg.Usage
+		p.usage.InputTokens -= prev.InputTokens
+		p.usage.OutputTokens -= prev.OutputTokens
+		p.usage.CacheCreationInputTokens -= prev.CacheCreationInputTokens
+		p.usage.CacheReadInputTokens -= prev.CacheReadInputTokens
+	}
+	p.usage.InputTokens += msg.Usage.InputTokens
+	p.usage.OutputTokens += msg.Usage.OutputTokens
+	p.usage.CacheCreationInputTokens += msg.Usage.CacheCreationInputTokens
+	p.usage.CacheReadInputTokens += msg.Usage.CacheReadInputTokens
+
+	cost := EstimateCost(p.model, p.usage)
+	p.estimatedCost.Store(cost)
+
+	p.router.Emit(&events.SessionCostEvent{
+		BaseEvent:           events.NewClaudeEvent(events.EventSessionCost),
+		SessionID:           p.sessionID,
+		BeadID:              p.beadID(),
+		Model:               p.model,
+		InputTokens:         p.usage.InputTokens,
+		OutputTokens:        p.usage.OutputTokens,
+		CacheCreationTokens: p.usage.CacheCreationInputTokens,
+		CacheReadTokens:     p.usage.CacheReadInputTokens,
+		EstimatedCostUSD:    cost,
+	})
+
+	if p.onCostUpdate != nil {
+		p.onCostUpdate(cost)
+	}
+}
+
 // handleUserEvent processes user message events (tool results).
 func (p *Parser) handleUserEvent(e *StreamEvent) {
 	if e.Message == nil {
@@ -261,16 +327,27 @@ func (p *Parser) handleResultEvent(e *StreamEvent) {
 	endEvent := &events.SessionEndEvent{
 		BaseEvent:    events.NewClaudeEvent(events.EventSessionEnd),
 		SessionID:    e.SessionID,
+		BeadID:       p.beadID(),
 		NumTurns:     e.NumTurns,
 		DurationMs:   e.DurationMs,
 		TotalCostUSD: e.TotalCostUSD,
 		Result:       e.Result,
+		Subtype:      e.Subtype,
+		IsError:      e.IsError,
 	}
 	// Capture result for later retrieval (thread-safe)
 	p.result.Store(endEvent)
 	p.router.Emit(endEvent)
 }
 
+// beadID returns the bead the session works on, if known.
+func (p *Parser) beadID() string {
+	if p.manager == nil {
+		return ""
+	}
+	return p.manager.BeadID()
+}
+
 // Result returns the captured session end event, or nil if no result was parsed.
 // This is safe to call from any goroutine after Parse() completes.
 func (p *Parser) Result() *events.SessionEndEvent {
@@ -286,6 +363,22 @@ func (p *Parser) SetOnTurnComplete(fn func()) {
 	p.onTurnComplete = fn
 }
 
+// SetOnCostUpdate sets a callback that's invoked with the new estimated cost
+// each time an assistant message reports token usage.
+func (p *Parser) SetOnCostUpdate(fn func(float64)) {
+	p.onCostUpdate = fn
+}
+
+// EstimatedCost returns the session's running cost estimate in USD, computed
+// from the token usage seen so far. It is 0 until usage has been reported and
+// is safe to call from any goroutine.
+func (p *Parser) EstimatedCost() float64 {
+	if v := p.estimatedCost.Load(); v != nil {
+		return v.(float64)
+	}
+	return 0
+}
+
 // TurnCount returns the number of completed turns in the current session.
 // A turn is completed when all tool_use blocks have received their tool_result blocks.
 func (p *Parser) TurnCount() int {
@@ -254,6 +254,28 @@ func TestParser_ResultCapture(t *testing.T) {
 	}
 }
 
+func TestParser_ErrorResult(t *testing.T) {
+	input := `{"type":"result","subtype":"error_during_execution","is_error":true,"session_id":"abc123","num_turns":1,"result":"API Error: 429 rate limit exceeded"}`
+	router := events.NewRouter(100)
+	defer router.Close()
+
+	parser := NewParser(strings.NewReader(input), router, nil)
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := parser.Result()
+	if result == nil {
+		t.Fatal("expected Result() to be non-nil after parsing")
+	}
+	if result.Subtype != "error_during_execution" {
+		t.Errorf("expected subtype error_during_execution, got %q", result.Subtype)
+	}
+	if !result.IsError {
+		t.Error("expected IsError to be set")
+	}
+}
+
 func TestParser_ResultNilWithoutResultEvent(t *testing.T) {
 	// No result event in stream
 	input := `{"type":"assistant","message":{"content":[{"type":"text","text":"Hello"}]}}`
@@ -732,3 +754,125 @@ func TestParser_ActivityUpdateCount(t *testing.T) {
 		t.Error("expected at least one activity update")
 	}
 }
+
+func TestParser_StampsBeadID(t *testing.T) {
+	input := `{"type":"assistant","session_id":"sess-1","message":{"id":"msg_1","content":[{"type":"text","text":"a"}],"usage":{"input_tokens":10,"output_tokens":10}}}
+{"type":"result","subtype":"success","session_id":"sess-1","num_turns":1,"total_cost_usd":0.01}`
+	router := events.NewRouter(100)
+	defer router.Close()
+
+	sub := router.Subscribe()
+	manager := New(config.Default(), router)
+	manager.SetBeadID("bd-001")
+	parser := NewParser(strings.NewReader(input), router, manager)
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	router.Close()
+	var stamped int
+	for _, e := range collectEvents(sub, 100*time.Millisecond) {
+		switch ev := e.(type) {
+		case *events.SessionCostEvent:
+			if ev.BeadID != "bd-001" {
+				t.Errorf("cost event bead ID = %q, want bd-001", ev.BeadID)
+			}
+			stamped++
+		case *events.SessionEndEvent:
+			if ev.BeadID != "bd-001" {
+				t.Errorf("end event bead ID = %q, want bd-001", ev.BeadID)
+			}
+			stamped++
+		}
+	}
+	if stamped != 2 {
+		t.Errorf("expected a cost and an end event, got %d", stamped)
+	}
+}
+
+func TestParser_SessionCostEvent(t *testing.T) {
+	// Two content blocks of the same message repeat its usage, then a second
+	// message adds more. The repeat must not be double counted.
+	input := `{"type":"system","subtype":"init","model":"claude-sonnet-4-5","session_id":"sess-1"}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text","text":"a"}],"usage":{"input_tokens":1000,"output_tokens":100}}}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text","text":"b"}],"usage":{"input_tokens":1000,"output_tokens":100}}}
+{"type":"assistant","message":{"id":"msg_2","content":[{"type":"text","text":"c"}],"usage":{"input_tokens":10,"output_tokens":200,"cache_read_input_tokens":1000}}}`
+	router := events.NewRouter(100)
+	defer router.Close()
+
+	sub := router.Subscribe()
+	parser := NewParser(strings.NewReader(input), router, nil)
+
+	var updates []float64
+	parser.SetOnCostUpdate(func(cost float64) {
+		updates = append(updates, cost)
+	})
+
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	router.Close()
+	collected := collectEvents(sub, 100*time.Millisecond)
+
+	var costEvents []*events.SessionCostEvent
+	for _, e := range collected {
+		if ce, ok := e.(*events.SessionCostEvent); ok {
+			costEvents = append(costEvents, ce)
+		}
+	}
+	if len(costEvents) != 2 {
+		t.Fatalf("expected 2 cost events, got %d", len(costEvents))
+	}
+	if len(updates) != 2 {
+		t.Errorf("expected 2 cost callbacks, got %d", len(updates))
+	}
+
+	last := costEvents[1]
+	if last.SessionID != "sess-1" || last.Model != "claude-sonnet-4-5" {
+		t.Errorf("unexpected session/model: %q/%q", last.SessionID, last.Model)
+	}
+	if last.InputTokens != 1010 || last.OutputTokens != 300 || last.CacheReadTokens != 1000 {
+		t.Errorf("unexpected token totals: %+v", last)
+	}
+
+	want := EstimateCost("claude-sonnet-4-5", Usage{InputTokens: 1010, OutputTokens: 300, CacheReadInputTokens: 1000})
+	if last.EstimatedCostUSD != want {
+		t.Errorf("EstimatedCostUSD = %v, want %v", last.EstimatedCostUSD, want)
+	}
+	if parser.EstimatedCost() != want {
+		t.Errorf("EstimatedCost() = %v, want %v", parser.EstimatedCost(), want)
+	}
+}
+
+func TestParser_SessionCostUpdatedUsage(t *testing.T) {
+	// A later event for the same message with higher output replaces the earlier usage
+	input := `{"type":"assistant","message":{"id":"msg_1","model":"claude-opus-4-1","content":[],"usage":{"input_tokens":100,"output_tokens":1}}}
+{"type":"assistant","message":{"id":"msg_1","model":"claude-opus-4-1","content":[],"usage":{"input_tokens":100,"output_tokens":500}}}`
+	router := events.NewRouter(100)
+	defer router.Close()
+
+	parser := NewParser(strings.NewReader(input), router, nil)
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := EstimateCost("claude-opus-4-1", Usage{InputTokens: 100, OutputTokens: 500})
+	if got := parser.EstimatedCost(); got != want {
+		t.Errorf("EstimatedCost() = %v, want %v", got, want)
+	}
+}
+
+func TestParser_EstimatedCostWithoutUsage(t *testing.T) {
+	input := `{"type":"assistant","message":{"content":[{"type":"text","text":"Hello"}]}}`
+	router := events.NewRouter(100)
+	defer router.Close()
+
+	parser := NewParser(strings.NewReader(input), router, nil)
+	if err := parser.Parse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := parser.EstimatedCost(); got != 0 {
+		t.Errorf("EstimatedCost() = %v, want 0", got)
+	}
+}
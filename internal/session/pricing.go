@@ -0,0 +1,68 @@
+package session
+
+import "strings"
+
+// ModelPrice holds per-million-token prices in USD for a model family.
+type ModelPrice struct {
+	Input      float64
+	Output     float64
+	CacheWrite float64
+	CacheRead  float64
+}
+
+// modelPrice builds a ModelPrice with the standard cache multipliers:
+// cache writes cost 1.25x input and cache reads 0.1x input.
+func modelPrice(input, output float64) ModelPrice {
+	return ModelPrice{
+		Input:      input,
+		Output:     output,
+		CacheWrite: input * 1.25,
+		CacheRead:  input * 0.1,
+	}
+}
+
+// priceTable maps model name fragments to prices. Entries are checked in
+// order, so more specific fragments must come before general ones.
+var priceTable = []struct {
+	match string
+	price ModelPrice
+}{
+	{"opus-4-5", modelPrice(5, 25)},
+	{"opus-4-6", modelPrice(5, 25)},
+	{"opus", modelPrice(15, 75)},
+	{"sonnet", modelPrice(3, 15)},
+	{"haiku-4", modelPrice(1, 5)},
+	{"haiku", modelPrice(0.8, 4)},
+}
+
+// defaultPrice is used for unknown or unreported models.
+var defaultPrice = modelPrice(3, 15)
+
+// Usage holds the token counts reported for one assistant message.
+type Usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// PriceFor returns the prices for a model name such as "claude-sonnet-4-5".
+// Unknown models are priced as Sonnet.
+func PriceFor(model string) ModelPrice {
+	model = strings.ToLower(model)
+	for _, entry := range priceTable {
+		if strings.Contains(model, entry.match) {
+			return entry.price
+		}
+	}
+	return defaultPrice
+}
+
+// EstimateCost returns the estimated cost in USD of the given token usage.
+func EstimateCost(model string, u Usage) float64 {
+	p := PriceFor(model)
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationInputTokens)*p.CacheWrite +
+		float64(u.CacheReadInputTokens)*p.CacheRead) / 1_000_000
+}
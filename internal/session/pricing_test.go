@@ -0,0 +1,45 @@
+package session
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPriceFor(t *testing.T) {
+	tests := []struct {
+		model     string
+		wantInput float64
+	}{
+		{"claude-opus-4-5-20251101", 5},
+		{"claude-opus-4-1-20250805", 15},
+		{"claude-sonnet-4-5-20250929", 3},
+		{"claude-haiku-4-5-20251001", 1},
+		{"claude-3-5-haiku-20241022", 0.8},
+		{"", 3},
+		{"some-future-model", 3},
+	}
+	for _, tt := range tests {
+		if got := PriceFor(tt.model).Input; got != tt.wantInput {
+			t.Errorf("PriceFor(%q).Input = %v, want %v", tt.model, got, tt.wantInput)
+		}
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	u := Usage{
+		InputTokens:              1_000_000,
+		OutputTokens:             100_000,
+		CacheCreationInputTokens: 200_000,
+		CacheReadInputTokens:     1_000_000,
+	}
+
+	// Sonnet: $3 input + $1.50 output + $0.75 cache write + $0.30 cache read
+	got := EstimateCost("claude-sonnet-4-5", u)
+	if math.Abs(got-5.55) > 1e-9 {
+		t.Errorf("EstimateCost() = %v, want 5.55", got)
+	}
+
+	if got := EstimateCost("claude-sonnet-4-5", Usage{}); got != 0 {
+		t.Errorf("EstimateCost(empty) = %v, want 0", got)
+	}
+}
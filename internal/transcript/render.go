@@ -0,0 +1,192 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/npratt/atari/internal/session"
+)
+
+// maxLineSize bounds a single transcript line when reading.
+const maxLineSize = 16 * 1024 * 1024
+
+// streamLine is the subset of a Claude stream-json line that Render shows.
+type streamLine struct {
+	Type         string  `json:"type"`
+	Subtype      string  `json:"subtype"`
+	Model        string  `json:"model"`
+	SessionID    string  `json:"session_id"`
+	NumTurns     int     `json:"num_turns"`
+	DurationMs   int64   `json:"duration_ms"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Result       string  `json:"result"`
+	Message      *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// contentBlock is one block of an assistant or user message. Unlike
+// session.ContentBlock, tool result content may be a string or a list of
+// text blocks.
+type contentBlock struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text"`
+	Thinking string          `json:"thinking"`
+	Name     string          `json:"name"`
+	Input    json.RawMessage `json:"input"`
+	Content  json.RawMessage `json:"content"`
+	IsError  bool            `json:"is_error"`
+}
+
+// Render writes a transcript as readable text in the style of the events
+// pane, with tool inputs and outputs shown in full. Lines from an agent
+// other than Claude are shown as they were recorded.
+func Render(w io.Writer, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	backend := session.BackendClaude
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(line, &head); err != nil {
+			fmt.Fprintf(w, "%s\n", line)
+			continue
+		}
+
+		switch head.Type {
+		case LineSession:
+			var s SessionLine
+			_ = json.Unmarshal(line, &s)
+			if s.Backend != "" {
+				backend = s.Backend
+			}
+			renderSession(w, &s)
+		case LineStderr:
+			var s StderrLine
+			_ = json.Unmarshal(line, &s)
+			fmt.Fprintf(w, "[%s] stderr: %s\n", s.Time.Local().Format("15:04:05"), s.Text)
+		default:
+			if backend != session.BackendClaude {
+				fmt.Fprintf(w, "%s\n", line)
+				continue
+			}
+			var s streamLine
+			if err := json.Unmarshal(line, &s); err != nil {
+				fmt.Fprintf(w, "%s\n", line)
+				continue
+			}
+			renderStream(w, &s)
+		}
+	}
+	return scanner.Err()
+}
+
+func renderSession(w io.Writer, s *SessionLine) {
+	fmt.Fprintf(w, "=== [%s] session: %s attempt %d", s.Time.Local().Format("2006-01-02 15:04:05"), s.BeadID, s.Attempt)
+	if s.Title != "" {
+		fmt.Fprintf(w, " - %s", s.Title)
+	}
+	fmt.Fprintln(w, " ===")
+	var details []string
+	if s.Model != "" {
+		details = append(details, "model: "+s.Model)
+	}
+	if s.PromptName != "" {
+		details = append(details, "prompt: "+s.PromptName)
+	}
+	if len(details) > 0 {
+		fmt.Fprintln(w, strings.Join(details, ", "))
+	}
+	if s.Prompt != "" {
+		fmt.Fprintln(w, "prompt:")
+		writeIndented(w, s.Prompt)
+	}
+}
+
+func renderStream(w io.Writer, s *streamLine) {
+	switch s.Type {
+	case "system":
+		if s.Subtype == "init" {
+			fmt.Fprintf(w, "session init: %s (model %s)\n", s.SessionID, s.Model)
+		}
+	case "assistant", "user":
+		if s.Message == nil {
+			return
+		}
+		var blocks []contentBlock
+		if err := json.Unmarshal(s.Message.Content, &blocks); err != nil {
+			return
+		}
+		for i := range blocks {
+			renderBlock(w, &blocks[i])
+		}
+	case "result":
+		fmt.Fprintf(w, "session ended: %d turns, %s, $%.4f\n", s.NumTurns,
+			(time.Duration(s.DurationMs) * time.Millisecond).Round(time.Second), s.TotalCostUSD)
+		if s.Result != "" {
+			writeIndented(w, s.Result)
+		}
+	}
+}
+
+func renderBlock(w io.Writer, b *contentBlock) {
+	switch b.Type {
+	case "text":
+		fmt.Fprintln(w, b.Text)
+	case "thinking":
+		fmt.Fprintln(w, "thinking:")
+		writeIndented(w, b.Thinking)
+	case "tool_use":
+		fmt.Fprintf(w, "tool: %s\n", b.Name)
+		var input any
+		if json.Unmarshal(b.Input, &input) == nil {
+			data, _ := json.MarshalIndent(input, "", "  ")
+			writeIndented(w, string(data))
+		}
+	case "tool_result":
+		status := "ok"
+		if b.IsError {
+			status = "ERROR"
+		}
+		fmt.Fprintf(w, "tool result: %s\n", status)
+		writeIndented(w, toolResultText(b.Content))
+	}
+}
+
+// toolResultText returns tool result content given as a string or as a
+// list of text blocks.
+func toolResultText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var blocks []contentBlock
+	if json.Unmarshal(raw, &blocks) == nil {
+		var parts []string
+		for _, b := range blocks {
+			if b.Type == "text" {
+				parts = append(parts, b.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return string(raw)
+}
+
+// writeIndented writes text with each line indented.
+func writeIndented(w io.Writer, text string) {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return
+	}
+	for line := range strings.SplitSeq(text, "\n") {
+		fmt.Fprintf(w, "    %s\n", line)
+	}
+}
@@ -0,0 +1,57 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"atari.session","timestamp":"2026-01-02T10:00:00Z","bead_id":"bd-001","title":"Fix login","attempt":1,"backend":"claude","model":"opus","prompt_name":"default","prompt":"Work on bd-001\nCarefully"}`,
+		`{"type":"system","subtype":"init","session_id":"sess-1","model":"opus"}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Looking at the code"},{"type":"tool_use","name":"Bash","input":{"command":"go test ./...","description":"Run tests"}}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","content":"ok  \tpkg\t0.1s\nFAIL\tother","is_error":true}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","content":[{"type":"text","text":"file contents"}]}]}}`,
+		`{"type":"atari.stderr","timestamp":"2026-01-02T10:00:01Z","text":"rate limited"}`,
+		`{"type":"result","num_turns":3,"duration_ms":65000,"total_cost_usd":0.25,"result":"Done"}`,
+		`not json`,
+	}, "\n")
+
+	var out strings.Builder
+	if err := Render(&out, strings.NewReader(input)); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	got := out.String()
+
+	for _, want := range []string{
+		"session: bd-001 attempt 1 - Fix login",
+		"model: opus, prompt: default",
+		"    Work on bd-001\n    Carefully\n",
+		"session init: sess-1",
+		"Looking at the code\n",
+		"tool: Bash\n",
+		`"command": "go test ./..."`,
+		"tool result: ERROR\n    ok  \tpkg\t0.1s\n    FAIL\tother\n",
+		"tool result: ok\n    file contents\n",
+		"stderr: rate limited",
+		"session ended: 3 turns, 1m5s, $0.2500\n    Done\n",
+		"not json\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRender_OtherBackendIsRaw(t *testing.T) {
+	input := `{"type":"atari.session","bead_id":"bd-001","attempt":1,"backend":"codex"}` + "\n" +
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"hi"}]}}` + "\n"
+
+	var out strings.Builder
+	if err := Render(&out, strings.NewReader(input)); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if !strings.Contains(out.String(), `{"type":"assistant"`) {
+		t.Errorf("expected raw line, got:\n%s", out.String())
+	}
+}
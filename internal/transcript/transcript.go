@@ -0,0 +1,290 @@
+// Package transcript saves the raw output of every agent session so an
+// attempt can be audited after the fact. Each bead attempt is one JSON lines
+// file, <dir>/<bead>/<attempt>.jsonl, holding the agent's stdout verbatim
+// with a header line before each session and stderr wrapped as JSON lines.
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+// Types of the lines atari adds to a transcript. Every other line is the
+// agent's stdout as it was received.
+const (
+	LineSession = "atari.session"
+	LineStderr  = "atari.stderr"
+)
+
+// SessionLine starts each session in a transcript.
+type SessionLine struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"timestamp"`
+	BeadID     string    `json:"bead_id"`
+	Title      string    `json:"title,omitempty"`
+	Attempt    int       `json:"attempt"`
+	Backend    string    `json:"backend,omitempty"`
+	Model      string    `json:"model,omitempty"`
+	PromptName string    `json:"prompt_name,omitempty"`
+	Prompt     string    `json:"prompt"`
+}
+
+// StderrLine holds one line the agent wrote to stderr.
+type StderrLine struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"timestamp"`
+	Text string    `json:"text"`
+}
+
+// Store manages the transcript directory.
+type Store struct {
+	dir        string
+	maxPerBead int
+	maxAge     time.Duration
+	now        func() time.Time
+}
+
+// NewStore creates a Store for transcripts under dir.
+func NewStore(dir string, cfg config.TranscriptConfig) *Store {
+	return &Store{
+		dir:        dir,
+		maxPerBead: cfg.MaxPerBead,
+		maxAge:     cfg.MaxAge,
+		now:        time.Now,
+	}
+}
+
+// Path returns the transcript file for a bead attempt.
+func (s *Store) Path(beadID string, attempt int) string {
+	return filepath.Join(s.beadDir(beadID), fmt.Sprintf("%d.jsonl", attempt))
+}
+
+// beadDir returns the directory holding a bead's transcripts. Path
+// separators are replaced so an ID cannot escape the store.
+func (s *Store) beadDir(beadID string) string {
+	name := strings.NewReplacer("/", "_", `\`, "_").Replace(beadID)
+	if name == "" || name == "." || name == ".." {
+		name = "_" + name
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// Attempts returns the attempt numbers with a transcript for a bead, in
+// ascending order.
+func (s *Store) Attempts(beadID string) ([]int, error) {
+	entries, err := os.ReadDir(s.beadDir(beadID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read transcripts: %w", err)
+	}
+
+	var attempts []int
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if n, err := strconv.Atoi(name); err == nil {
+			attempts = append(attempts, n)
+		}
+	}
+	slices.Sort(attempts)
+	return attempts, nil
+}
+
+// Open opens the transcript for a bead attempt for appending, so a retried
+// attempt or a follow-up session adds to the same file. The bead's oldest
+// transcripts beyond the per-bead limit are removed.
+func (s *Store) Open(beadID string, attempt int) (*Writer, error) {
+	if err := os.MkdirAll(s.beadDir(beadID), 0755); err != nil {
+		return nil, fmt.Errorf("create transcript directory: %w", err)
+	}
+	file, err := os.OpenFile(s.Path(beadID, attempt), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open transcript: %w", err)
+	}
+
+	if s.maxPerBead > 0 {
+		attempts, err := s.Attempts(beadID)
+		if err == nil && len(attempts) > s.maxPerBead {
+			for _, old := range attempts[:len(attempts)-s.maxPerBead] {
+				if old != attempt {
+					_ = os.Remove(s.Path(beadID, old))
+				}
+			}
+		}
+	}
+
+	return newWriter(file, beadID, attempt, s.now), nil
+}
+
+// Prune removes transcripts older than the configured maximum age, and bead
+// directories left empty. It returns the number of files removed.
+func (s *Store) Prune() (int, error) {
+	if s.maxAge <= 0 {
+		return 0, nil
+	}
+	beads, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read transcripts: %w", err)
+	}
+
+	cutoff := s.now().Add(-s.maxAge)
+	removed := 0
+	for _, bead := range beads {
+		if !bead.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.dir, bead.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		kept := 0
+		for _, f := range files {
+			info, err := f.Info()
+			if err == nil && !f.IsDir() && info.ModTime().Before(cutoff) {
+				if os.Remove(filepath.Join(dir, f.Name())) == nil {
+					removed++
+					continue
+				}
+			}
+			kept++
+		}
+		if kept == 0 {
+			_ = os.Remove(dir)
+		}
+	}
+	return removed, nil
+}
+
+// Writer appends one attempt's sessions to a transcript file. Its stdout and
+// stderr writers never fail, so a transcript problem cannot break a session.
+type Writer struct {
+	mu      sync.Mutex
+	file    *os.File
+	beadID  string
+	attempt int
+	now     func() time.Time
+	stdout  *lineWriter
+	stderr  *lineWriter
+}
+
+func newWriter(file *os.File, beadID string, attempt int, now func() time.Time) *Writer {
+	w := &Writer{file: file, beadID: beadID, attempt: attempt, now: now}
+	w.stdout = &lineWriter{emit: w.writeLine}
+	w.stderr = &lineWriter{emit: func(line []byte) {
+		w.writeJSON(StderrLine{Type: LineStderr, Time: w.now(), Text: string(line)})
+	}}
+	return w
+}
+
+// BeginSession writes the header for a new session. The type, bead and
+// attempt are filled in, and the time if unset.
+func (w *Writer) BeginSession(line SessionLine) {
+	line.Type = LineSession
+	line.BeadID = w.beadID
+	line.Attempt = w.attempt
+	if line.Time.IsZero() {
+		line.Time = w.now()
+	}
+	w.writeJSON(line)
+}
+
+// Stdout returns a writer for the agent's stdout. Complete lines are copied
+// to the transcript verbatim.
+func (w *Writer) Stdout() io.Writer {
+	return w.stdout
+}
+
+// Stderr returns a writer for the agent's stderr. Each line is stored as a
+// StderrLine.
+func (w *Writer) Stderr() io.Writer {
+	return w.stderr
+}
+
+// Close flushes partial lines and closes the file.
+func (w *Writer) Close() error {
+	w.stdout.flush()
+	w.stderr.flush()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *Writer) writeJSON(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	w.writeLine(data)
+}
+
+func (w *Writer) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return
+	}
+	_, _ = w.file.Write(append(slices.Clip(line), '\n'))
+}
+
+// lineWriter splits a byte stream into lines and passes each to emit.
+type lineWriter struct {
+	mu   sync.Mutex
+	buf  []byte
+	emit func(line []byte)
+}
+
+// Write implements io.Writer. It always succeeds.
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimSuffix(l.buf[:i], []byte("\r"))
+		if len(line) > 0 {
+			l.emit(line)
+		}
+		l.buf = l.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits any trailing partial line.
+func (l *lineWriter) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.buf) > 0 {
+		l.emit(l.buf)
+		l.buf = nil
+	}
+}
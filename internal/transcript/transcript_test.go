@@ -0,0 +1,162 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open transcript: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir(), config.TranscriptConfig{})
+	w, err := store.Open("bd-001", 2)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+
+	w.BeginSession(SessionLine{Title: "Fix login", Backend: "claude", Prompt: "do the work"})
+	_, _ = w.Stdout().Write([]byte(`{"type":"system","subtype":"init"}` + "\n" + `{"type":"res`))
+	_, _ = w.Stdout().Write([]byte(`ult"}` + "\n"))
+	_, _ = w.Stderr().Write([]byte("warning: slow\npartial"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	lines := readLines(t, store.Path("bd-001", 2))
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want 5: %q", len(lines), lines)
+	}
+
+	var header SessionLine
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("decode header: %v", err)
+	}
+	if header.Type != LineSession || header.BeadID != "bd-001" || header.Attempt != 2 || header.Time.IsZero() {
+		t.Errorf("header = %+v", header)
+	}
+	if lines[1] != `{"type":"system","subtype":"init"}` || lines[2] != `{"type":"result"}` {
+		t.Errorf("stdout lines not copied verbatim: %q", lines[1:3])
+	}
+
+	var stderr StderrLine
+	if err := json.Unmarshal([]byte(lines[3]), &stderr); err != nil {
+		t.Fatalf("decode stderr: %v", err)
+	}
+	if stderr.Type != LineStderr || stderr.Text != "warning: slow" {
+		t.Errorf("stderr line = %+v", stderr)
+	}
+	if !strings.Contains(lines[4], `"text":"partial"`) {
+		t.Errorf("partial stderr line not flushed on close: %s", lines[4])
+	}
+
+	// Writes after Close are dropped rather than failing.
+	if n, err := w.Stdout().Write([]byte("late\n")); err != nil || n != 5 {
+		t.Errorf("Write after Close = %d, %v", n, err)
+	}
+}
+
+func TestStore_OpenAppends(t *testing.T) {
+	store := NewStore(t.TempDir(), config.TranscriptConfig{})
+	for range 2 {
+		w, err := store.Open("bd-001", 1)
+		if err != nil {
+			t.Fatalf("Open() error: %v", err)
+		}
+		w.BeginSession(SessionLine{})
+		_ = w.Close()
+	}
+	if lines := readLines(t, store.Path("bd-001", 1)); len(lines) != 2 {
+		t.Errorf("got %d lines, want both sessions", len(lines))
+	}
+}
+
+func TestStore_MaxPerBead(t *testing.T) {
+	store := NewStore(t.TempDir(), config.TranscriptConfig{MaxPerBead: 2})
+	for attempt := 1; attempt <= 4; attempt++ {
+		w, err := store.Open("bd-001", attempt)
+		if err != nil {
+			t.Fatalf("Open() error: %v", err)
+		}
+		_ = w.Close()
+	}
+
+	attempts, err := store.Attempts("bd-001")
+	if err != nil {
+		t.Fatalf("Attempts() error: %v", err)
+	}
+	if fmt.Sprint(attempts) != "[3 4]" {
+		t.Errorf("Attempts() = %v, want [3 4]", attempts)
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, config.TranscriptConfig{MaxAge: 24 * time.Hour})
+
+	for _, bead := range []string{"bd-old", "bd-new"} {
+		w, err := store.Open(bead, 1)
+		if err != nil {
+			t.Fatalf("Open() error: %v", err)
+		}
+		_ = w.Close()
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(store.Path("bd-old", 1), old, old); err != nil {
+		t.Fatalf("Chtimes() error: %v", err)
+	}
+
+	removed, err := store.Prune()
+	if err != nil {
+		t.Fatalf("Prune() error: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Prune() removed %d, want 1", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bd-old")); !os.IsNotExist(err) {
+		t.Error("empty bead directory should be removed")
+	}
+	if _, err := os.Stat(store.Path("bd-new", 1)); err != nil {
+		t.Errorf("recent transcript removed: %v", err)
+	}
+}
+
+func TestStore_PathStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, config.TranscriptConfig{})
+	for _, id := range []string{"../escape", "a/b", ".."} {
+		path := store.Path(id, 1)
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || strings.Count(rel, string(filepath.Separator)) != 1 {
+			t.Errorf("Path(%q) = %s, outside %s", id, path, dir)
+		}
+	}
+}
+
+func TestStore_AttemptsMissingBead(t *testing.T) {
+	store := NewStore(t.TempDir(), config.TranscriptConfig{})
+	attempts, err := store.Attempts("bd-none")
+	if err != nil || attempts != nil {
+		t.Errorf("Attempts() = %v, %v, want nil, nil", attempts, err)
+	}
+}
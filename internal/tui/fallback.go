@@ -57,7 +57,11 @@ func (t *TUI) runSimple() error {
 				continue
 			}
 
-			timestamp := time.Now().Format("15:04:05")
+			now := time.Now()
+			if t.playback != nil {
+				now = event.Timestamp()
+			}
+			timestamp := now.Format("15:04:05")
 			fmt.Printf("%s %s\n", timestamp, text)
 		}
 	}
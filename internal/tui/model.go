@@ -32,8 +32,13 @@ type beadInfo struct {
 	Title     string
 	Priority  int
 	StartTime time.Time
+	Model     string // model selected for the session, if any
 }
 
+// sessionCosts holds the live cost estimates of one bead's sessions, by
+// session ID. A bead has more than one when a follow-up session runs.
+type sessionCosts map[string]float64
+
 // modelStats holds display statistics.
 type modelStats struct {
 	Completed         int
@@ -107,6 +112,9 @@ type model struct {
 	currentSessionTurns int                        // turns in current session (reset on iteration end)
 	inBackoff           int                        // number of beads currently in backoff period
 	topBlockedBead      *viewmodel.BlockedBeadInfo // bead with shortest remaining backoff
+	workers             []viewmodel.WorkerInfo     // per-worker progress (shown when more than one worker)
+	schedule            *viewmodel.ScheduleInfo    // drain schedule window (nil without a schedule)
+	liveCost            map[string]sessionCosts    // estimated cost of running sessions, by bead ID
 	epicID              string                     // active epic filter, if any
 	workingDirectory    string                     // working directory for the TUI
 	activeTopLevelID    string                     // active top-level item ID (when selection_mode=top-level)
@@ -150,6 +158,13 @@ type model struct {
 	onResume func()
 	onQuit   func()
 	onRetry  func()
+	onStop   func()
+
+	// Attached to a drain in another process: quitting detaches
+	detach bool
+
+	// Replaying a recorded log instead of watching a drain
+	playback Playback
 
 	// Stats provider
 	statsGetter StatsGetter
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Seek steps for the replay keys.
+const (
+	playbackSeekShort = time.Minute
+	playbackSeekLong  = 10 * time.Minute
+)
+
+// Playback controls a recorded event log replayed into the TUI.
+type Playback interface {
+	TogglePause()
+	Seek(d time.Duration)
+	Paused() bool
+	Speed() float64
+	Now() time.Time
+	Progress() (played, total int)
+}
+
+// WithPlayback runs the TUI against a replayed log. Space and p pause and
+// resume, [ and ] seek by a minute, { and } by ten minutes, and elapsed times
+// follow the replay position rather than the wall clock.
+func WithPlayback(pb Playback) Option {
+	return func(t *TUI) {
+		t.playback = pb
+	}
+}
+
+// handlePlaybackKey handles the replay keys. It reports whether the key was
+// used; drain control keys are swallowed since there is no drain to control.
+func (m *model) handlePlaybackKey(key string) bool {
+	switch key {
+	case " ", "p":
+		m.playback.TogglePause()
+	case "[":
+		m.playback.Seek(-playbackSeekShort)
+	case "]":
+		m.playback.Seek(playbackSeekShort)
+	case "{":
+		m.playback.Seek(-playbackSeekLong)
+	case "}":
+		m.playback.Seek(playbackSeekLong)
+	case "r", "R", "S":
+	default:
+		return false
+	}
+	return true
+}
+
+// resetDrainState discards everything learned from events, before a replay
+// sends earlier events again. Layout and pane state are kept.
+func (m *model) resetDrainState() {
+	m.status = "idle"
+	m.currentBead = nil
+	m.stats = modelStats{}
+	m.currentSessionTurns = 0
+	m.liveCost = nil
+	m.activeTopLevelID = ""
+	m.activeTopLevelTitle = ""
+	m.eventLines = nil
+	m.scrollPos = 0
+	m.autoScroll = true
+	m.stalledBeadID = ""
+	m.stalledBeadTitle = ""
+	m.stallReason = ""
+	m.stalledAt = time.Time{}
+	m.stallType = ""
+	m.stallCreatedBeads = nil
+	m.graphPane.SetCurrentBead("")
+	m.graphPane.SetActiveTopLevel("")
+}
+
+// clock returns the current time, which follows the replay position when
+// replaying a log.
+func (m model) clock() time.Time {
+	if m.playback != nil {
+		return m.playback.Now()
+	}
+	return time.Now()
+}
+
+// playbackStatus describes the replay position for the header.
+func (m model) playbackStatus() string {
+	played, total := m.playback.Progress()
+	state := "playing"
+	if m.playback.Paused() {
+		state = "paused"
+	}
+	return fmt.Sprintf("REPLAY %sx %s %s %d/%d",
+		strconv.FormatFloat(m.playback.Speed(), 'g', -1, 64),
+		state,
+		m.playback.Now().Local().Format("2006-01-02 15:04:05"),
+		played, total)
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/replay"
+)
+
+// fakePlayback records the controls used by the TUI.
+type fakePlayback struct {
+	paused  bool
+	seeks   []time.Duration
+	now     time.Time
+	toggled int
+}
+
+func (f *fakePlayback) TogglePause()                  { f.toggled++; f.paused = !f.paused }
+func (f *fakePlayback) Seek(d time.Duration)          { f.seeks = append(f.seeks, d) }
+func (f *fakePlayback) Paused() bool                  { return f.paused }
+func (f *fakePlayback) Speed() float64                { return 10 }
+func (f *fakePlayback) Now() time.Time                { return f.now }
+func (f *fakePlayback) Progress() (played, total int) { return 4, 9 }
+
+func newPlaybackModel(pb Playback) model {
+	m := newModel(nil, nil, nil, nil, nil, nil, nil, nil, nil, "", "")
+	m.playback = pb
+	m.width = 120
+	m.height = 40
+	return m
+}
+
+func TestPlayback_Keys(t *testing.T) {
+	pb := &fakePlayback{}
+	m := newPlaybackModel(pb)
+
+	for _, key := range []string{" ", "p", "[", "]", "{", "}", "r", "S"} {
+		newM, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = newM.(model)
+	}
+
+	if pb.toggled != 2 {
+		t.Errorf("TogglePause called %d times, want 2", pb.toggled)
+	}
+	want := []time.Duration{-time.Minute, time.Minute, -10 * time.Minute, 10 * time.Minute}
+	if len(pb.seeks) != len(want) {
+		t.Fatalf("seeks = %v, want %v", pb.seeks, want)
+	}
+	for i := range want {
+		if pb.seeks[i] != want[i] {
+			t.Errorf("seek %d = %v, want %v", i, pb.seeks[i], want[i])
+		}
+	}
+	if m.status != "idle" {
+		t.Errorf("status = %q, drain keys should not change it during replay", m.status)
+	}
+}
+
+func TestPlayback_QuitWithoutConfirm(t *testing.T) {
+	m := newPlaybackModel(&fakePlayback{})
+	m.status = "working"
+
+	newM, cmd := m.tryQuit()
+	if newM.(model).quitConfirmOpen || cmd == nil {
+		t.Error("quitting a replay should not ask for confirmation")
+	}
+}
+
+func TestPlayback_ClockAndHeader(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	pb := &fakePlayback{now: start.Add(3 * time.Minute), paused: true}
+	m := newPlaybackModel(pb)
+	m.handleEvent(&events.IterationStartEvent{
+		BaseEvent: events.BaseEvent{EventType: events.EventIterationStart, Time: start, Src: events.SourceInternal},
+		BeadID:    "bd-001",
+	})
+
+	m.handleTick()
+	if m.stats.CurrentDurationMs != (3 * time.Minute).Milliseconds() {
+		t.Errorf("CurrentDurationMs = %d, want 3m of replay time", m.stats.CurrentDurationMs)
+	}
+
+	status := m.renderStatus()
+	if !strings.Contains(status, "REPLAY 10x paused") || !strings.Contains(status, "4/9") {
+		t.Errorf("status = %q, want replay position", status)
+	}
+	if footer := m.renderEventsFooter(); !strings.Contains(footer, "space: play/pause") {
+		t.Errorf("footer = %q, want replay keys", footer)
+	}
+}
+
+// TestPlayback_ReplayedLog drives the model from a recorded log through a
+// router, seeking forwards and back.
+func TestPlayback_ReplayedLog(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration, eventType events.EventType) events.BaseEvent {
+		return events.BaseEvent{EventType: eventType, Time: start.Add(offset), Src: events.SourceInternal}
+	}
+	recorded := []events.Event{
+		&events.DrainStateChangedEvent{BaseEvent: at(0, events.EventDrainStateChanged), From: "idle", To: "working"},
+		&events.IterationStartEvent{BaseEvent: at(time.Second, events.EventIterationStart), BeadID: "bd-001", Title: "First"},
+		&events.IterationEndEvent{BaseEvent: at(time.Minute, events.EventIterationEnd), BeadID: "bd-001", Success: true, TotalCostUSD: 0.5},
+		&events.IterationStartEvent{BaseEvent: at(2*time.Minute, events.EventIterationStart), BeadID: "bd-002", Title: "Second"},
+		&events.IterationEndEvent{BaseEvent: at(5*time.Minute, events.EventIterationEnd), BeadID: "bd-002", Success: false},
+	}
+
+	router := events.NewRouter(0)
+	defer router.Close()
+	ch := router.SubscribeBuffered(2 * len(recorded))
+	player := replay.New(recorded, router, replay.WithStartPaused())
+	m := newPlaybackModel(player)
+
+	drain := func() {
+		for {
+			select {
+			case ev := <-ch:
+				m.handleEvent(ev)
+			default:
+				return
+			}
+		}
+	}
+
+	player.Seek(10 * time.Minute)
+	drain()
+	if m.stats.Completed != 1 || m.stats.Failed != 1 {
+		t.Errorf("after seeking to the end: completed=%d failed=%d, want 1 and 1", m.stats.Completed, m.stats.Failed)
+	}
+
+	// The forward seek stopped at the last event, 5m in
+	player.Seek(-2*time.Minute - 30*time.Second)
+	drain()
+	if m.stats.Completed != 1 || m.stats.Failed != 0 {
+		t.Errorf("after seeking back: completed=%d failed=%d, want 1 and 0", m.stats.Completed, m.stats.Failed)
+	}
+	if m.currentBead == nil || m.currentBead.ID != "bd-002" {
+		t.Errorf("currentBead = %+v, want bd-002 in progress", m.currentBead)
+	}
+	if m.stats.TotalCost != 0.5 {
+		t.Errorf("TotalCost = %v, want only the first bead's cost", m.stats.TotalCost)
+	}
+}
@@ -26,12 +26,15 @@ type TUI struct {
 	onResume         func()
 	onQuit           func()
 	onRetry          func()
+	onStop           func()
+	detach           bool
 	statsGetter      StatsGetter
 	observer         *observer.Observer
 	graphFetcher     BeadFetcher
 	beadStateGetter  BeadStateGetter
 	epicID           string
 	workingDirectory string
+	playback         Playback
 }
 
 // Option configures the TUI.
@@ -78,6 +81,22 @@ func WithOnRetry(fn func()) Option {
 	}
 }
 
+// WithOnStop sets the callback invoked when the user presses Shift+S to stop the drain.
+// Without it the key does nothing; quitting is the usual way to stop an in-process drain.
+func WithOnStop(fn func()) Option {
+	return func(t *TUI) {
+		t.onStop = fn
+	}
+}
+
+// WithDetach marks the TUI as attached to a drain running elsewhere.
+// Quitting then detaches immediately, without asking for confirmation.
+func WithDetach() Option {
+	return func(t *TUI) {
+		t.detach = true
+	}
+}
+
 // WithStatsGetter sets the stats provider for header display.
 func WithStatsGetter(sg StatsGetter) Option {
 	return func(t *TUI) {
@@ -136,6 +155,9 @@ func (t *TUI) Run() error {
 
 	// Run the full bubbletea TUI
 	m := newModel(t.eventChan, t.onPause, t.onResume, t.onQuit, t.onRetry, t.statsGetter, t.observer, t.graphFetcher, t.beadStateGetter, t.epicID, t.workingDirectory)
+	m.onStop = t.onStop
+	m.detach = t.detach
+	m.playback = t.playback
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err := p.Run()
 	return err
@@ -295,6 +295,11 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Replay keys take the place of the drain controls
+	if m.playback != nil && m.handlePlaybackKey(key) {
+		return m, nil
+	}
+
 	// Global control keys - work when observer is in normal mode (not typing)
 	// In insert mode, these keys go to the textarea instead
 	if !m.observerPane.IsInsertMode() {
@@ -323,6 +328,14 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.status = "retrying..."
 				return m, nil
 			}
+
+		case "S":
+			// Shift+S: stop the drain (attach mode)
+			if m.onStop != nil && m.status != "stopped" {
+				m.onStop()
+				m.status = "stopping..."
+				return m, nil
+			}
 		}
 	}
 
@@ -359,6 +372,14 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.status = "retrying..."
 			return m, nil
 		}
+
+	case "S":
+		// Shift+S: stop the drain (attach mode)
+		if m.onStop != nil && m.status != "stopped" {
+			m.onStop()
+			m.status = "stopping..."
+			return m, nil
+		}
 	}
 
 	// When graph is focused, forward remaining keys to graph pane
@@ -405,6 +426,10 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // handleEvent processes an event and updates model state.
 func (m *model) handleEvent(event events.Event) {
 	switch e := event.(type) {
+	case *events.ReplayRewindEvent:
+		m.resetDrainState()
+		return
+
 	case *events.DrainStateChangedEvent:
 		m.status = e.To
 
@@ -425,10 +450,40 @@ func (m *model) handleEvent(event events.Event) {
 		// Update graph pane with active top-level for subtree highlighting
 		m.graphPane.SetActiveTopLevel(e.TopLevelID)
 
+	case *events.SessionStartEvent:
+		if m.currentBead != nil && m.currentBead.ID == e.BeadID {
+			m.currentBead.Model = e.Model
+		}
+
+	case *events.SessionCostEvent:
+		if m.liveCost == nil {
+			m.liveCost = make(map[string]sessionCosts)
+		}
+		if m.liveCost[e.BeadID] == nil {
+			m.liveCost[e.BeadID] = make(sessionCosts)
+		}
+		m.liveCost[e.BeadID][e.SessionID] = e.EstimatedCostUSD
+		// Cost updates only refresh the header; one per message would flood the feed
+		return
+
+	case *events.SessionEndEvent:
+		// The reported cost supersedes the estimate until the iteration ends
+		if _, ok := m.liveCost[e.BeadID][e.SessionID]; ok {
+			m.liveCost[e.BeadID][e.SessionID] = e.TotalCostUSD
+		}
+
 	case *events.IterationEndEvent:
 		m.currentBead = nil
 		m.status = "idle"
 		m.currentSessionTurns = 0 // Reset turn count for next session
+		// The iteration's cost, including any follow-up session and sessions
+		// that failed before reporting, is now counted in TotalCost. A single
+		// worker has at most one bead, so any leftover estimate belongs to it too.
+		if len(m.workers) > 1 {
+			delete(m.liveCost, e.BeadID)
+		} else {
+			m.liveCost = nil
+		}
 		if e.Success {
 			m.stats.Completed++
 		} else {
@@ -503,7 +558,7 @@ func (m *model) handleEvent(event events.Event) {
 func (m *model) handleTick() {
 	// Update current bead elapsed time
 	if m.currentBead != nil && !m.currentBead.StartTime.IsZero() {
-		m.stats.CurrentDurationMs = time.Since(m.currentBead.StartTime).Milliseconds()
+		m.stats.CurrentDurationMs = m.clock().Sub(m.currentBead.StartTime).Milliseconds()
 	}
 
 	if m.statsGetter == nil {
@@ -549,6 +604,12 @@ func (m *model) handleTick() {
 	m.inBackoff = stats.InBackoff
 	m.topBlockedBead = stats.TopBlockedBead
 
+	// Update per-worker progress for header display
+	m.workers = stats.Workers
+
+	// Update schedule window for header display
+	m.schedule = stats.Schedule
+
 	// Sync stall info from controller (for banner display after restart)
 	// Use StallReason as the presence indicator since review stalls have no StalledBeadID
 	if stats.StallReason != "" && m.stallReason == "" {
@@ -574,9 +635,10 @@ func (m *model) handleTick() {
 
 // tryQuit attempts to quit, showing confirmation if atari is actively working.
 // Only shows confirmation when status indicates active work (not idle/paused/stopped).
+// Detaching never interrupts work, so it needs no confirmation.
 func (m model) tryQuit() (tea.Model, tea.Cmd) {
 	// Check if we need confirmation - only when actively working
-	needsConfirm := m.status != "idle" && m.status != "paused" && m.status != "stopped"
+	needsConfirm := !m.detach && m.playback == nil && m.status != "idle" && m.status != "paused" && m.status != "stopped"
 
 	if needsConfirm {
 		m.quitConfirmOpen = true
@@ -109,6 +109,46 @@ func TestHandleKey_Resume(t *testing.T) {
 	}
 }
 
+func TestHandleKey_Stop(t *testing.T) {
+	stopCalled := false
+	m := model{
+		status: "working",
+		onStop: func() { stopCalled = true },
+	}
+
+	newM, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+
+	if !stopCalled {
+		t.Error("onStop callback should be called")
+	}
+	if cmd != nil {
+		t.Error("should return nil command")
+	}
+	if newM.(model).status != "stopping..." {
+		t.Errorf("status should be 'stopping...', got %q", newM.(model).status)
+	}
+}
+
+func TestHandleKey_DetachQuitsWithoutConfirm(t *testing.T) {
+	m := model{
+		status:    "working",
+		focusMode: FocusModeNone,
+		detach:    true,
+	}
+
+	newM, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+
+	if newM.(model).quitConfirmOpen {
+		t.Error("detaching should not ask for confirmation")
+	}
+	if cmd == nil {
+		t.Error("should return tea.Quit command")
+	}
+	if footer := newM.(model).renderHeaderOnlyFooter(); !strings.Contains(footer, "q: detach") {
+		t.Errorf("footer should offer detach, got %q", footer)
+	}
+}
+
 func TestHandleKey_ScrollUp(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -304,6 +344,29 @@ func TestHandleEvent_IterationStart(t *testing.T) {
 	}
 }
 
+func TestHandleEvent_SessionStartRecordsModel(t *testing.T) {
+	m := model{status: "idle", width: 120}
+
+	m.handleEvent(&events.IterationStartEvent{
+		BaseEvent: events.NewInternalEvent(events.EventIterationStart),
+		BeadID:    "bd-123",
+		Title:     "Test bead",
+	})
+	m.handleEvent(&events.SessionStartEvent{
+		BaseEvent: events.NewInternalEvent(events.EventSessionStart),
+		BeadID:    "bd-123",
+		Title:     "Test bead",
+		Model:     "opus",
+	})
+
+	if m.currentBead == nil || m.currentBead.Model != "opus" {
+		t.Fatalf("currentBead.Model should be 'opus', got %+v", m.currentBead)
+	}
+	if line := m.renderBeadLine(120); !strings.Contains(line, "[opus, ") {
+		t.Errorf("bead line should show the model, got %q", line)
+	}
+}
+
 func TestHandleEvent_IterationEnd(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -357,6 +420,95 @@ func TestHandleEvent_IterationEnd(t *testing.T) {
 	}
 }
 
+func TestHandleEvent_SessionCost(t *testing.T) {
+	m := model{stats: modelStats{TotalCost: 1.0}}
+
+	m.handleEvent(&events.SessionCostEvent{
+		BaseEvent:        events.NewClaudeEvent(events.EventSessionCost),
+		SessionID:        "sess-1",
+		EstimatedCostUSD: 0.10,
+	})
+	m.handleEvent(&events.SessionCostEvent{
+		BaseEvent:        events.NewClaudeEvent(events.EventSessionCost),
+		SessionID:        "sess-1",
+		EstimatedCostUSD: 0.25,
+	})
+
+	if got := m.displayCost(); got != 1.25 {
+		t.Errorf("displayCost() = %v, want 1.25", got)
+	}
+	if len(m.eventLines) != 0 {
+		t.Errorf("cost updates should not add feed lines, got %d", len(m.eventLines))
+	}
+
+	// The reported cost replaces the estimate when the session ends
+	m.handleEvent(&events.SessionEndEvent{
+		BaseEvent:    events.NewClaudeEvent(events.EventSessionEnd),
+		SessionID:    "sess-1",
+		TotalCostUSD: 0.30,
+	})
+	if got := m.displayCost(); got != 1.3 {
+		t.Errorf("displayCost() after session end = %v, want 1.3", got)
+	}
+
+	// The iteration's cost moves into TotalCost
+	m.handleEvent(&events.IterationEndEvent{
+		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
+		BeadID:       "bd-123",
+		Success:      true,
+		TotalCostUSD: 0.30,
+		SessionID:    "sess-1",
+	})
+	if got := m.displayCost(); got != 1.3 {
+		t.Errorf("displayCost() after iteration end = %v, want 1.3", got)
+	}
+	if len(m.liveCost) != 0 {
+		t.Errorf("expected live costs cleared, got %v", m.liveCost)
+	}
+}
+
+func TestHandleEvent_SessionCostPooled(t *testing.T) {
+	m := model{workers: []viewmodel.WorkerInfo{{ID: 1}, {ID: 2}}}
+	cost := func(beadID, sessionID string, usd float64) {
+		m.handleEvent(&events.SessionCostEvent{
+			BaseEvent:        events.NewClaudeEvent(events.EventSessionCost),
+			SessionID:        sessionID,
+			BeadID:           beadID,
+			EstimatedCostUSD: usd,
+		})
+	}
+
+	// bd-1 runs a session and a follow-up; bd-2 runs on the other worker
+	cost("bd-1", "sess-1", 0.5)
+	m.handleEvent(&events.SessionEndEvent{
+		BaseEvent:    events.NewClaudeEvent(events.EventSessionEnd),
+		SessionID:    "sess-1",
+		BeadID:       "bd-1",
+		TotalCostUSD: 0.5,
+	})
+	cost("bd-1", "sess-1b", 0.125)
+	cost("bd-2", "sess-2", 0.25)
+	if got := m.displayCost(); got != 0.875 {
+		t.Errorf("displayCost() = %v, want 0.875", got)
+	}
+
+	// A failed iteration carries no session ID; every bd-1 estimate goes
+	m.handleEvent(&events.IterationEndEvent{
+		BaseEvent:    events.NewInternalEvent(events.EventIterationEnd),
+		BeadID:       "bd-1",
+		Success:      false,
+		TotalCostUSD: 0.625,
+	})
+
+	// The other worker's session keeps its estimate
+	if got := m.displayCost(); got != 0.875 {
+		t.Errorf("displayCost() after bd-1 ends = %v, want 0.875", got)
+	}
+	if _, ok := m.liveCost["bd-1"]; ok {
+		t.Errorf("expected bd-1 estimates cleared, got %v", m.liveCost)
+	}
+}
+
 func TestHandleEvent_BeadAbandoned(t *testing.T) {
 	m := model{stats: modelStats{Abandoned: 0}}
 
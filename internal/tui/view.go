@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/npratt/atari/internal/events"
+	"github.com/npratt/atari/internal/schedule"
 )
 
 const (
@@ -116,6 +117,9 @@ func (m model) renderStallBanner() string {
 	if m.stallType == "review" {
 		return m.renderReviewStallBanner()
 	}
+	if m.stallType == "budget" {
+		return m.renderBudgetStallBanner()
+	}
 	return m.renderAbandonedStallBanner()
 }
 
@@ -138,7 +142,7 @@ func (m model) renderAbandonedStallBanner() string {
 	// Calculate stall duration
 	stallDuration := ""
 	if !m.stalledAt.IsZero() {
-		stallDuration = fmt.Sprintf(" (stalled for %s)", formatDurationHuman(time.Since(m.stalledAt).Milliseconds()))
+		stallDuration = fmt.Sprintf(" (stalled for %s)", formatDurationHuman(m.clock().Sub(m.stalledAt).Milliseconds()))
 	}
 
 	var content strings.Builder
@@ -176,6 +180,55 @@ func (m model) renderAbandonedStallBanner() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalContent)
 }
 
+// renderBudgetStallBanner renders the stall banner when a cost limit is reached.
+func (m model) renderBudgetStallBanner() string {
+	// Title style (orange, bold)
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("208"))
+
+	// Info style
+	infoStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	// Hint style
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("245")).
+		Italic(true)
+
+	// Calculate stall duration
+	stallDuration := ""
+	if !m.stalledAt.IsZero() {
+		stallDuration = fmt.Sprintf(" (stalled for %s)", formatDurationHuman(m.clock().Sub(m.stalledAt).Milliseconds()))
+	}
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render(fmt.Sprintf("BUDGET REACHED%s", stallDuration)))
+	content.WriteString("\n\n")
+
+	// Reason line (truncate if too long)
+	reason := m.stallReason
+	if len(reason) > 60 {
+		reason = reason[:57] + "..."
+	}
+	content.WriteString(infoStyle.Render(fmt.Sprintf("Reason: %s", reason)))
+	content.WriteString("\n\n")
+
+	content.WriteString(hintStyle.Render("Press Shift+R to continue with a fresh budget"))
+
+	// Create modal box with orange border
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("208")).
+		Padding(1, 3).
+		Width(68)
+
+	modalContent := modalStyle.Render(content.String())
+
+	// Center the banner on screen
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modalContent)
+}
+
 // renderReviewStallBanner renders the stall banner for review stalls (created beads).
 func (m model) renderReviewStallBanner() string {
 	// Title style (yellow, bold)
@@ -199,7 +252,7 @@ func (m model) renderReviewStallBanner() string {
 	// Calculate stall duration
 	stallDuration := ""
 	if !m.stalledAt.IsZero() {
-		stallDuration = fmt.Sprintf(" (waiting for %s)", formatDurationHuman(time.Since(m.stalledAt).Milliseconds()))
+		stallDuration = fmt.Sprintf(" (waiting for %s)", formatDurationHuman(m.clock().Sub(m.stalledAt).Milliseconds()))
 	}
 
 	var content strings.Builder
@@ -336,16 +389,16 @@ func (m model) renderFullscreenObserverView() string {
 func (m model) renderFullscreenGraphFooter() string {
 	var help string
 	if m.graphPane.IsShowingDetail() {
-		help = "enter: fullscreen  esc: back  j/k: scroll  B: exit fullscreen  q: quit"
+		help = "enter: fullscreen  esc: back  j/k: scroll  B: exit fullscreen  " + m.quitHint()
 	} else {
-		help = "↑/↓/←/→: nav  d: density  a: view  R: refresh  B/esc: exit fullscreen  q: quit"
+		help = "↑/↓/←/→: nav  d: density  a: view  R: refresh  B/esc: exit fullscreen  " + m.quitHint()
 	}
 	return styles.Footer.Render(help)
 }
 
 // renderFullscreenObserverFooter returns footer help when observer is in fullscreen mode.
 func (m model) renderFullscreenObserverFooter() string {
-	help := "enter: ask  O/esc: exit fullscreen  ctrl+c: cancel  q: quit"
+	help := "enter: ask  O/esc: exit fullscreen  ctrl+c: cancel  " + m.quitHint()
 	return styles.Footer.Render(help)
 }
 
@@ -436,12 +489,14 @@ func (m model) renderHeaderOnlyFooter() string {
 	var parts []string
 
 	// Pause/resume/retry based on status
-	switch m.status {
-	case "paused", "pausing...", "pausing":
+	switch {
+	case m.playback != nil:
+		parts = append(parts, "space: play/pause", "[/]: seek 1m", "{/}: seek 10m")
+	case m.status == "paused" || m.status == "pausing..." || m.status == "pausing":
 		parts = append(parts, "r: resume")
-	case "stalled":
+	case m.status == "stalled":
 		parts = append(parts, "R: retry", "r: skip")
-	case "stopped":
+	case m.status == "stopped":
 		// No pause/resume for stopped state
 	default:
 		parts = append(parts, "p: pause")
@@ -450,8 +505,12 @@ func (m model) renderHeaderOnlyFooter() string {
 	// Panel open hints
 	parts = append(parts, "e: events", "o: observer", "b: beads")
 
+	if m.onStop != nil && m.status != "stopped" {
+		parts = append(parts, "S: stop")
+	}
+
 	// Quit
-	parts = append(parts, "q: quit")
+	parts = append(parts, m.quitHint())
 
 	return styles.Footer.Render(strings.Join(parts, "  "))
 }
@@ -603,7 +662,7 @@ func (m model) renderVerticalSplit() string {
 // renderSharedHeader renders the header that spans all panes.
 func (m model) renderSharedHeader(w int) string {
 	// Line 1: Status, working directory, and cost
-	cost := styles.Cost.Render(fmt.Sprintf("$%.4f", m.stats.TotalCost))
+	cost := styles.Cost.Render(fmt.Sprintf("$%.4f", m.displayCost()))
 	costWidth := lipgloss.Width(cost)
 	status := m.renderStatusWithWorkDir(w, costWidth)
 
@@ -615,27 +674,13 @@ func (m model) renderSharedHeader(w int) string {
 	)
 
 	// Line 2: Current bead (or idle message with backoff info) with elapsed time and turn count
-	var beadLine string
-	if m.currentBead != nil {
-		elapsed := formatDurationHuman(m.stats.CurrentDurationMs)
-		var beadText string
-		if m.currentSessionTurns > 0 {
-			beadText = fmt.Sprintf("bead: %s - %s [%s, turn %d]",
-				m.currentBead.ID, m.currentBead.Title, elapsed, m.currentSessionTurns)
-		} else {
-			beadText = fmt.Sprintf("bead: %s - %s [%s]",
-				m.currentBead.ID, m.currentBead.Title, elapsed)
-		}
-		if len(beadText) > w {
-			beadText = beadText[:w-3] + "..."
-		}
-		beadLine = styles.Bead.Render(beadText)
-	} else {
-		beadLine = m.renderBlockedInfo(w)
-	}
+	beadLine := m.renderBeadLine(w)
 
 	// Line 3: Turns, total duration, and progress stats
 	turnsText := fmt.Sprintf("turns: %d", m.stats.TotalTurns)
+	if sched := m.scheduleText(); sched != "" {
+		turnsText += "  " + sched
+	}
 	totalDur := formatDurationHuman(m.stats.TotalDurationMs)
 	statsText := fmt.Sprintf("total: %s  completed: %d  failed: %d  abandoned: %d",
 		totalDur, m.stats.Completed, m.stats.Failed, m.stats.Abandoned)
@@ -751,7 +796,7 @@ func (m model) renderGraphPane(width, height int) string {
 // renderHeaderForWidth renders the header for a specific width.
 func (m model) renderHeaderForWidth(w int) string {
 	// Line 1: Status, working directory, and cost
-	cost := styles.Cost.Render(fmt.Sprintf("$%.4f", m.stats.TotalCost))
+	cost := styles.Cost.Render(fmt.Sprintf("$%.4f", m.displayCost()))
 	costWidth := lipgloss.Width(cost)
 	status := m.renderStatusWithWorkDir(w, costWidth)
 
@@ -763,27 +808,13 @@ func (m model) renderHeaderForWidth(w int) string {
 	)
 
 	// Line 2: Current bead (or idle message with backoff info) with elapsed time and turn count
-	var beadLine string
-	if m.currentBead != nil {
-		elapsed := formatDurationHuman(m.stats.CurrentDurationMs)
-		var beadText string
-		if m.currentSessionTurns > 0 {
-			beadText = fmt.Sprintf("bead: %s - %s [%s, turn %d]",
-				m.currentBead.ID, m.currentBead.Title, elapsed, m.currentSessionTurns)
-		} else {
-			beadText = fmt.Sprintf("bead: %s - %s [%s]",
-				m.currentBead.ID, m.currentBead.Title, elapsed)
-		}
-		if len(beadText) > w {
-			beadText = beadText[:w-3] + "..."
-		}
-		beadLine = styles.Bead.Render(beadText)
-	} else {
-		beadLine = m.renderBlockedInfo(w)
-	}
+	beadLine := m.renderBeadLine(w)
 
 	// Line 3: Turns, total duration, and progress stats
 	turnsText := fmt.Sprintf("turns: %d", m.stats.TotalTurns)
+	if sched := m.scheduleText(); sched != "" {
+		turnsText += "  " + sched
+	}
 	totalDur := formatDurationHuman(m.stats.TotalDurationMs)
 	statsText := fmt.Sprintf("total: %s  completed: %d  failed: %d  abandoned: %d",
 		totalDur, m.stats.Completed, m.stats.Failed, m.stats.Abandoned)
@@ -801,6 +832,80 @@ func (m model) renderHeaderForWidth(w int) string {
 	return strings.Join([]string{statusLine, beadLine, statsLine}, "\n")
 }
 
+// scheduleText describes the drain schedule's window for the header, or
+// returns "" without a schedule.
+func (m model) scheduleText() string {
+	if m.schedule == nil {
+		return ""
+	}
+	status := schedule.Status{Open: m.schedule.Open, Window: m.schedule.Window, Next: m.schedule.Next}
+	return status.Describe(m.clock())
+}
+
+// renderBeadLine renders the header's bead line: the current bead with its
+// model, elapsed time and turn count, a per-worker summary when more than one
+// worker is configured, or the idle message with backoff info.
+func (m model) renderBeadLine(w int) string {
+	if len(m.workers) > 1 {
+		return m.renderWorkersLine(w)
+	}
+	if m.currentBead == nil {
+		return m.renderBlockedInfo(w)
+	}
+
+	details := []string{formatDurationHuman(m.stats.CurrentDurationMs)}
+	if m.currentBead.Model != "" {
+		details = append([]string{m.currentBead.Model}, details...)
+	}
+	if m.currentSessionTurns > 0 {
+		details = append(details, fmt.Sprintf("turn %d", m.currentSessionTurns))
+	}
+	beadText := fmt.Sprintf("bead: %s - %s [%s]",
+		m.currentBead.ID, m.currentBead.Title, strings.Join(details, ", "))
+	if len(beadText) > w {
+		beadText = beadText[:w-3] + "..."
+	}
+	return styles.Bead.Render(beadText)
+}
+
+// displayCost returns the header cost: completed iterations plus the live
+// estimates of sessions still running.
+func (m model) displayCost() float64 {
+	total := m.stats.TotalCost
+	for _, sessions := range m.liveCost {
+		for _, cost := range sessions {
+			total += cost
+		}
+	}
+	return total
+}
+
+// renderWorkersLine renders a compact per-worker summary in place of the
+// bead line when more than one worker is configured.
+func (m model) renderWorkersLine(w int) string {
+	parts := make([]string, 0, len(m.workers))
+	for _, wk := range m.workers {
+		if wk.BeadID == "" {
+			parts = append(parts, fmt.Sprintf("%d: idle", wk.ID))
+			continue
+		}
+		details := []string{formatDurationHuman(time.Since(wk.StartedAt).Milliseconds())}
+		if wk.Model != "" {
+			details = append([]string{wk.Model}, details...)
+		}
+		if wk.Turns > 0 {
+			details = append(details, fmt.Sprintf("turn %d", wk.Turns))
+		}
+		parts = append(parts, fmt.Sprintf("%d: %s [%s]", wk.ID, wk.BeadID, strings.Join(details, ", ")))
+	}
+
+	text := "workers: " + strings.Join(parts, "  ")
+	if len(text) > w {
+		text = text[:max(0, w-3)] + "..."
+	}
+	return styles.Bead.Render(text)
+}
+
 // renderDividerForWidth renders a divider for a specific width.
 func (m model) renderDividerForWidth(w int) string {
 	return styles.Divider.Render(strings.Repeat("─", w))
@@ -858,7 +963,7 @@ func (m model) renderHeader() string {
 	w := safeWidth(m.width - 4) // Account for container borders
 
 	// Line 1: Status, working directory, and cost
-	cost := styles.Cost.Render(fmt.Sprintf("$%.4f", m.stats.TotalCost))
+	cost := styles.Cost.Render(fmt.Sprintf("$%.4f", m.displayCost()))
 	costWidth := lipgloss.Width(cost)
 	status := m.renderStatusWithWorkDir(w, costWidth)
 
@@ -870,27 +975,13 @@ func (m model) renderHeader() string {
 	)
 
 	// Line 2: Current bead (or idle message with backoff info) with elapsed time and turn count
-	var beadLine string
-	if m.currentBead != nil {
-		elapsed := formatDurationHuman(m.stats.CurrentDurationMs)
-		var beadText string
-		if m.currentSessionTurns > 0 {
-			beadText = fmt.Sprintf("bead: %s - %s [%s, turn %d]",
-				m.currentBead.ID, m.currentBead.Title, elapsed, m.currentSessionTurns)
-		} else {
-			beadText = fmt.Sprintf("bead: %s - %s [%s]",
-				m.currentBead.ID, m.currentBead.Title, elapsed)
-		}
-		if len(beadText) > w {
-			beadText = beadText[:w-3] + "..."
-		}
-		beadLine = styles.Bead.Render(beadText)
-	} else {
-		beadLine = m.renderBlockedInfo(w)
-	}
+	beadLine := m.renderBeadLine(w)
 
 	// Line 3: Turns, total duration, and progress stats
 	turnsText := fmt.Sprintf("turns: %d", m.stats.TotalTurns)
+	if sched := m.scheduleText(); sched != "" {
+		turnsText += "  " + sched
+	}
 	totalDur := formatDurationHuman(m.stats.TotalDurationMs)
 	statsText := fmt.Sprintf("total: %s  completed: %d  failed: %d  abandoned: %d",
 		totalDur, m.stats.Completed, m.stats.Failed, m.stats.Abandoned)
@@ -939,6 +1030,9 @@ func (m model) renderStatusWithWorkDir(totalWidth, costWidth int) string {
 	}
 
 	result := style.Render(status)
+	if m.playback != nil {
+		result += styles.Footer.Render(" " + m.playbackStatus())
+	}
 	if m.epicID != "" {
 		result += styles.Footer.Render(fmt.Sprintf(" (epic: %s)", m.epicID))
 	} else if m.activeTopLevelID != "" {
@@ -1150,13 +1244,13 @@ func (m model) renderGlobalFooter(width int) string {
 	// Show different help based on focus
 	switch {
 	case m.isObserverFocused() && m.observerOpen:
-		help = "enter: ask  e/o/b: panels  tab: switch  esc: close  q: quit"
+		help = "enter: ask  e/o/b: panels  tab: switch  esc: close  " + m.quitHint()
 
 	case m.isGraphFocused() && m.graphOpen:
 		if m.graphPane.IsShowingDetail() {
-			help = "enter: fullscreen  esc: back  j/k: scroll  e/o/b: panels  tab: switch  q: quit"
+			help = "enter: fullscreen  esc: back  j/k: scroll  e/o/b: panels  tab: switch  " + m.quitHint()
 		} else {
-			help = "↑/↓/←/→: nav  d: density  a: view  R: refresh  e/o/b: panels  tab: switch  q: quit"
+			help = "↑/↓/←/→: nav  d: density  a: view  R: refresh  e/o/b: panels  tab: switch  " + m.quitHint()
 		}
 
 	default:
@@ -1172,12 +1266,14 @@ func (m model) renderEventsFooter() string {
 	var parts []string
 
 	// Pause/resume/retry based on status
-	switch m.status {
-	case "paused", "pausing...", "pausing":
+	switch {
+	case m.playback != nil:
+		parts = append(parts, "space: play/pause", "[/]: seek 1m", "{/}: seek 10m")
+	case m.status == "paused" || m.status == "pausing..." || m.status == "pausing":
 		parts = append(parts, "r: resume")
-	case "stalled":
+	case m.status == "stalled":
 		parts = append(parts, "R: retry", "r: skip")
-	case "stopped":
+	case m.status == "stopped":
 		// No pause/resume for stopped state
 	default:
 		parts = append(parts, "p: pause")
@@ -1204,12 +1300,24 @@ func (m model) renderEventsFooter() string {
 		parts = append(parts, "tab: switch")
 	}
 
+	if m.onStop != nil && m.status != "stopped" {
+		parts = append(parts, "S: stop")
+	}
+
 	// Common controls
-	parts = append(parts, "q: quit", "↑/↓: scroll")
+	parts = append(parts, m.quitHint(), "↑/↓: scroll")
 
 	return strings.Join(parts, "  ")
 }
 
+// quitHint returns the footer hint for the quit key.
+func (m model) quitHint() string {
+	if m.detach {
+		return "q: detach"
+	}
+	return "q: quit"
+}
+
 // formatDurationShort formats a time.Duration to compact form like "30s", "5m", "1h".
 // Returns "now" for negative or near-zero durations.
 func formatDurationShort(d time.Duration) string {
@@ -1291,7 +1399,8 @@ func StyleForEvent(event events.Event) lipgloss.Style {
 		return styles.Tool
 	case *events.ClaudeTextEvent:
 		return styles.Session
-	case *events.SessionStartEvent, *events.SessionEndEvent, *events.SessionTimeoutEvent:
+	case *events.SessionStartEvent, *events.SessionEndEvent, *events.SessionTimeoutEvent,
+		*events.SessionCostEvent:
 		return styles.Session
 	case *events.IterationStartEvent, *events.IterationEndEvent, *events.TurnCompleteEvent:
 		return styles.BeadStatus
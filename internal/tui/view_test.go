@@ -1183,3 +1183,92 @@ func TestRenderHeader_WithBackoff(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderHeader_WithWorkers(t *testing.T) {
+	t.Run("shows per-worker summary when multiple workers", func(t *testing.T) {
+		m := model{
+			width:  120,
+			height: 25,
+			status: "working",
+			workers: []viewmodel.WorkerInfo{
+				{ID: 1, BeadID: "bd-aaa", Turns: 3, StartedAt: time.Now()},
+				{ID: 2, BeadID: "bd-bbb", StartedAt: time.Now()},
+				{ID: 3},
+			},
+		}
+
+		result := m.renderHeader()
+
+		if !strings.Contains(result, "1: bd-aaa") {
+			t.Error("header should show worker 1 bead")
+		}
+		if !strings.Contains(result, "turn 3") {
+			t.Error("header should show worker 1 turn count")
+		}
+		if !strings.Contains(result, "2: bd-bbb") {
+			t.Error("header should show worker 2 bead")
+		}
+		if !strings.Contains(result, "3: idle") {
+			t.Error("header should show worker 3 as idle")
+		}
+	})
+
+	t.Run("single worker uses standard bead line", func(t *testing.T) {
+		m := model{
+			width:       80,
+			height:      25,
+			status:      "working",
+			currentBead: &beadInfo{ID: "bd-one", Title: "Only bead"},
+			workers:     []viewmodel.WorkerInfo{{ID: 1, BeadID: "bd-one"}},
+		}
+
+		result := m.renderHeader()
+
+		if !strings.Contains(result, "bead: bd-one - Only bead") {
+			t.Error("header should show standard bead line")
+		}
+		if strings.Contains(result, "workers:") {
+			t.Error("header should not show workers summary for a single worker")
+		}
+	})
+}
+
+func TestRenderHeader_WithSchedule(t *testing.T) {
+	t.Run("shows the open window", func(t *testing.T) {
+		m := model{
+			width:    120,
+			height:   25,
+			status:   "working",
+			schedule: &viewmodel.ScheduleInfo{Open: true, Window: "sat,sun", Next: time.Now().Add(90*time.Minute + 30*time.Second)},
+		}
+
+		result := m.renderHeader()
+
+		if !strings.Contains(result, "in window sat,sun, closes in 1h31m") {
+			t.Errorf("header should show the open window, got:\n%s", result)
+		}
+	})
+
+	t.Run("shows the time until the next window", func(t *testing.T) {
+		m := model{
+			width:    120,
+			height:   25,
+			status:   "paused",
+			schedule: &viewmodel.ScheduleInfo{Next: time.Now().Add(5*time.Hour + 30*time.Second)},
+		}
+
+		result := m.renderHeader()
+
+		if !strings.Contains(result, "outside schedule, opens in 5h1m") {
+			t.Errorf("header should show the next window, got:\n%s", result)
+		}
+	})
+
+	t.Run("omitted without a schedule", func(t *testing.T) {
+		m := model{width: 120, height: 25, status: "idle"}
+
+		if result := m.renderHeader(); strings.Contains(result, "schedule") || strings.Contains(result, "window") {
+			t.Errorf("header should not mention a schedule, got:\n%s", result)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+// Package verify runs the configured verification commands after a bead is
+// closed, so a close is only accepted once the checks pass.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/exec"
+)
+
+// MaxOutputBytes limits how much command output a Failure keeps. The tail is
+// kept, since test runners and linters report their summary last.
+const MaxOutputBytes = 4000
+
+// script runs one command in a directory with stderr folded into stdout, as
+// CommandRunner only returns stdout. Arguments: $1 directory, $2 command.
+const script = `cd "$1" && exec sh -c "$2" 2>&1`
+
+// Failure describes a verification command that did not succeed.
+type Failure struct {
+	Command string // command as configured
+	Output  string // combined stdout and stderr, truncated to MaxOutputBytes
+	Err     error  // exit error or timeout
+}
+
+// Error implements error.
+func (f *Failure) Error() string {
+	return fmt.Sprintf("verify command %q failed: %v", f.Command, f.Err)
+}
+
+// Verifier runs verification commands.
+type Verifier struct {
+	runner   exec.CommandRunner
+	commands []string
+	timeout  time.Duration
+}
+
+// New creates a Verifier for the configured commands.
+func New(runner exec.CommandRunner, cfg config.VerifyConfig) *Verifier {
+	return &Verifier{
+		runner:   runner,
+		commands: cfg.Commands,
+		timeout:  cfg.Timeout,
+	}
+}
+
+// Commands returns the configured commands.
+func (v *Verifier) Commands() []string {
+	return v.commands
+}
+
+// Run executes each command in dir (the current directory if empty), stopping
+// at the first one that fails. It returns a *Failure for a failed command.
+func (v *Verifier) Run(ctx context.Context, dir string) error {
+	if dir == "" {
+		dir = "."
+	}
+
+	for _, command := range v.commands {
+		cmdCtx := ctx
+		var cancel context.CancelFunc
+		if v.timeout > 0 {
+			cmdCtx, cancel = context.WithTimeout(ctx, v.timeout)
+		}
+		out, err := v.runner.Run(cmdCtx, "sh", "-c", script, "atari-verify", dir, command)
+		if err != nil && cmdCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", v.timeout)
+		}
+		if cancel != nil {
+			cancel()
+		}
+		if err != nil {
+			return &Failure{
+				Command: command,
+				Output:  tail(strings.TrimSpace(string(out)), MaxOutputBytes),
+				Err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// tail returns at most n bytes from the end of s.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
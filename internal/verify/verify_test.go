@@ -0,0 +1,101 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/testutil"
+)
+
+func TestRun_AllPass(t *testing.T) {
+	dir := t.TempDir()
+	v := New(cmdexec.NewExecRunner(), config.VerifyConfig{
+		Commands: []string{"true", "touch ran"},
+		Timeout:  time.Minute,
+	})
+
+	if err := v.Run(context.Background(), dir); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	// Commands run in the given directory
+	if _, err := os.Stat(filepath.Join(dir, "ran")); err != nil {
+		t.Errorf("expected command to run in %s: %v", dir, err)
+	}
+}
+
+func TestRun_FailureCapturesOutput(t *testing.T) {
+	dir := t.TempDir()
+	v := New(cmdexec.NewExecRunner(), config.VerifyConfig{
+		Commands: []string{"echo building", "echo FAIL: TestFoo >&2; exit 1", "touch never"},
+		Timeout:  time.Minute,
+	})
+
+	err := v.Run(context.Background(), dir)
+	var f *Failure
+	if !errors.As(err, &f) {
+		t.Fatalf("expected *Failure, got %v", err)
+	}
+	if f.Command != "echo FAIL: TestFoo >&2; exit 1" {
+		t.Errorf("Command = %q", f.Command)
+	}
+	if !strings.Contains(f.Output, "FAIL: TestFoo") {
+		t.Errorf("expected stderr in output, got %q", f.Output)
+	}
+	// Commands after the failure are skipped
+	if _, err := os.Stat(filepath.Join(dir, "never")); err == nil {
+		t.Error("expected later commands to be skipped")
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	v := New(cmdexec.NewExecRunner(), config.VerifyConfig{
+		Commands: []string{"sleep 5"},
+		Timeout:  50 * time.Millisecond,
+	})
+
+	err := v.Run(context.Background(), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRun_UsesRunner(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	var gotArgs []string
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		gotArgs = args
+		return []byte(strings.Repeat("x", MaxOutputBytes+100)), errors.New("exit status 2"), true
+	}
+	v := New(runner, config.VerifyConfig{Commands: []string{"make check"}})
+
+	err := v.Run(context.Background(), "")
+	var f *Failure
+	if !errors.As(err, &f) {
+		t.Fatalf("expected *Failure, got %v", err)
+	}
+	if len(gotArgs) != 5 || gotArgs[3] != "." || gotArgs[4] != "make check" {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+	if len(f.Output) != MaxOutputBytes+len("...") || !strings.HasPrefix(f.Output, "...") {
+		t.Errorf("expected output truncated to the last %d bytes, got %d", MaxOutputBytes, len(f.Output))
+	}
+}
+
+func TestRun_NoCommands(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	v := New(runner, config.VerifyConfig{})
+
+	if err := v.Run(context.Background(), ""); err != nil {
+		t.Errorf("Run() error: %v", err)
+	}
+	if calls := runner.GetCalls(); len(calls) != 0 {
+		t.Errorf("expected no calls, got %v", calls)
+	}
+}
@@ -11,6 +11,25 @@ type BlockedBeadInfo struct {
 	LastError    string        // Error message from last attempt
 }
 
+// WorkerInfo represents the progress of a single worker in the pool.
+type WorkerInfo struct {
+	ID              int       // Worker number (1-based)
+	BeadID          string    // ID of the bead being worked on (empty if idle)
+	BeadTitle       string    // Title of the bead being worked on
+	StartedAt       time.Time // When the worker started the current bead
+	Turns           int       // Turns completed in the worker's current session
+	CostUSD         float64   // Estimated cost of the worker's running session
+	Model           string    // Model selected for the worker's bead (empty = agent default)
+	SelectedBecause string    // Why the selection strategy chose the worker's bead
+}
+
+// ScheduleInfo describes the drain schedule's current window.
+type ScheduleInfo struct {
+	Open   bool      // A schedule window is open
+	Window string    // The open window as configured (empty when closed)
+	Next   time.Time // When the window closes or the next one opens (zero = never)
+}
+
 // TUIStats provides a snapshot of controller statistics for TUI display.
 type TUIStats struct {
 	Completed      int              // Number of successfully completed beads
@@ -20,12 +39,14 @@ type TUIStats struct {
 	CurrentBead    string           // ID of bead being worked on (empty if idle)
 	CurrentTurns   int              // Turns completed in current session
 	TopBlockedBead *BlockedBeadInfo // Bead with shortest remaining backoff (nil if none)
+	Workers        []WorkerInfo     // Per-worker progress (one entry per configured worker)
+	Schedule       *ScheduleInfo    // Schedule window (nil if no schedule is configured)
 
 	// Stall info (populated when controller is in stalled state)
 	StalledBeadID    string    // ID of the stalled bead (empty if not stalled)
 	StalledBeadTitle string    // Title of the stalled bead
 	StallReason      string    // Reason for the stall
 	StalledAt        time.Time // When the stall occurred
-	StallType        string    // "abandoned" or "review"
+	StallType        string    // "abandoned", "review", or "budget"
 	CreatedBeads     []string  // bead IDs created during session (for review stalls)
 }
@@ -0,0 +1,66 @@
+package workqueue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+	"github.com/npratt/atari/internal/events"
+)
+
+// FailureClass re-exports the events type for convenience.
+type FailureClass = events.FailureClass
+
+// Re-export FailureClass constants.
+const (
+	FailureRateLimit = events.FailureRateLimit
+	FailureAuth      = events.FailureAuth
+	FailureTimeout   = events.FailureTimeout
+	FailureTransient = events.FailureTransient
+	FailureCrash     = events.FailureCrash
+	FailureGaveUp    = events.FailureGaveUp
+	FailureOther     = events.FailureOther
+)
+
+// Failure is an error classified for RecordFailure.
+type Failure struct {
+	Class FailureClass
+	Err   error
+}
+
+// Classify tags err with the class of failure it represents.
+func Classify(class FailureClass, err error) error {
+	return &Failure{Class: class, Err: err}
+}
+
+func (f *Failure) Error() string { return f.Err.Error() }
+func (f *Failure) Unwrap() error { return f.Err }
+
+// ClassOf returns the class err was tagged with by Classify, or
+// FailureOther for an unclassified error.
+func ClassOf(err error) FailureClass {
+	var f *Failure
+	if errors.As(err, &f) {
+		return f.Class
+	}
+	return FailureOther
+}
+
+// RetryPolicy returns the configured retry policy for a failure class.
+func (m *Manager) RetryPolicy(class FailureClass) config.RetryPolicy {
+	return m.config.Retry.Policy(string(class))
+}
+
+// backoffFor returns how long a failed bead waits before it is retried,
+// following the retry policy for the class of its latest failure.
+func (m *Manager) backoffFor(h *BeadHistory) time.Duration {
+	policy := m.RetryPolicy(h.FailureClass)
+	switch policy.Action {
+	case config.RetryNow, config.RetryPause:
+		return 0
+	case config.RetryLongBackoff:
+		return policy.Wait
+	default:
+		return m.calculateBackoff(h.Attempts)
+	}
+}
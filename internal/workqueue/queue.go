@@ -63,25 +63,66 @@ type Manager struct {
 	config         *config.Config
 	client         brclient.WorkQueueClient
 	history        map[string]*BeadHistory
-	activeTopLevel string // Runtime state: currently active top-level item ID
+	activeTopLevel string                  // Runtime state: currently active top-level item ID
+	inFlight       map[string]*BeadHistory // Beads handed out by Next and not yet released, with their history before selection
+	chosen         map[string]string       // Why each in-flight bead was selected
+	strategy       SelectionStrategy       // Orders eligible beads, and top-level items in top-level mode
+	costs          CostEstimator           // Expected bead costs for the cost-aware strategies
 	logger         *slog.Logger
 	mu             sync.RWMutex
 }
 
 // New creates a Manager with the given config and br client.
-// If logger is nil, slog.Default() is used.
+// If logger is nil, slog.Default() is used. An invalid selection mode falls
+// back to priority order; check it with NewStrategy first to report it.
 func New(cfg *config.Config, client brclient.WorkQueueClient, logger *slog.Logger) *Manager {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	strategy, err := NewStrategy(cfg.WorkQueue.SelectionMode, cfg.WorkQueue.Selection)
+	if err != nil {
+		logger.Warn("invalid selection mode, using priority order", "error", err)
+		strategy = PriorityStrategy{}
+	}
 	return &Manager{
-		config:  cfg,
-		client:  client,
-		history: make(map[string]*BeadHistory),
-		logger:  logger,
+		config:   cfg,
+		client:   client,
+		history:  make(map[string]*BeadHistory),
+		inFlight: make(map[string]*BeadHistory),
+		chosen:   make(map[string]string),
+		strategy: strategy,
+		logger:   logger,
 	}
 }
 
+// SetCostEstimator sets where the cost-aware strategies get expected bead
+// costs from. Without one, those beads are ordered by priority.
+func (m *Manager) SetCostEstimator(costs CostEstimator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.costs = costs
+}
+
+// Strategy returns the strategy that orders beads.
+func (m *Manager) Strategy() SelectionStrategy {
+	return m.strategy
+}
+
+// Explain returns why an in-flight bead was selected, or "" if it is not
+// in flight.
+func (m *Manager) Explain(beadID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.chosen[beadID]
+}
+
+// rankContext returns the inputs for a strategy's ranking.
+func (m *Manager) rankContext() RankContext {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return RankContext{Now: time.Now(), Costs: m.costs}
+}
+
 // Poll executes br ready --json and returns available beads.
 // It applies the configured label filter.
 // Returns nil slice (not error) when no work is available.
@@ -104,7 +145,7 @@ func (m *Manager) Poll(ctx context.Context) ([]Bead, error) {
 }
 
 // Next polls for available work, filters by history, and returns the
-// highest-priority eligible bead. Returns nil if no work is available
+// eligible bead the selection strategy ranks first. Returns nil if no work is available
 // or all beads are in backoff. The SelectionReason indicates why no bead
 // was selected when the result is nil.
 func (m *Manager) Next(ctx context.Context) (*Bead, SelectionReason, error) {
@@ -130,25 +171,10 @@ func (m *Manager) Next(ctx context.Context) (*Bead, SelectionReason, error) {
 		return nil, result.reason(), nil
 	}
 
-	// Sort by priority (lower = higher priority), then by created_at
-	sort.Slice(result.eligible, func(i, j int) bool {
-		if result.eligible[i].Priority != result.eligible[j].Priority {
-			return result.eligible[i].Priority < result.eligible[j].Priority
-		}
-		return result.eligible[i].CreatedAt.Before(result.eligible[j].CreatedAt)
-	})
-
+	why := m.strategy.Rank(result.eligible, m.rankContext())
 	selected := result.eligible[0]
 
-	// Mark as working and increment attempts
-	m.mu.Lock()
-	if m.history[selected.ID] == nil {
-		m.history[selected.ID] = &BeadHistory{ID: selected.ID}
-	}
-	m.history[selected.ID].Status = HistoryWorking
-	m.history[selected.ID].Attempts++
-	m.history[selected.ID].LastAttempt = time.Now()
-	m.mu.Unlock()
+	m.claim(selected.ID, why)
 
 	return &selected, ReasonSuccess, nil
 }
@@ -179,7 +205,7 @@ func (r *filterResult) reason() SelectionReason {
 	return ReasonNoReady
 }
 
-// filterEligible returns beads that are not completed, abandoned, in backoff, or have excluded labels.
+// filterEligible returns beads that are not completed, abandoned, in backoff, in flight, or have excluded labels.
 // If epicDescendants is non-nil, only beads in that set are considered.
 // Also tracks skip reasons to determine why selection failed.
 func (m *Manager) filterEligible(beads []Bead, epicDescendants map[string]bool) filterResult {
@@ -190,58 +216,100 @@ func (m *Manager) filterEligible(beads []Bead, epicDescendants map[string]bool)
 	now := time.Now()
 
 	for _, bead := range beads {
-		// Skip epics - they are containers, not work items
-		if bead.IssueType == "epic" {
-			continue
-		}
-
 		// If epic filter is active, skip beads not in descendant set
 		if epicDescendants != nil && !epicDescendants[bead.ID] {
 			continue
 		}
 
-		// Skip beads with excluded labels
-		if m.hasExcludedLabel(bead.Labels) {
-			continue
-		}
-
-		history := m.history[bead.ID]
-		if history == nil {
-			// Never seen before - eligible
+		switch kind, _ := m.skipReason(bead, now); kind {
+		case notSkipped:
 			result.eligible = append(result.eligible, bead)
-			continue
+		case skipMaxFailures:
+			history := m.history[bead.ID]
+			// Log only on first skip (status hasn't been marked abandoned yet)
+			m.logger.Warn("bead hit max failures, skipping",
+				"bead_id", bead.ID,
+				"attempts", history.Attempts,
+				"max_failures", m.config.Backoff.MaxFailures,
+				"last_error", history.LastError)
+			result.skippedMaxFailed++
+		case skipBackoff:
+			result.skippedBackoff++
 		}
+	}
 
-		// Skip completed, abandoned, or skipped beads
-		if history.Status == HistoryCompleted || history.Status == HistoryAbandoned || history.Status == HistorySkipped {
-			continue
-		}
+	return result
+}
 
-		// Check failed beads for backoff and max failures
-		if history.Status == HistoryFailed {
-			// Check if we've hit max failures
-			if m.config.Backoff.MaxFailures > 0 && history.Attempts >= m.config.Backoff.MaxFailures {
-				// Log only on first skip (status hasn't been marked abandoned yet)
-				m.logger.Warn("bead hit max failures, skipping",
-					"bead_id", bead.ID,
-					"attempts", history.Attempts,
-					"max_failures", m.config.Backoff.MaxFailures,
-					"last_error", history.LastError)
-				result.skippedMaxFailed++
-				continue
-			}
-			// Check if still in backoff period
-			backoff := m.calculateBackoff(history.Attempts)
-			if now.Sub(history.LastAttempt) < backoff {
-				result.skippedBackoff++
-				continue
+// skipKind classifies why a ready bead is not eligible for selection.
+type skipKind int
+
+const (
+	notSkipped skipKind = iota
+	skipOther
+	skipBackoff
+	skipMaxFailures
+)
+
+// SkipReason explains why a ready bead would not be selected, or returns ""
+// if it is eligible. It does not consider the epic filter.
+func (m *Manager) SkipReason(bead Bead) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, why := m.skipReason(bead, time.Now())
+	return why
+}
+
+// skipReason classifies and explains why a ready bead is not eligible at
+// now. Callers hold m.mu.
+func (m *Manager) skipReason(bead Bead, now time.Time) (skipKind, string) {
+	// Skip epics - they are containers, not work items
+	if bead.IssueType == "epic" {
+		return skipOther, "epics are closed when their beads are, not worked on"
+	}
+
+	// Skip beads with excluded labels
+	if label := m.excludedLabel(bead.Labels); label != "" {
+		return skipOther, fmt.Sprintf("has excluded label %q", label)
+	}
+
+	// Skip beads another worker is already running
+	if _, ok := m.inFlight[bead.ID]; ok {
+		return skipOther, "already being worked on"
+	}
+
+	history := m.history[bead.ID]
+	if history == nil {
+		// Never seen before - eligible
+		return notSkipped, ""
+	}
+
+	// Skip completed, abandoned, or skipped beads
+	switch history.Status {
+	case HistoryCompleted:
+		return skipOther, "completed earlier"
+	case HistoryAbandoned:
+		return skipOther, fmt.Sprintf("abandoned after %d attempts", history.Attempts)
+	case HistorySkipped:
+		return skipOther, "skipped"
+	case HistoryFailed:
+		// Check if we've hit max failures
+		if m.config.Backoff.MaxFailures > 0 && history.Attempts >= m.config.Backoff.MaxFailures {
+			return skipMaxFailures, fmt.Sprintf("hit max failures (%d of %d)", history.Attempts, m.config.Backoff.MaxFailures)
+		}
+		// Check if still in backoff period
+		backoff := m.backoffFor(history)
+		if now.Sub(history.LastAttempt) < backoff {
+			why := fmt.Sprintf("in backoff until %s after %d failed attempts",
+				history.LastAttempt.Add(backoff).Format("15:04"), history.Attempts)
+			if history.FailureClass != "" {
+				why += fmt.Sprintf(" (last: %s)", history.FailureClass)
 			}
+			return skipBackoff, why
 		}
-
-		result.eligible = append(result.eligible, bead)
 	}
 
-	return result
+	return notSkipped, ""
 }
 
 // calculateBackoff returns the backoff duration for a given number of attempts.
@@ -264,17 +332,20 @@ func (m *Manager) calculateBackoff(attempts int) time.Duration {
 
 // hasExcludedLabel returns true if any of the bead's labels are in the exclude list.
 func (m *Manager) hasExcludedLabel(beadLabels []string) bool {
-	if len(m.config.WorkQueue.ExcludeLabels) == 0 {
-		return false
-	}
+	return m.excludedLabel(beadLabels) != ""
+}
+
+// excludedLabel returns the first of the bead's labels in the exclude list,
+// or "" if none are.
+func (m *Manager) excludedLabel(beadLabels []string) string {
 	for _, beadLabel := range beadLabels {
 		for _, excludeLabel := range m.config.WorkQueue.ExcludeLabels {
 			if beadLabel == excludeLabel {
-				return true
+				return beadLabel
 			}
 		}
 	}
-	return false
+	return ""
 }
 
 // fetchDescendants fetches all beads and builds a set of IDs that are descendants
@@ -370,11 +441,8 @@ func identifyTopLevelItems(beads []Bead) []Bead {
 		}
 	}
 
-	sort.Slice(topLevel, func(i, j int) bool {
-		if topLevel[i].Priority != topLevel[j].Priority {
-			return topLevel[i].Priority < topLevel[j].Priority
-		}
-		return topLevel[i].CreatedAt.Before(topLevel[j].CreatedAt)
+	sort.SliceStable(topLevel, func(i, j int) bool {
+		return byPriorityThenAge(topLevel[i], topLevel[j])
 	})
 
 	return topLevel
@@ -398,15 +466,21 @@ func hasReadyDescendants(topLevelID string, readyBeads []Bead, allBeads []Bead)
 	return false
 }
 
-// selectBestTopLevel finds the highest-priority top-level item that has ready work.
-// Returns empty string if no top-level item has ready descendants.
-func selectBestTopLevel(topLevelItems []Bead, readyBeads []Bead, allBeads []Bead) string {
+// selectBestTopLevel finds the top-level item with ready work that the
+// strategy ranks first. Returns empty string if no top-level item has ready
+// descendants.
+func selectBestTopLevel(topLevelItems []Bead, readyBeads []Bead, allBeads []Bead, strategy SelectionStrategy, rc RankContext) string {
+	var candidates []Bead
 	for _, item := range topLevelItems {
 		if hasReadyDescendants(item.ID, readyBeads, allBeads) {
-			return item.ID
+			candidates = append(candidates, item)
 		}
 	}
-	return ""
+	if len(candidates) == 0 {
+		return ""
+	}
+	strategy.Rank(candidates, rc)
+	return candidates[0].ID
 }
 
 // NextTopLevel selects the next bead using top-level selection mode.
@@ -415,10 +489,10 @@ func selectBestTopLevel(topLevelItems []Bead, readyBeads []Bead, allBeads []Bead
 //
 // Algorithm:
 // 1. If activeTopLevel is set and has ready descendants, use it
-// 2. Otherwise, identify all top-level items, pick highest priority with ready work
+// 2. Otherwise, identify all top-level items, pick the best ranked with ready work
 // 3. Filter beads to descendants of selected top-level item
 // 4. Apply existing filterEligible logic
-// 5. Return highest priority descendant
+// 5. Return the descendant the selection strategy ranks first
 //
 // The SelectionReason indicates why no bead was selected when the result is nil.
 func (m *Manager) NextTopLevel(ctx context.Context) (*Bead, SelectionReason, error) {
@@ -455,7 +529,7 @@ func (m *Manager) NextTopLevel(ctx context.Context) (*Bead, SelectionReason, err
 
 	// Select new top-level item
 	topLevelItems := identifyTopLevelItems(allBeads)
-	newTopLevel := selectBestTopLevel(topLevelItems, readyBeads, allBeads)
+	newTopLevel := selectBestTopLevel(topLevelItems, readyBeads, allBeads, m.strategy, m.rankContext())
 	if newTopLevel == "" {
 		// No top-level items with ready work; fall back to global selection
 		// This handles orphaned beads that somehow aren't under any top-level
@@ -471,7 +545,7 @@ func (m *Manager) NextTopLevel(ctx context.Context) (*Bead, SelectionReason, err
 }
 
 // selectFromTopLevel filters ready beads to descendants of the given top-level
-// and returns the highest priority eligible bead.
+// and returns the eligible bead the selection strategy ranks first.
 func (m *Manager) selectFromTopLevel(topLevelID string, readyBeads []Bead, allBeads []Bead) (*Bead, SelectionReason, error) {
 	var epicDescendants map[string]bool
 	if topLevelID != "" {
@@ -483,25 +557,13 @@ func (m *Manager) selectFromTopLevel(topLevelID string, readyBeads []Bead, allBe
 		return nil, result.reason(), nil
 	}
 
-	// Sort by priority (lower = higher priority), then by created_at
-	sort.Slice(result.eligible, func(i, j int) bool {
-		if result.eligible[i].Priority != result.eligible[j].Priority {
-			return result.eligible[i].Priority < result.eligible[j].Priority
-		}
-		return result.eligible[i].CreatedAt.Before(result.eligible[j].CreatedAt)
-	})
-
+	why := m.strategy.Rank(result.eligible, m.rankContext())
+	if topLevelID != "" {
+		why = fmt.Sprintf("working through %s; %s", topLevelID, why)
+	}
 	selected := result.eligible[0]
 
-	// Mark as working and increment attempts
-	m.mu.Lock()
-	if m.history[selected.ID] == nil {
-		m.history[selected.ID] = &BeadHistory{ID: selected.ID}
-	}
-	m.history[selected.ID].Status = HistoryWorking
-	m.history[selected.ID].Attempts++
-	m.history[selected.ID].LastAttempt = time.Now()
-	m.mu.Unlock()
+	m.claim(selected.ID, why)
 
 	return &selected, ReasonSuccess, nil
 }
@@ -578,6 +640,80 @@ func (m *Manager) HasEligibleReadyDescendants(ctx context.Context, topLevelID st
 	return len(result.eligible) > 0, nil
 }
 
+// claim marks a selected bead as working, increments its attempts, and
+// records it as in flight along with why it was selected.
+func (m *Manager) claim(beadID, why string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claimLocked(beadID, why)
+}
+
+func (m *Manager) claimLocked(beadID, why string) {
+	var prev *BeadHistory
+	if h := m.history[beadID]; h != nil {
+		snapshot := *h
+		prev = &snapshot
+	} else {
+		m.history[beadID] = &BeadHistory{ID: beadID}
+	}
+	m.history[beadID].Status = HistoryWorking
+	m.history[beadID].Attempts++
+	m.history[beadID].LastAttempt = time.Now()
+	m.inFlight[beadID] = prev
+	m.chosen[beadID] = why
+}
+
+// Claim marks a bead chosen by the caller, rather than by Next or
+// NextTopLevel, as being worked on, with why it was chosen. It returns false
+// if the bead is already in flight. Release or Unclaim it as for a selected
+// bead.
+func (m *Manager) Claim(beadID, why string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, busy := m.inFlight[beadID]; busy {
+		return false
+	}
+	m.claimLocked(beadID, why)
+	return true
+}
+
+// Unclaim reverses the selection of a bead that was never worked on,
+// restoring its history to what it was before Next or NextTopLevel returned it.
+func (m *Manager) Unclaim(beadID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prev, ok := m.inFlight[beadID]
+	if !ok {
+		return
+	}
+	delete(m.inFlight, beadID)
+	delete(m.chosen, beadID)
+	if prev == nil {
+		delete(m.history, beadID)
+	} else {
+		m.history[beadID] = prev
+	}
+}
+
+// Release marks a bead returned by Next or NextTopLevel as no longer in flight,
+// making it selectable again (subject to history). Call it once the worker
+// running the bead has finished, regardless of outcome.
+func (m *Manager) Release(beadID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.inFlight, beadID)
+	delete(m.chosen, beadID)
+}
+
+// InFlight returns the number of beads currently handed out to workers.
+func (m *Manager) InFlight() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.inFlight)
+}
+
 // RecordSuccess marks a bead as completed.
 func (m *Manager) RecordSuccess(beadID string) {
 	m.mu.Lock()
@@ -589,8 +725,11 @@ func (m *Manager) RecordSuccess(beadID string) {
 	m.history[beadID].Status = HistoryCompleted
 }
 
-// RecordFailure marks a bead as failed with the given error.
-// If the bead has exceeded max failures, it will be marked as abandoned.
+// RecordFailure marks a bead as failed with the given error, recording the
+// class it was tagged with by Classify. The class's retry policy decides
+// when the bead is retried: an abandon policy, or reaching max failures,
+// marks it as abandoned. A failure that pauses the drain does not count as
+// an attempt or a failure.
 func (m *Manager) RecordFailure(beadID string, err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -601,15 +740,37 @@ func (m *Manager) RecordFailure(beadID string, err error) {
 	h := m.history[beadID]
 	h.LastError = err.Error()
 	h.LastAttempt = time.Now()
+	h.FailureClass = ClassOf(err)
+
+	policy := m.RetryPolicy(h.FailureClass)
+	if policy.Action == config.RetryPause {
+		if h.Attempts > 0 {
+			h.Attempts--
+		}
+	} else {
+		h.Failures++
+	}
 
 	// Check if we've exceeded max failures
-	if m.config.Backoff.MaxFailures > 0 && h.Attempts >= m.config.Backoff.MaxFailures {
+	if policy.Action == config.RetryAbandon ||
+		(m.config.Backoff.MaxFailures > 0 && h.Attempts >= m.config.Backoff.MaxFailures) {
 		h.Status = HistoryAbandoned
 	} else {
 		h.Status = HistoryFailed
 	}
 }
 
+// RecordModel records the model selected for a bead's latest attempt.
+func (m *Manager) RecordModel(beadID, model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.history[beadID] == nil {
+		m.history[beadID] = &BeadHistory{ID: beadID}
+	}
+	m.history[beadID].Model = model
+}
+
 // RecordSkipped marks a bead as skipped (user chose to move past it).
 func (m *Manager) RecordSkipped(beadID string) {
 	m.mu.Lock()
@@ -621,6 +782,22 @@ func (m *Manager) RecordSkipped(beadID string) {
 	m.history[beadID].Status = HistorySkipped
 }
 
+// RecordStopped records an attempt that atari cut short, such as at a budget
+// limit, and skips the bead. The error is kept, but unlike RecordFailure the
+// attempt does not count as a failure.
+func (m *Manager) RecordStopped(beadID string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.history[beadID] == nil {
+		m.history[beadID] = &BeadHistory{ID: beadID}
+	}
+	h := m.history[beadID]
+	h.LastError = err.Error()
+	h.LastAttempt = time.Now()
+	h.Status = HistorySkipped
+}
+
 // ResetHistory clears the history for a bead, allowing it to be retried.
 func (m *Manager) ResetHistory(beadID string) {
 	m.mu.Lock()
@@ -638,6 +815,7 @@ func (m *Manager) ResetBead(beadID string) {
 	if h := m.history[beadID]; h != nil {
 		h.Status = HistoryPending
 		h.Attempts = 0
+		h.Failures = 0
 		h.LastAttempt = time.Time{}
 		h.LastError = ""
 	}
@@ -667,7 +845,7 @@ func (m *Manager) Stats() QueueStats {
 			stats.Completed++
 		case HistoryFailed:
 			stats.Failed++
-			backoff := m.calculateBackoff(h.Attempts)
+			backoff := m.backoffFor(h)
 			if now.Sub(h.LastAttempt) < backoff {
 				stats.InBackoff++
 			}
@@ -728,7 +906,7 @@ func (m *Manager) GetBeadState(beadID string) (status string, attempts int, inBa
 		status = "failed"
 		// Check if in backoff
 		if !h.LastAttempt.IsZero() {
-			backoff := m.calculateBackoff(h.Attempts)
+			backoff := m.backoffFor(h)
 			elapsed := time.Since(h.LastAttempt)
 			inBackoff = elapsed < backoff
 		}
@@ -764,7 +942,7 @@ func (m *Manager) GetBlockedBeads() []viewmodel.BlockedBeadInfo {
 			continue
 		}
 
-		backoff := m.calculateBackoff(h.Attempts)
+		backoff := m.backoffFor(h)
 		elapsed := now.Sub(h.LastAttempt)
 		remaining := backoff - elapsed
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -497,6 +498,40 @@ func TestFilterEligible_ExcludesMaxFailures(t *testing.T) {
 	}
 }
 
+func TestSkipReason(t *testing.T) {
+	cfg := config.Default()
+	cfg.Backoff.MaxFailures = 3
+	cfg.Backoff.Initial = time.Hour
+	cfg.WorkQueue.ExcludeLabels = []string{"manual"}
+
+	m := New(cfg, newMockClient(), nil)
+	m.history["bd-done"] = &BeadHistory{ID: "bd-done", Status: HistoryCompleted, Attempts: 1}
+	m.history["bd-backoff"] = &BeadHistory{ID: "bd-backoff", Status: HistoryFailed, Attempts: 2, LastAttempt: time.Now()}
+	m.history["bd-maxed"] = &BeadHistory{ID: "bd-maxed", Status: HistoryFailed, Attempts: 3, LastAttempt: time.Now()}
+	m.Claim("bd-busy", "test")
+
+	tests := []struct {
+		bead Bead
+		want string
+	}{
+		{Bead{ID: "bd-new"}, ""},
+		{Bead{ID: "bd-epic", IssueType: "epic"}, "epics"},
+		{Bead{ID: "bd-manual", Labels: []string{"manual"}}, `excluded label "manual"`},
+		{Bead{ID: "bd-busy"}, "already being worked on"},
+		{Bead{ID: "bd-done"}, "completed earlier"},
+		{Bead{ID: "bd-backoff"}, "in backoff until"},
+		{Bead{ID: "bd-maxed"}, "max failures (3 of 3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bead.ID, func(t *testing.T) {
+			got := m.SkipReason(tt.bead)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("SkipReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNext_ReturnsHighestPriority(t *testing.T) {
 	mock := newMockClient()
 	mock.ReadyResponse = []brclient.Bead{
@@ -751,6 +786,74 @@ func TestNext_IncrementsAttempts(t *testing.T) {
 	}
 }
 
+func TestNext_SkipsInFlight(t *testing.T) {
+	mock := newMockClient()
+	mock.ReadyResponse = []brclient.Bead{
+		{ID: "bd-001", Title: "First", Status: "open", Priority: 1},
+		{ID: "bd-002", Title: "Second", Status: "open", Priority: 2},
+	}
+
+	cfg := config.Default()
+	m := New(cfg, mock, nil)
+
+	first, _, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first == nil || first.ID != "bd-001" {
+		t.Fatalf("expected bd-001, got %v", first)
+	}
+
+	second, _, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second == nil || second.ID != "bd-002" {
+		t.Fatalf("expected bd-002 while bd-001 is in flight, got %v", second)
+	}
+	if m.InFlight() != 2 {
+		t.Errorf("expected 2 in flight, got %d", m.InFlight())
+	}
+
+	third, reason, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if third != nil {
+		t.Errorf("expected nil bead when all ready beads are in flight, got %s", third.ID)
+	}
+	if reason != ReasonNoReady {
+		t.Errorf("expected ReasonNoReady, got %v", reason)
+	}
+}
+
+func TestRelease_MakesBeadSelectable(t *testing.T) {
+	mock := newMockClient()
+	mock.ReadyResponse = []brclient.Bead{
+		{ID: "bd-001", Title: "Only", Status: "open", Priority: 1},
+	}
+
+	cfg := config.Default()
+	m := New(cfg, mock, nil)
+
+	if _, _, err := m.Next(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m.Release("bd-001")
+	if m.InFlight() != 0 {
+		t.Errorf("expected 0 in flight after release, got %d", m.InFlight())
+	}
+
+	bead, _, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bead == nil || bead.ID != "bd-001" {
+		t.Errorf("expected bd-001 after release, got %v", bead)
+	}
+}
+
 func TestRecordSuccess(t *testing.T) {
 	cfg := config.Default()
 	m := New(cfg, newMockClient(), nil)
@@ -846,6 +949,84 @@ func TestRecordFailure_NotAbandonedBelowMax(t *testing.T) {
 	}
 }
 
+func TestRecordFailure_RetryPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		class         FailureClass
+		wantStatus    string
+		wantAttempts  int
+		wantInBackoff bool
+		wantFailures  int
+		transient     string // retry.transient action, if not the default
+	}{
+		{name: "crash backs off", class: FailureCrash, wantStatus: "failed", wantAttempts: 2, wantInBackoff: true, wantFailures: 2},
+		{name: "unclassified backs off", class: "", wantStatus: "failed", wantAttempts: 2, wantInBackoff: true, wantFailures: 2},
+		{name: "transient backs off", class: FailureTransient, wantStatus: "failed", wantAttempts: 2, wantInBackoff: true, wantFailures: 2},
+		{name: "transient retries now", class: FailureTransient, wantStatus: "failed", wantAttempts: 2, wantFailures: 2, transient: config.RetryNow},
+		{name: "timeout waits long", class: FailureTimeout, wantStatus: "failed", wantAttempts: 2, wantInBackoff: true, wantFailures: 2},
+		{name: "rate limit does not count", class: FailureRateLimit, wantStatus: "failed", wantAttempts: 1, wantFailures: 1},
+		{name: "gave up abandons", class: FailureGaveUp, wantStatus: "abandoned", wantAttempts: 2, wantFailures: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Backoff.MaxFailures = 5
+			cfg.Retry.GaveUp = config.RetryPolicy{Action: config.RetryAbandon}
+			if tt.transient != "" {
+				cfg.Retry.Transient = config.RetryPolicy{Action: tt.transient}
+			}
+			m := New(cfg, newMockClient(), nil)
+			m.history["bd-001"] = &BeadHistory{ID: "bd-001", Status: HistoryWorking, Attempts: 2, Failures: 1}
+
+			err := errors.New("session failed")
+			if tt.class != "" {
+				err = Classify(tt.class, err)
+			}
+			m.RecordFailure("bd-001", err)
+
+			wantClass := tt.class
+			if wantClass == "" {
+				wantClass = FailureOther
+			}
+			if got := m.history["bd-001"].FailureClass; got != wantClass {
+				t.Errorf("FailureClass = %q, want %q", got, wantClass)
+			}
+			if got := m.history["bd-001"].Failures; got != tt.wantFailures {
+				t.Errorf("Failures = %d, want %d", got, tt.wantFailures)
+			}
+			status, attempts, inBackoff := m.GetBeadState("bd-001")
+			if status != tt.wantStatus || attempts != tt.wantAttempts || inBackoff != tt.wantInBackoff {
+				t.Errorf("GetBeadState() = %q, %d, %t; want %q, %d, %t",
+					status, attempts, inBackoff, tt.wantStatus, tt.wantAttempts, tt.wantInBackoff)
+			}
+			if tt.wantInBackoff {
+				why := m.SkipReason(Bead{ID: "bd-001"})
+				if !strings.Contains(why, "(last: "+string(wantClass)+")") {
+					t.Errorf("SkipReason() = %q, want the failure class", why)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordStopped(t *testing.T) {
+	m := New(config.Default(), newMockClient(), nil)
+	m.history["bd-001"] = &BeadHistory{ID: "bd-001", Status: HistoryWorking, Attempts: 2, Failures: 1}
+
+	m.RecordStopped("bd-001", errors.New("over budget"))
+
+	h := m.history["bd-001"]
+	if h.Status != HistorySkipped {
+		t.Errorf("expected status skipped, got %s", h.Status)
+	}
+	if h.LastError != "over budget" || h.LastAttempt.IsZero() {
+		t.Errorf("expected the error and attempt time recorded, got %+v", h)
+	}
+	if h.Attempts != 2 || h.Failures != 1 {
+		t.Errorf("expected attempts 2 and failures 1 unchanged, got %d and %d", h.Attempts, h.Failures)
+	}
+}
+
 func TestStats_Empty(t *testing.T) {
 	cfg := config.Default()
 	m := New(cfg, newMockClient(), nil)
@@ -1450,7 +1631,7 @@ func TestSelectBestTopLevel(t *testing.T) {
 		{ID: "task-002", Parent: "epic-002"},
 	}
 
-	best := selectBestTopLevel(topLevelItems, readyBeads, allBeads)
+	best := selectBestTopLevel(topLevelItems, readyBeads, allBeads, PriorityStrategy{}, RankContext{})
 	if best != "epic-002" {
 		t.Errorf("expected epic-002 (has ready work), got %s", best)
 	}
@@ -1465,12 +1646,63 @@ func TestSelectBestTopLevel_NoReadyWork(t *testing.T) {
 		{ID: "epic-001", IssueType: "epic"},
 	}
 
-	best := selectBestTopLevel(topLevelItems, readyBeads, allBeads)
+	best := selectBestTopLevel(topLevelItems, readyBeads, allBeads, PriorityStrategy{}, RankContext{})
 	if best != "" {
 		t.Errorf("expected empty string when no ready work, got %s", best)
 	}
 }
 
+func TestSelectBestTopLevel_Strategy(t *testing.T) {
+	topLevelItems := []Bead{
+		{ID: "epic-001", Priority: 1},
+		{ID: "epic-002", Priority: 2, DependentCount: 3},
+	}
+	readyBeads := []Bead{
+		{ID: "task-001", IssueType: "task"},
+		{ID: "task-002", IssueType: "task"},
+	}
+	allBeads := []Bead{
+		{ID: "epic-001", IssueType: "epic"},
+		{ID: "epic-002", IssueType: "epic"},
+		{ID: "task-001", Parent: "epic-001"},
+		{ID: "task-002", Parent: "epic-002"},
+	}
+
+	best := selectBestTopLevel(topLevelItems, readyBeads, allBeads, UnblockingStrategy{}, RankContext{})
+	if best != "epic-002" {
+		t.Errorf("expected epic-002 (unblocks the most beads), got %s", best)
+	}
+}
+
+func TestNextTopLevel_UsesSelectionStrategy(t *testing.T) {
+	mock := newMockClient()
+
+	mock.ReadyResponse = []brclient.Bead{
+		{ID: "task-a", Title: "Task A", Status: "open", Priority: 1, IssueType: "task"},
+		{ID: "task-b", Title: "Task B", Status: "open", Priority: 2, IssueType: "task", DependentCount: 3},
+	}
+	mock.ListResponse = []brclient.Bead{
+		{ID: "epic-001", Title: "Epic 1", Status: "open", IssueType: "epic", Priority: 1},
+		{ID: "task-a", Title: "Task A", Status: "open", Parent: "epic-001"},
+		{ID: "task-b", Title: "Task B", Status: "open", Parent: "epic-001"},
+	}
+
+	cfg := config.Default()
+	cfg.WorkQueue.Selection.Strategy = ModeUnblocking
+	m := New(cfg, mock, nil)
+
+	bead, _, err := m.NextTopLevel(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bead == nil || bead.ID != "task-b" {
+		t.Fatalf("expected task-b (unblocks the most beads), got %+v", bead)
+	}
+	if why := m.Explain("task-b"); why != "working through epic-001; unblocks 3 beads" {
+		t.Errorf("Explain() = %q", why)
+	}
+}
+
 func TestNextTopLevel_SelectsFromActiveTopLevel(t *testing.T) {
 	mock := newMockClient()
 
@@ -2118,3 +2350,73 @@ func TestHasEligibleReadyDescendants_RealisticMock(t *testing.T) {
 		t.Error("expected Show calls to populate parent fields, got none")
 	}
 }
+
+func TestUnclaim_RestoresHistory(t *testing.T) {
+	mock := newMockClient()
+	mock.ReadyResponse = []brclient.Bead{
+		{ID: "bd-001", Title: "Only", Status: "open", Priority: 1},
+	}
+
+	cfg := config.Default()
+
+	m := New(cfg, mock, nil)
+
+	bead, _, err := m.Next(context.Background())
+	if err != nil || bead == nil {
+		t.Fatalf("Next() = %v, %v", bead, err)
+	}
+	m.Unclaim(bead.ID)
+
+	if _, ok := m.History()["bd-001"]; ok {
+		t.Error("expected history entry removed for first selection")
+	}
+	if m.InFlight() != 0 {
+		t.Errorf("expected nothing in flight, got %d", m.InFlight())
+	}
+
+	// A bead with prior failures keeps its attempt count
+	m.SetHistory(map[string]*BeadHistory{
+		"bd-001": {ID: "bd-001", Status: HistoryFailed, Attempts: 1},
+	})
+
+	bead, _, _ = m.Next(context.Background())
+	if bead == nil {
+		t.Fatal("expected bead to be selectable")
+	}
+	m.Unclaim(bead.ID)
+
+	h := m.History()["bd-001"]
+	if h == nil || h.Attempts != 1 || h.Status != HistoryFailed {
+		t.Errorf("expected history restored to 1 failed attempt, got %+v", h)
+	}
+}
+
+func TestClaim(t *testing.T) {
+	mock := newMockClient()
+	m := New(config.Default(), mock, nil)
+	m.SetHistory(map[string]*BeadHistory{
+		"bd-001": {ID: "bd-001", Status: HistoryFailed, Attempts: 2},
+	})
+
+	if !m.Claim("bd-001", "asked for") {
+		t.Fatal("Claim() = false for an idle bead")
+	}
+	if why := m.Explain("bd-001"); why != "asked for" {
+		t.Errorf("Explain() = %q, want %q", why, "asked for")
+	}
+	h := m.History()["bd-001"]
+	if h.Status != HistoryWorking || h.Attempts != 3 {
+		t.Errorf("history after Claim() = %+v, want working attempt 3", h)
+	}
+	if m.Claim("bd-001", "again") {
+		t.Error("Claim() = true for a bead already in flight")
+	}
+
+	m.Unclaim("bd-001")
+	if why := m.Explain("bd-001"); why != "" {
+		t.Errorf("Explain() after Unclaim() = %q, want empty", why)
+	}
+	if h := m.History()["bd-001"]; h.Status != HistoryFailed || h.Attempts != 2 {
+		t.Errorf("history after Unclaim() = %+v, want restored", h)
+	}
+}
@@ -0,0 +1,286 @@
+package workqueue
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+// Selection modes for config.WorkQueueConfig.SelectionMode. Top-level mode
+// works through one epic or standalone bead at a time, ordering the items and
+// their beads with selection.strategy. The other modes choose among all
+// ready beads with a strategy.
+const (
+	ModeTopLevel   = "top-level"
+	ModeGlobal     = "global" // same as priority
+	ModePriority   = "priority"
+	ModeAging      = "aging"
+	ModeUnblocking = "unblocking"
+	ModeCheapest   = "cheapest"
+	ModeWeighted   = "weighted"
+)
+
+// lowestPriority is the largest priority number beads use (P4).
+const lowestPriority = 4
+
+// SelectionStrategy decides which eligible bead to work on next.
+type SelectionStrategy interface {
+	// Name identifies the strategy, as in selection_mode.
+	Name() string
+
+	// Rank sorts beads best first and explains why the first was chosen.
+	Rank(beads []Bead, rc RankContext) string
+}
+
+// RankContext is what a strategy may use besides the beads themselves.
+type RankContext struct {
+	Now   time.Time
+	Costs CostEstimator // nil when no run history is available
+}
+
+// CostEstimator predicts what a bead will cost, from earlier attempts.
+type CostEstimator interface {
+	// EstimateCost returns the expected cost in USD and what the estimate
+	// is based on. ok is false when there is nothing to go on.
+	EstimateCost(bead Bead) (usd float64, basis string, ok bool)
+}
+
+// NewStrategy returns the strategy for a selection mode. Top-level mode uses
+// cfg.Strategy, priority order by default; global mode orders beads by
+// priority. cfg.Strategy is an error in any other mode.
+func NewStrategy(mode string, cfg config.SelectionConfig) (SelectionStrategy, error) {
+	if mode == "" || mode == ModeTopLevel {
+		switch cfg.Strategy {
+		case "":
+			return PriorityStrategy{}, nil
+		case ModeTopLevel:
+			return nil, fmt.Errorf("workqueue.selection.strategy cannot be %q", ModeTopLevel)
+		}
+		strategy, err := newRankingStrategy(cfg.Strategy, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("workqueue.selection.strategy: %w", err)
+		}
+		return strategy, nil
+	}
+	if cfg.Strategy != "" {
+		return nil, fmt.Errorf("workqueue.selection.strategy only applies to selection mode %q; use selection_mode: %s instead",
+			ModeTopLevel, cfg.Strategy)
+	}
+	return newRankingStrategy(mode, cfg)
+}
+
+// newRankingStrategy returns the strategy named by mode, which is not
+// top-level.
+func newRankingStrategy(mode string, cfg config.SelectionConfig) (SelectionStrategy, error) {
+	switch mode {
+	case ModeGlobal, ModePriority:
+		return PriorityStrategy{}, nil
+	case ModeAging:
+		if cfg.AgingInterval <= 0 {
+			return nil, fmt.Errorf("selection mode %q needs a positive workqueue.selection.aging_interval", mode)
+		}
+		return AgingStrategy{Interval: cfg.AgingInterval}, nil
+	case ModeUnblocking:
+		return UnblockingStrategy{}, nil
+	case ModeCheapest:
+		return CheapestStrategy{}, nil
+	case ModeWeighted:
+		w := cfg.Weights
+		if w.Priority < 0 || w.Age < 0 || w.Unblocking < 0 || w.Cost < 0 {
+			return nil, fmt.Errorf("selection mode %q: weights must not be negative", mode)
+		}
+		if w.Priority+w.Age+w.Unblocking+w.Cost == 0 {
+			return nil, fmt.Errorf("selection mode %q needs at least one weight in workqueue.selection.weights", mode)
+		}
+		return WeightedStrategy{Weights: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown selection mode %q (want %s, %s, %s, %s, %s, %s or %s)", mode,
+			ModeTopLevel, ModeGlobal, ModePriority, ModeAging, ModeUnblocking, ModeCheapest, ModeWeighted)
+	}
+}
+
+// PriorityStrategy picks the highest-priority bead, oldest first.
+type PriorityStrategy struct{}
+
+// Name implements SelectionStrategy.
+func (PriorityStrategy) Name() string { return ModePriority }
+
+// Rank implements SelectionStrategy.
+func (PriorityStrategy) Rank(beads []Bead, _ RankContext) string {
+	sort.SliceStable(beads, func(i, j int) bool {
+		return byPriorityThenAge(beads[i], beads[j])
+	})
+	if len(beads) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("highest priority ready (P%d), oldest first", beads[0].Priority)
+}
+
+// AgingStrategy raises a bead's priority one level for every Interval it has
+// waited since it was created, so low-priority work is not starved.
+type AgingStrategy struct {
+	Interval time.Duration
+}
+
+// Name implements SelectionStrategy.
+func (AgingStrategy) Name() string { return ModeAging }
+
+// Rank implements SelectionStrategy.
+func (s AgingStrategy) Rank(beads []Bead, rc RankContext) string {
+	return rankByScore(beads, func(b Bead) (float64, string) {
+		effective := s.effectivePriority(b, rc.Now)
+		if effective == b.Priority {
+			return -float64(effective), fmt.Sprintf("priority P%d, not yet aged", b.Priority)
+		}
+		return -float64(effective), fmt.Sprintf("P%d aged to P%d after waiting %s",
+			b.Priority, effective, formatAge(rc.Now.Sub(b.CreatedAt)))
+	})
+}
+
+// effectivePriority returns the bead's priority after aging, never above P0.
+func (s AgingStrategy) effectivePriority(b Bead, now time.Time) int {
+	if b.CreatedAt.IsZero() || s.Interval <= 0 {
+		return b.Priority
+	}
+	levels := int(now.Sub(b.CreatedAt) / s.Interval)
+	return max(b.Priority-levels, 0)
+}
+
+// UnblockingStrategy picks the bead that the most other beads depend on.
+type UnblockingStrategy struct{}
+
+// Name implements SelectionStrategy.
+func (UnblockingStrategy) Name() string { return ModeUnblocking }
+
+// Rank implements SelectionStrategy.
+func (UnblockingStrategy) Rank(beads []Bead, _ RankContext) string {
+	return rankByScore(beads, func(b Bead) (float64, string) {
+		switch b.DependentCount {
+		case 0:
+			return 0, fmt.Sprintf("no ready bead unblocks others; highest priority (P%d)", b.Priority)
+		case 1:
+			return 1, "unblocks 1 bead"
+		default:
+			return float64(b.DependentCount), fmt.Sprintf("unblocks %d beads", b.DependentCount)
+		}
+	})
+}
+
+// CheapestStrategy picks the bead with the lowest expected cost according to
+// the run history. Beads with no estimate come after those with one.
+type CheapestStrategy struct{}
+
+// Name implements SelectionStrategy.
+func (CheapestStrategy) Name() string { return ModeCheapest }
+
+// Rank implements SelectionStrategy.
+func (CheapestStrategy) Rank(beads []Bead, rc RankContext) string {
+	return rankByScore(beads, func(b Bead) (float64, string) {
+		if rc.Costs != nil {
+			if usd, basis, ok := rc.Costs.EstimateCost(b); ok {
+				return -usd, fmt.Sprintf("cheapest expected cost $%.2f (%s)", usd, basis)
+			}
+		}
+		return math.Inf(-1), fmt.Sprintf("no cost history; highest priority (P%d)", b.Priority)
+	})
+}
+
+// WeightedStrategy combines priority, age, dependents and expected cost.
+// Each factor is scaled to 0-1 across the ready beads, then weighted.
+type WeightedStrategy struct {
+	Weights config.SelectionWeights
+}
+
+// Name implements SelectionStrategy.
+func (WeightedStrategy) Name() string { return ModeWeighted }
+
+// Rank implements SelectionStrategy.
+func (s WeightedStrategy) Rank(beads []Bead, rc RankContext) string {
+	// Find each factor's range across the candidates
+	var maxAge time.Duration
+	maxDependents := 0
+	costs := make(map[string]float64)
+	minCost, maxCost := math.Inf(1), math.Inf(-1)
+	for _, b := range beads {
+		if !b.CreatedAt.IsZero() {
+			maxAge = max(maxAge, rc.Now.Sub(b.CreatedAt))
+		}
+		maxDependents = max(maxDependents, b.DependentCount)
+		if rc.Costs != nil && s.Weights.Cost > 0 {
+			if usd, _, ok := rc.Costs.EstimateCost(b); ok {
+				costs[b.ID] = usd
+				minCost, maxCost = min(minCost, usd), max(maxCost, usd)
+			}
+		}
+	}
+
+	return rankByScore(beads, func(b Bead) (float64, string) {
+		priority := float64(lowestPriority-min(max(b.Priority, 0), lowestPriority)) / lowestPriority
+		var age, unblocking, cost float64
+		if maxAge > 0 && !b.CreatedAt.IsZero() {
+			age = float64(rc.Now.Sub(b.CreatedAt)) / float64(maxAge)
+		}
+		if maxDependents > 0 {
+			unblocking = float64(b.DependentCount) / float64(maxDependents)
+		}
+		if usd, ok := costs[b.ID]; ok {
+			cost = 1
+			if maxCost > minCost {
+				cost = (maxCost - usd) / (maxCost - minCost)
+			}
+		}
+
+		w := s.Weights
+		score := w.Priority*priority + w.Age*age + w.Unblocking*unblocking + w.Cost*cost
+		return score, fmt.Sprintf("weighted score %.2f (priority %.2f, age %.2f, unblocking %.2f, cost %.2f)",
+			score, priority, age, unblocking, cost)
+	})
+}
+
+// rankByScore sorts beads by score, highest first, breaking ties by priority
+// then age, and returns the explanation for the first bead.
+func rankByScore(beads []Bead, score func(Bead) (float64, string)) string {
+	type scored struct {
+		bead  Bead
+		score float64
+		why   string
+	}
+	ranked := make([]scored, len(beads))
+	for i, b := range beads {
+		v, why := score(b)
+		ranked[i] = scored{bead: b, score: v, why: why}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return byPriorityThenAge(ranked[i].bead, ranked[j].bead)
+	})
+	for i := range ranked {
+		beads[i] = ranked[i].bead
+	}
+	if len(ranked) == 0 {
+		return ""
+	}
+	return ranked[0].why
+}
+
+// byPriorityThenAge orders by priority (lower = higher priority), then by
+// creation time, oldest first.
+func byPriorityThenAge(a, b Bead) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// formatAge formats a wait in days or hours, e.g. "3d" or "5h".
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%dh", int(d/time.Hour))
+}
@@ -0,0 +1,182 @@
+package workqueue
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npratt/atari/internal/config"
+)
+
+// fixedCosts is a CostEstimator backed by a map.
+type fixedCosts map[string]float64
+
+func (f fixedCosts) EstimateCost(bead Bead) (float64, string, bool) {
+	usd, ok := f[bead.ID]
+	return usd, "fixed", ok
+}
+
+func beadIDs(beads []Bead) []string {
+	ids := make([]string, len(beads))
+	for i, b := range beads {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
+func TestNewStrategy(t *testing.T) {
+	defaults := config.Default().WorkQueue.Selection
+
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr string
+	}{
+		{"", ModePriority, ""},
+		{ModeTopLevel, ModePriority, ""},
+		{ModeGlobal, ModePriority, ""},
+		{ModeAging, ModeAging, ""},
+		{ModeUnblocking, ModeUnblocking, ""},
+		{ModeCheapest, ModeCheapest, ""},
+		{ModeWeighted, ModeWeighted, ""},
+		{"random", "", "unknown selection mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			s, err := NewStrategy(tt.mode, defaults)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NewStrategy() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewStrategy() error: %v", err)
+			}
+			if s.Name() != tt.want {
+				t.Errorf("Name() = %q, want %q", s.Name(), tt.want)
+			}
+		})
+	}
+
+	if _, err := NewStrategy(ModeWeighted, config.SelectionConfig{}); err == nil {
+		t.Error("expected error for weighted mode with no weights")
+	}
+	if _, err := NewStrategy(ModeAging, config.SelectionConfig{}); err == nil {
+		t.Error("expected error for aging mode with no interval")
+	}
+
+	// selection.strategy orders top-level mode and is rejected elsewhere
+	withStrategy := func(strategy string) config.SelectionConfig {
+		cfg := defaults
+		cfg.Strategy = strategy
+		return cfg
+	}
+	if s, err := NewStrategy(ModeTopLevel, withStrategy(ModeAging)); err != nil || s.Name() != ModeAging {
+		t.Errorf("NewStrategy(top-level, aging) = %v, %v; want aging", s, err)
+	}
+	for _, tt := range []struct {
+		mode, strategy, wantErr string
+	}{
+		{ModeTopLevel, ModeTopLevel, "cannot be"},
+		{ModeTopLevel, "random", "unknown selection mode"},
+		{ModeGlobal, ModeAging, "only applies to selection mode"},
+	} {
+		if _, err := NewStrategy(tt.mode, withStrategy(tt.strategy)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewStrategy(%s, strategy %s) error = %v, want %q", tt.mode, tt.strategy, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStrategies_Rank(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	beads := func() []Bead {
+		return []Bead{
+			{ID: "bd-p1-new", Priority: 1, CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "bd-p1-old", Priority: 1, CreatedAt: now.Add(-day)},
+			{ID: "bd-p3-stale", Priority: 3, CreatedAt: now.Add(-5 * day)},
+			{ID: "bd-p2-blocker", Priority: 2, CreatedAt: now.Add(-time.Hour), DependentCount: 4},
+		}
+	}
+	costs := fixedCosts{"bd-p1-new": 3, "bd-p1-old": 2, "bd-p3-stale": 0.5}
+
+	tests := []struct {
+		name      string
+		strategy  SelectionStrategy
+		costs     CostEstimator
+		wantFirst string
+		wantWhy   string
+	}{
+		{"priority", PriorityStrategy{}, nil, "bd-p1-old", "highest priority ready (P1), oldest first"},
+		{"aging", AgingStrategy{Interval: 36 * time.Hour}, nil, "bd-p3-stale", "P3 aged to P0 after waiting 5d"},
+		{"unblocking", UnblockingStrategy{}, nil, "bd-p2-blocker", "unblocks 4 beads"},
+		{"cheapest", CheapestStrategy{}, costs, "bd-p3-stale", "cheapest expected cost $0.50 (fixed)"},
+		{"cheapest without history", CheapestStrategy{}, nil, "bd-p1-old", "no cost history; highest priority (P1)"},
+		{"weighted on priority", WeightedStrategy{Weights: config.SelectionWeights{Priority: 1}}, nil, "bd-p1-old", "weighted score 0.75"},
+		{"weighted on unblocking", WeightedStrategy{Weights: config.SelectionWeights{Priority: 1, Unblocking: 2}}, nil, "bd-p2-blocker", "weighted score 2.50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := beads()
+			why := tt.strategy.Rank(ranked, RankContext{Now: now, Costs: tt.costs})
+			if ranked[0].ID != tt.wantFirst {
+				t.Errorf("ranked %v, want %s first", beadIDs(ranked), tt.wantFirst)
+			}
+			if !strings.HasPrefix(why, tt.wantWhy) {
+				t.Errorf("why = %q, want prefix %q", why, tt.wantWhy)
+			}
+			if len(ranked) != len(beads()) {
+				t.Errorf("Rank() changed the number of beads to %d", len(ranked))
+			}
+		})
+	}
+}
+
+func TestNext_UsesSelectionStrategy(t *testing.T) {
+	mock := newMockClient()
+	mock.ReadyResponse = []Bead{
+		{ID: "bd-001", Priority: 1, CreatedAt: time.Now()},
+		{ID: "bd-002", Priority: 3, CreatedAt: time.Now(), DependentCount: 2},
+	}
+	cfg := config.Default()
+	cfg.WorkQueue.SelectionMode = ModeUnblocking
+	m := New(cfg, mock, nil)
+
+	bead, reason, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	if reason != ReasonSuccess || bead == nil || bead.ID != "bd-002" {
+		t.Fatalf("Next() = %+v, %v; want bd-002", bead, reason)
+	}
+	if why := m.Explain("bd-002"); why != "unblocks 2 beads" {
+		t.Errorf("Explain() = %q", why)
+	}
+
+	m.Release("bd-002")
+	if why := m.Explain("bd-002"); why != "" {
+		t.Errorf("Explain() after Release() = %q, want empty", why)
+	}
+}
+
+func TestNext_CheapestUsesCostEstimator(t *testing.T) {
+	mock := newMockClient()
+	mock.ReadyResponse = []Bead{
+		{ID: "bd-001", Priority: 1, CreatedAt: time.Now()},
+		{ID: "bd-002", Priority: 2, CreatedAt: time.Now()},
+	}
+	cfg := config.Default()
+	cfg.WorkQueue.SelectionMode = ModeCheapest
+	m := New(cfg, mock, nil)
+	m.SetCostEstimator(fixedCosts{"bd-001": 4, "bd-002": 1})
+
+	bead, _, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	if bead == nil || bead.ID != "bd-002" {
+		t.Fatalf("Next() = %+v, want cheaper bd-002", bead)
+	}
+}
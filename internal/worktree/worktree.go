@@ -0,0 +1,140 @@
+// Package worktree manages the git worktrees that isolate parallel workers.
+//
+// Each worker gets a long-lived worktree under the configured worktrees
+// directory, checked out on its own branch (atari/<worker-name>). Worktrees
+// are reused across beads and restarts so commits made by a worker are never
+// discarded by atari. Beads run on their own branches, created from the
+// target branch by the branch-per-bead workflow, which pooled workers require.
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/git"
+)
+
+// BranchPrefix is the prefix used for branches created by atari.
+const BranchPrefix = "atari/"
+
+// beadsRedirectFile is the file br reads inside a worktree's .beads directory
+// to locate the main repository's bead database.
+const beadsRedirectFile = "redirect"
+
+// Manager creates and removes git worktrees for parallel workers.
+type Manager struct {
+	runner  exec.CommandRunner
+	repoDir string // main repository root
+	baseDir string // directory holding worker worktrees
+}
+
+// New creates a Manager for the repository at repoDir that places worktrees
+// under baseDir. Relative baseDir values are resolved against repoDir.
+func New(runner exec.CommandRunner, repoDir, baseDir string) *Manager {
+	if !filepath.IsAbs(baseDir) {
+		baseDir = filepath.Join(repoDir, baseDir)
+	}
+	return &Manager{
+		runner:  runner,
+		repoDir: repoDir,
+		baseDir: baseDir,
+	}
+}
+
+// RepoDir returns the main repository root.
+func (m *Manager) RepoDir() string {
+	return m.repoDir
+}
+
+// Path returns the worktree path for the named worker.
+func (m *Manager) Path(name string) string {
+	return filepath.Join(m.baseDir, name)
+}
+
+// Branch returns the branch checked out in the named worker's worktree.
+func Branch(name string) string {
+	return BranchPrefix + name
+}
+
+// Ensure creates the worktree for the named worker if it does not already
+// exist and returns its path. An existing worktree is reused as-is.
+// The worker branch is created from the main repository's HEAD on first use.
+func (m *Manager) Ensure(ctx context.Context, name string) (string, error) {
+	path := m.Path(name)
+
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		if err := m.linkBeads(path); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
+		return "", fmt.Errorf("create worktrees directory: %w", err)
+	}
+
+	// Drop registrations for worktree directories that were deleted by hand,
+	// otherwise git refuses to add a worktree at the same path.
+	if _, err := m.git(ctx, "worktree", "prune"); err != nil {
+		return "", fmt.Errorf("git worktree prune: %w", err)
+	}
+
+	branch := Branch(name)
+	args := []string{"worktree", "add", path, branch}
+	if !git.BranchExists(ctx, m.runner, m.repoDir, branch) {
+		args = []string{"worktree", "add", "-b", branch, path, "HEAD"}
+	}
+	if _, err := m.git(ctx, args...); err != nil {
+		return "", fmt.Errorf("git worktree add %s: %w", path, err)
+	}
+
+	if err := m.linkBeads(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Remove deletes the named worker's worktree. Uncommitted changes in the
+// worktree are discarded; the worker branch is kept.
+func (m *Manager) Remove(ctx context.Context, name string) error {
+	path := m.Path(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if _, err := m.git(ctx, "worktree", "remove", "--force", path); err != nil {
+		return fmt.Errorf("git worktree remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// linkBeads points the worktree's .beads directory at the main repository's
+// bead database so br commands run inside the worktree share one database.
+// It is a no-op when the main repository has no .beads directory.
+func (m *Manager) linkBeads(path string) error {
+	mainBeads := filepath.Join(m.repoDir, ".beads")
+	if info, err := os.Stat(mainBeads); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	rel, err := filepath.Rel(path, mainBeads)
+	if err != nil {
+		return fmt.Errorf("resolve beads directory: %w", err)
+	}
+
+	beadsDir := filepath.Join(path, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		return fmt.Errorf("create worktree beads directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, beadsRedirectFile), []byte(rel+"\n"), 0644); err != nil {
+		return fmt.Errorf("write beads redirect: %w", err)
+	}
+	return nil
+}
+
+// git runs a git command against the main repository.
+func (m *Manager) git(ctx context.Context, args ...string) ([]byte, error) {
+	return git.Run(ctx, m.runner, m.repoDir, args...)
+}
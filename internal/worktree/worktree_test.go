@@ -0,0 +1,227 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmdexec "github.com/npratt/atari/internal/exec"
+	"github.com/npratt/atari/internal/testutil"
+)
+
+func TestNew_ResolvesRelativeBaseDir(t *testing.T) {
+	m := New(testutil.NewMockRunner(), "/repo", ".atari/worktrees")
+
+	if got := m.Path("worker-1"); got != "/repo/.atari/worktrees/worker-1" {
+		t.Errorf("Path() = %q, want %q", got, "/repo/.atari/worktrees/worker-1")
+	}
+	if m.RepoDir() != "/repo" {
+		t.Errorf("RepoDir() = %q, want %q", m.RepoDir(), "/repo")
+	}
+}
+
+func TestNew_KeepsAbsoluteBaseDir(t *testing.T) {
+	m := New(testutil.NewMockRunner(), "/repo", "/tmp/wt")
+
+	if got := m.Path("worker-2"); got != "/tmp/wt/worker-2" {
+		t.Errorf("Path() = %q, want %q", got, "/tmp/wt/worker-2")
+	}
+}
+
+func TestBranch(t *testing.T) {
+	if got := Branch("worker-1"); got != "atari/worker-1" {
+		t.Errorf("Branch() = %q, want %q", got, "atari/worker-1")
+	}
+}
+
+func TestEnsure_CreatesBranchWhenMissing(t *testing.T) {
+	repo := t.TempDir()
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		if len(args) > 2 && args[2] == "rev-parse" {
+			return nil, errors.New("exit status 1"), true
+		}
+		return []byte{}, nil, true
+	}
+
+	m := New(runner, repo, ".atari/worktrees")
+	path, err := m.Ensure(context.Background(), "worker-1")
+	if err != nil {
+		t.Fatalf("Ensure() error: %v", err)
+	}
+	if path != m.Path("worker-1") {
+		t.Errorf("Ensure() path = %q, want %q", path, m.Path("worker-1"))
+	}
+
+	var addCall string
+	for _, call := range runner.GetCalls() {
+		joined := strings.Join(call.Args, " ")
+		if strings.Contains(joined, "worktree add") {
+			addCall = joined
+		}
+	}
+	want := "-C " + repo + " worktree add -b atari/worker-1 " + path + " HEAD"
+	if addCall != want {
+		t.Errorf("worktree add args = %q, want %q", addCall, want)
+	}
+}
+
+func TestEnsure_ReusesExistingBranch(t *testing.T) {
+	repo := t.TempDir()
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		return []byte{}, nil, true
+	}
+
+	m := New(runner, repo, ".atari/worktrees")
+	path, err := m.Ensure(context.Background(), "worker-2")
+	if err != nil {
+		t.Fatalf("Ensure() error: %v", err)
+	}
+
+	var addCall string
+	for _, call := range runner.GetCalls() {
+		joined := strings.Join(call.Args, " ")
+		if strings.Contains(joined, "worktree add") {
+			addCall = joined
+		}
+	}
+	want := "-C " + repo + " worktree add " + path + " atari/worker-2"
+	if addCall != want {
+		t.Errorf("worktree add args = %q, want %q", addCall, want)
+	}
+}
+
+func TestEnsure_SkipsExistingWorktree(t *testing.T) {
+	repo := t.TempDir()
+	runner := testutil.NewMockRunner()
+
+	m := New(runner, repo, ".atari/worktrees")
+	if err := os.MkdirAll(m.Path("worker-1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(m.Path("worker-1"), ".git"), []byte("gitdir: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Ensure(context.Background(), "worker-1"); err != nil {
+		t.Fatalf("Ensure() error: %v", err)
+	}
+	if calls := runner.GetCalls(); len(calls) != 0 {
+		t.Errorf("expected no git calls for existing worktree, got %v", calls)
+	}
+}
+
+func TestEnsure_AddError(t *testing.T) {
+	repo := t.TempDir()
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		if len(args) > 3 && args[2] == "worktree" && args[3] == "add" {
+			return nil, errors.New("fatal: invalid reference"), true
+		}
+		return []byte{}, nil, true
+	}
+
+	m := New(runner, repo, ".atari/worktrees")
+	_, err := m.Ensure(context.Background(), "worker-1")
+	if err == nil {
+		t.Fatal("expected error from failed worktree add")
+	}
+	if !strings.Contains(err.Error(), "git worktree add") {
+		t.Errorf("error = %q, want it to mention git worktree add", err)
+	}
+}
+
+func TestEnsure_WritesBeadsRedirect(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, ".beads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewMockRunner()
+	runner.DynamicResponse = func(ctx context.Context, name string, args []string) ([]byte, error, bool) {
+		return []byte{}, nil, true
+	}
+
+	m := New(runner, repo, ".atari/worktrees")
+	path, err := m.Ensure(context.Background(), "worker-1")
+	if err != nil {
+		t.Fatalf("Ensure() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, ".beads", "redirect"))
+	if err != nil {
+		t.Fatalf("read redirect: %v", err)
+	}
+	target := filepath.Join(path, strings.TrimSpace(string(data)))
+	if filepath.Clean(target) != filepath.Join(repo, ".beads") {
+		t.Errorf("redirect resolves to %q, want %q", target, filepath.Join(repo, ".beads"))
+	}
+}
+
+func TestRemove_MissingWorktreeIsNoop(t *testing.T) {
+	runner := testutil.NewMockRunner()
+	m := New(runner, t.TempDir(), ".atari/worktrees")
+
+	if err := m.Remove(context.Background(), "worker-9"); err != nil {
+		t.Errorf("Remove() error: %v", err)
+	}
+	if calls := runner.GetCalls(); len(calls) != 0 {
+		t.Errorf("expected no git calls, got %v", calls)
+	}
+}
+
+func TestEnsure_RealGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitRun("init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", "README")
+	gitRun("commit", "-q", "-m", "initial")
+
+	m := New(cmdexec.NewExecRunner(), repo, ".atari/worktrees")
+	ctx := context.Background()
+
+	path, err := m.Ensure(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("Ensure() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "README")); err != nil {
+		t.Errorf("expected README checked out in worktree: %v", err)
+	}
+
+	// Second call reuses the worktree
+	if _, err := m.Ensure(ctx, "worker-1"); err != nil {
+		t.Fatalf("second Ensure() error: %v", err)
+	}
+
+	if err := m.Remove(ctx, "worker-1"); err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected worktree removed, stat err = %v", err)
+	}
+
+	// Recreating after removal reuses the existing worker branch
+	if _, err := m.Ensure(ctx, "worker-1"); err != nil {
+		t.Fatalf("Ensure() after Remove error: %v", err)
+	}
+}